@@ -8,6 +8,8 @@ import (
 
 	"github.com/cheggaaa/pb/v3"
 	"github.com/nathanhack/avgstd"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
 	mat "github.com/nathanhack/sparsemat"
 	"github.com/nathanhack/threadpool"
@@ -18,6 +20,7 @@ type Stats struct {
 	ChannelCodewordError avgstd.AvgStd // probability of a bit error after channel errors are fixed
 	ChannelMessageError  avgstd.AvgStd // probability of a bit error after channel errors are fixed
 	ChannelParityError   avgstd.AvgStd // probability of a bit error after channel errors are fixed
+	Iterations           avgstd.AvgStd // number of iterations the correction used
 }
 
 func (s Stats) String() string {
@@ -50,6 +53,20 @@ type BPSKChannel func(codeword mat2.Vector) (channelInducedCodeword mat2.Vector)
 type BPSKChannelCorrection func(originalCodeword, channelInducedCodeword mat2.Vector) (fixedChannelInducedCodeword mat2.Vector)
 type BPSKChannelMetrics func(originalMessage mat.SparseVector, originalCodeword, fixedChannelInducedCodeword mat2.Vector) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64)
 
+// BPSKChannelIterativeCorrection is a BPSKChannelCorrection that also returns the number of iterations it used
+type BPSKChannelIterativeCorrection func(originalCodeword, channelInducedCodeword mat2.Vector) (fixedChannelInducedCodeword mat2.Vector, iterations int)
+
+// specific to symbol channels, nonbinary codes (Reed-Solomon) where each symbol is an int
+type SymbolMessageConstructor func(trial int) (message []int)
+type SymbolChannelEncoder func(message []int) (codeword []int)
+type SymbolChannel func(codeword []int) (channelInducedCodeword []int, erasures []int)
+type SymbolChannelCorrection func(channelInducedCodeword []int, erasures []int) (fixedChannelInducedCodeword []int, iterations int)
+type SymbolChannelMetrics func(originalMessage, originalCodeword, fixedChannelInducedCodeword []int) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64)
+
+// generic to any channel and linearblock.Decoder
+type BinaryChannel func(codeword mat.SparseVector) (received linearblock.Received)
+type DecoderConstructor func() linearblock.Decoder
+
 func BenchmarkBSC(ctx context.Context,
 	trials int, threads int,
 	createMessage BinaryMessageConstructor,
@@ -232,6 +249,48 @@ func BenchmarkBPSKContinueStats(ctx context.Context,
 	checkpoints Checkpoints,
 	previousStats Stats,
 	showProgress bool) Stats {
+	repair := func(originalCodeword, channelInducedCodeword mat2.Vector) (mat2.Vector, int) {
+		return codewordRepair(originalCodeword, channelInducedCodeword), 0
+	}
+	return benchmarkBPSK(ctx, trials, threads, createMessage, encode, channel, repair, false, metrics, checkpoints, previousStats, showProgress)
+}
+
+// BenchmarkBPSKIterative is BenchmarkBPSK for iterative decoders, the Stats also have the number of iterations.
+func BenchmarkBPSKIterative(ctx context.Context,
+	trials int, threads int,
+	createMessage BinaryMessageConstructor,
+	encode BPSKChannelEncoder,
+	channel BPSKChannel,
+	codewordRepair BPSKChannelIterativeCorrection,
+	metrics BPSKChannelMetrics,
+	checkpoints Checkpoints, showProgress bool) Stats {
+	return BenchmarkBPSKIterativeContinueStats(ctx, trials, threads, createMessage, encode, channel, codewordRepair, metrics, checkpoints, Stats{}, showProgress)
+}
+
+func BenchmarkBPSKIterativeContinueStats(ctx context.Context,
+	trials int, threads int,
+	createMessage BinaryMessageConstructor,
+	encode BPSKChannelEncoder,
+	channel BPSKChannel,
+	codewordRepair BPSKChannelIterativeCorrection,
+	metrics BPSKChannelMetrics,
+	checkpoints Checkpoints,
+	previousStats Stats,
+	showProgress bool) Stats {
+	return benchmarkBPSK(ctx, trials, threads, createMessage, encode, channel, codewordRepair, true, metrics, checkpoints, previousStats, showProgress)
+}
+
+func benchmarkBPSK(ctx context.Context,
+	trials int, threads int,
+	createMessage BinaryMessageConstructor,
+	encode BPSKChannelEncoder,
+	channel BPSKChannel,
+	codewordRepair BPSKChannelIterativeCorrection,
+	countIterations bool,
+	metrics BPSKChannelMetrics,
+	checkpoints Checkpoints,
+	previousStats Stats,
+	showProgress bool) Stats {
 	trialsToRun := trials - int(previousStats.ChannelCodewordError.Count)
 	if trialsToRun <= 0 {
 		return previousStats
@@ -258,7 +317,7 @@ func BenchmarkBPSKContinueStats(ctx context.Context,
 		channelInducedCodeword := channel(codeword)
 
 		// repair the codeword (if possible)
-		repaired := codewordRepair(codeword, channelInducedCodeword)
+		repaired, iterations := codewordRepair(codeword, channelInducedCodeword)
 
 		// get metrics
 		percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors := metrics(message, codeword, repaired)
@@ -267,6 +326,9 @@ func BenchmarkBPSKContinueStats(ctx context.Context,
 		previousStats.ChannelCodewordError.Update(percentFixedCodewordErrors)
 		previousStats.ChannelMessageError.Update(percentFixedMessageErrors)
 		previousStats.ChannelParityError.Update(percentFixedParityErrors)
+		if countIterations {
+			previousStats.Iterations.Update(float64(iterations))
+		}
 
 		if checkpoints != nil {
 			checkpoints(previousStats) //give them the updated checkpoint
@@ -289,6 +351,191 @@ func BenchmarkBPSKContinueStats(ctx context.Context,
 	return previousStats
 }
 
+// BenchmarkSymbols simulates sending random messages of symbols through a symbol channel, the stats are symbol
+// error probabilities instead of bit error probabilities. The channel returns the indices of the erased symbols
+// along with the channel induced codeword.
+func BenchmarkSymbols(ctx context.Context,
+	trials int, threads int,
+	createMessage SymbolMessageConstructor,
+	encode SymbolChannelEncoder,
+	channel SymbolChannel,
+	codewordRepair SymbolChannelCorrection,
+	metrics SymbolChannelMetrics,
+	checkpoints Checkpoints,
+	showProgress bool) Stats {
+	return BenchmarkSymbolsContinueStats(ctx, trials, threads, createMessage, encode, channel, codewordRepair, metrics, checkpoints, Stats{}, showProgress)
+}
+
+func BenchmarkSymbolsContinueStats(ctx context.Context,
+	trials int, threads int,
+	createMessage SymbolMessageConstructor,
+	encode SymbolChannelEncoder,
+	channel SymbolChannel,
+	codewordRepair SymbolChannelCorrection,
+	metrics SymbolChannelMetrics,
+	checkpoints Checkpoints,
+	previousStats Stats,
+	showProgress bool) Stats {
+	trialsToRun := trials - int(previousStats.ChannelCodewordError.Count)
+	if trialsToRun <= 0 {
+		return previousStats
+	}
+
+	var bar *pb.ProgressBar
+	if showProgress {
+		bar = pb.StartNew(trialsToRun)
+	}
+
+	pool := threadpool.New(ctx, threads)
+	statsMux := sync.Mutex{}
+
+	trial := func(i int) {
+		if showProgress {
+			bar.Increment()
+		}
+		//we create a random message
+		message := createMessage(i)
+
+		// encode to get our codeword
+		codeword := encode(message)
+
+		// send through the channel to get channel induced errors and erasures
+		channelInducedCodeword, erasures := channel(codeword)
+
+		// repair the codeword (if possible)
+		repaired, iterations := codewordRepair(channelInducedCodeword, erasures)
+
+		// get metrics
+		percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors := metrics(message, codeword, repaired)
+
+		statsMux.Lock()
+		previousStats.ChannelCodewordError.Update(percentFixedCodewordErrors)
+		previousStats.ChannelMessageError.Update(percentFixedMessageErrors)
+		previousStats.ChannelParityError.Update(percentFixedParityErrors)
+		previousStats.Iterations.Update(float64(iterations))
+		if checkpoints != nil {
+			checkpoints(previousStats) //give them the updated checkpoint
+		}
+		statsMux.Unlock()
+	}
+
+	for i := int(previousStats.ChannelCodewordError.Count); i < trials; i++ {
+		tmp := i
+		pool.Add(func() { trial(tmp) })
+	}
+	pool.Wait()
+	if showProgress {
+		bar.Finish()
+	}
+
+	if checkpoints != nil {
+		checkpoints(previousStats) //give them the updated checkpoint
+	}
+
+	return previousStats
+}
+
+// BenchmarkDecoder simulates sending random messages encoded with the block through the channel and decoding
+// them with a linearblock.Decoder. Since decoders may keep state a decoder is only used by one thread
+// at a time, newDecoder is called whenever another one is needed.
+func BenchmarkDecoder(ctx context.Context,
+	block *linearblock.LinearBlock,
+	trials int, threads int,
+	createMessage BinaryMessageConstructor,
+	channel BinaryChannel,
+	newDecoder DecoderConstructor,
+	checkpoints Checkpoints,
+	showProgress bool) Stats {
+	return BenchmarkDecoderContinueStats(ctx, block, trials, threads, createMessage, channel, newDecoder, checkpoints, Stats{}, showProgress)
+}
+
+func BenchmarkDecoderContinueStats(ctx context.Context,
+	block *linearblock.LinearBlock,
+	trials int, threads int,
+	createMessage BinaryMessageConstructor,
+	channel BinaryChannel,
+	newDecoder DecoderConstructor,
+	checkpoints Checkpoints,
+	previousStats Stats,
+	showProgress bool) Stats {
+	trialsToRun := trials - int(previousStats.ChannelCodewordError.Count)
+	if trialsToRun <= 0 {
+		return previousStats
+	}
+
+	var bar *pb.ProgressBar
+	if showProgress {
+		bar = pb.StartNew(trialsToRun)
+	}
+	pool := threadpool.New(ctx, threads)
+	statsMux := sync.Mutex{}
+	decoders := sync.Pool{New: func() any { return newDecoder() }}
+
+	trial := func(i int) {
+		if showProgress {
+			bar.Increment()
+		}
+		//we create a random message
+		message := createMessage(i)
+
+		// encode to get our codeword
+		codeword := block.Encode(message)
+
+		// send through the channel to get channel induced errors
+		received := channel(codeword)
+
+		// repair the codeword (if possible)
+		decoder := decoders.Get().(linearblock.Decoder)
+		result := decoder.Decode(ctx, received)
+		decoders.Put(decoder)
+		if result.Stop == messagepassing.Canceled {
+			//the trial was cut short so it isn't counted
+			return
+		}
+
+		// get metrics
+		codewordErrors, messageErrors := DecodeResultErrors(block, message, codeword, result)
+		parityErrors := codewordErrors - messageErrors
+
+		statsMux.Lock()
+		previousStats.ChannelCodewordError.Update(float64(codewordErrors) / float64(block.CodewordLength()))
+		previousStats.ChannelMessageError.Update(float64(messageErrors) / float64(block.MessageLength()))
+		previousStats.ChannelParityError.Update(float64(parityErrors) / float64(block.ParitySymbols()))
+		previousStats.Iterations.Update(float64(result.Iterations))
+
+		if checkpoints != nil {
+			checkpoints(previousStats) //give them the updated checkpoint
+		}
+		statsMux.Unlock()
+	}
+
+	for i := int(previousStats.ChannelCodewordError.Count); i < trials; i++ {
+		tmp := i
+		pool.Add(func() { trial(tmp) })
+	}
+	pool.Wait()
+	if showProgress {
+		bar.Finish()
+	}
+
+	if checkpoints != nil {
+		checkpoints(previousStats) //give them the updated checkpoint
+	}
+
+	return previousStats
+}
+
+// DecodeResultErrors counts the codeword and message bits the result got wrong,
+// when the result has unresolved erasures they're counted as errors.
+func DecodeResultErrors(block *linearblock.LinearBlock, message, codeword mat.SparseVector, result linearblock.DecodeResult) (codewordErrors, messageErrors int) {
+	if result.Erasures == nil {
+		return codeword.HammingDistance(result.Codeword), message.HammingDistance(result.Message)
+	}
+
+	return HammingDistanceErasuresToBits(result.Erasures, codeword),
+		HammingDistanceErasuresToBits(block.DecodeBE(result.Erasures), message)
+}
+
 // HammingDistanceErasuresToBits calculates number of bits different.
 // If a and b are different sizes it assumes they are
 // both aligned with the zero index (the difference is at the end)
@@ -309,6 +556,26 @@ func HammingDistanceErasuresToBits(a []bec.ErasureBit, b mat.SparseVector) int {
 	return max - min + count
 }
 
+// HammingDistanceSymbols calculates number of symbols different.
+// If a and b are different sizes it assumes they are
+// both aligned with the zero index (the difference is at the end)
+func HammingDistanceSymbols(a, b []int) int {
+	min := len(a)
+	max := len(b)
+	if min > max {
+		min = len(b)
+		max = len(a)
+	}
+
+	count := 0
+	for i := 0; i < min; i++ {
+		if a[i] != b[i] {
+			count++
+		}
+	}
+	return max - min + count
+}
+
 // BitsToBPSK converts a [0,1] matrix to a [-1,1] matrix
 func BitsToBPSK(a mat.SparseVector) mat2.Vector {
 	output := mat2.NewVecDense(a.Len(), nil)
@@ -337,6 +604,17 @@ func BPSKToBits(a mat2.Vector, boundary float64) mat.SparseVector {
 	return result
 }
 
+// BPSKToLLR converts a received BPSK vector into log likelihood ratios ln(P(0|y)/P(1|y))
+// assuming the same E_b/N_0 used by RandomNoiseBPSK. Since a 1 is sent as +1
+// a positive value received will have a negative LLR.
+func BPSKToLLR(a mat2.Vector, E_bPerN_0 float64) mat2.Vector {
+	//using  σ^2 = N_0/2 and E_b=1
+	// we get  LLR = -2y/σ^2 = -4*E_bPerN_0*y
+	result := mat2.NewVecDense(a.Len(), nil)
+	result.ScaleVec(-4*E_bPerN_0, a)
+	return result
+}
+
 // HammingDistanceBPSK calculates number of bits different.
 // Assumes >=0 is 1 and <0 is 0
 // If a and b are different sizes it assumes they are
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"runtime"
 
+	"github.com/nathanhack/ecc/linearblock"
 	"github.com/nathanhack/ecc/linearblock/hamming"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bec/iterative"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bitflipping/harddecision"
+	"github.com/nathanhack/ecc/linearblock/reedsolomon"
 	mat "github.com/nathanhack/sparsemat"
 	mat2 "gonum.org/v1/gonum/mat"
 )
@@ -169,3 +171,61 @@ func ExampleBenchmarkBPSK() {
 	//Output:
 	// Bit Error Probability : {Codeword:0.00(+/-0.04), Message:0.00(+/-0.05), Parity:0.00(+/-0.05)}
 }
+
+func ExampleBenchmarkDecoder() {
+	linearBlock, _ := hamming.New(context.Background(), 3, 0)
+
+	createMessage := func(trial int) mat.SparseVector {
+		return RandomMessage(linearBlock.MessageLength())
+	}
+
+	channel := func(codeword mat.SparseVector) (received linearblock.Received) {
+		//since hamming can fix only one bit wrong we'll just flip one bit per codeword
+		return linearblock.Received{Bits: RandomFlipBitCount(codeword, 1)}
+	}
+
+	// any linearblock.Decoder works, bit flipping algs keep state so each thread gets its own
+	newDecoder := func() linearblock.Decoder {
+		return &harddecision.Decoder{
+			Block:   linearBlock,
+			Alg:     &harddecision.Gallager{H: linearBlock.H},
+			MaxIter: 20,
+		}
+	}
+
+	stats := BenchmarkDecoder(context.Background(), linearBlock, 1000, runtime.NumCPU(), createMessage, channel, newDecoder, nil, false)
+
+	fmt.Println("Bit Error Probability :", stats)
+	//Output:
+	// Bit Error Probability : {Codeword:0.00(+/-0.00), Message:0.00(+/-0.00), Parity:0.00(+/-0.00)}
+}
+
+func ExampleBenchmarkSymbols() {
+	rs, _ := reedsolomon.New(8, 255, 223)
+
+	createMessage := func(trial int) []int {
+		return RandomSymbols(rs.MessageLength(), rs.SymbolSize())
+	}
+
+	channel := func(codeword []int) ([]int, []int) {
+		//RS(255,223) corrects any e errors and f erasures with 2e+f <= 32
+		return RandomSymbolErrorCount(codeword, rs.SymbolSize(), 10, 12)
+	}
+
+	codewordRepair := func(channelInducedCodeword []int, erasures []int) ([]int, int) {
+		fixed, _, _ := rs.Decode(channelInducedCodeword, erasures)
+		return fixed, 0
+	}
+
+	metrics := func(originalMessage, originalCodeword, fixedChannelInducedCodeword []int) (float64, float64, float64) {
+		return float64(HammingDistanceSymbols(originalCodeword, fixedChannelInducedCodeword)) / float64(rs.CodewordLength()),
+			float64(HammingDistanceSymbols(originalMessage, rs.Message(fixedChannelInducedCodeword))) / float64(rs.MessageLength()),
+			float64(HammingDistanceSymbols(originalCodeword[rs.MessageLength():], fixedChannelInducedCodeword[rs.MessageLength():])) / float64(rs.ParitySymbols())
+	}
+
+	stats := BenchmarkSymbols(context.Background(), 1000, runtime.NumCPU(), createMessage, rs.Encode, channel, codewordRepair, metrics, nil, false)
+
+	fmt.Println("Symbol Error Probability :", stats)
+	//Output:
+	// Symbol Error Probability : {Codeword:0.00(+/-0.00), Message:0.00(+/-0.00), Parity:0.00(+/-0.00)}
+}
@@ -1,6 +1,7 @@
 package benchmarking
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 
@@ -70,14 +71,53 @@ func RandomEraseCount(codeword []bec.ErasureBit, numberOfBitsToFlip int) []bec.E
 	return output
 }
 
+// RandomSymbols creates a random message of length len where each symbol has symbolSize bits.
+func RandomSymbols(len int, symbolSize int) []int {
+	message := make([]int, len)
+	for i := range message {
+		message[i] = rand.Intn(1 << symbolSize)
+	}
+	return message
+}
+
+// RandomSymbolErrorCount creates a copy of the codeword with numberOfErrors symbols changed to a different
+// random value and numberOfErasures other symbols erased. Erased symbols are given a random value and their
+// indices are returned as erasures.
+func RandomSymbolErrorCount(codeword []int, symbolSize int, numberOfErrors, numberOfErasures int) (output []int, erasures []int) {
+	if numberOfErrors+numberOfErasures > len(codeword) {
+		panic(fmt.Sprintf("numberOfErrors+numberOfErasures <= %v required but found %v", len(codeword), numberOfErrors+numberOfErasures))
+	}
+	output = make([]int, len(codeword))
+	copy(output, codeword)
+
+	indices := rand.Perm(len(codeword))
+	for _, i := range indices[:numberOfErrors] {
+		output[i] ^= 1 + rand.Intn(1<<symbolSize-1)
+	}
+	erasures = indices[numberOfErrors : numberOfErrors+numberOfErasures]
+	for _, i := range erasures {
+		output[i] = rand.Intn(1 << symbolSize)
+	}
+	return output, erasures
+}
+
 // RandomNoiseBPSK creates a randomizes version of the bpsk vector using the E_b/N_0 passed in
 func RandomNoiseBPSK(bpsk mat2.Vector, E_bPerN_0 float64) mat2.Vector {
+	return randomNoiseBPSK(rand.NormFloat64, bpsk, E_bPerN_0)
+}
+
+// RandomNoiseBPSKFrom is RandomNoiseBPSK drawing the noise from random, so a seeded source reproduces it
+func RandomNoiseBPSKFrom(random *rand.Rand, bpsk mat2.Vector, E_bPerN_0 float64) mat2.Vector {
+	return randomNoiseBPSK(random.NormFloat64, bpsk, E_bPerN_0)
+}
+
+func randomNoiseBPSK(normFloat64 func() float64, bpsk mat2.Vector, E_bPerN_0 float64) mat2.Vector {
 	//using  σ^2 = N_0/2 and E_b=1
 	// we get  σ = sqrt(1/(2*E_bPerN_0))
 	σ := math.Sqrt(1 / (2 * E_bPerN_0))
 	result := mat2.NewVecDense(bpsk.Len(), nil)
 	for i := 0; i < bpsk.Len(); i++ {
-		result.SetVec(i, rand.NormFloat64()*σ)
+		result.SetVec(i, normFloat64()*σ)
 	}
 	result.AddVec(result, bpsk)
 	return result
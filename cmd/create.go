@@ -1,10 +1,21 @@
 package cmd
 
 import (
+	"github.com/nathanhack/ecc/cmd/internal/create/bch"
+	"github.com/nathanhack/ecc/cmd/internal/create/convolutional"
+	"github.com/nathanhack/ecc/cmd/internal/create/finitegeometry"
 	"github.com/nathanhack/ecc/cmd/internal/create/gallager"
 	"github.com/nathanhack/ecc/cmd/internal/create/gce"
+	"github.com/nathanhack/ecc/cmd/internal/create/golay"
 	"github.com/nathanhack/ecc/cmd/internal/create/hamming"
+	"github.com/nathanhack/ecc/cmd/internal/create/ira"
+	"github.com/nathanhack/ecc/cmd/internal/create/peg"
+	"github.com/nathanhack/ecc/cmd/internal/create/polar"
+	"github.com/nathanhack/ecc/cmd/internal/create/protograph"
+	"github.com/nathanhack/ecc/cmd/internal/create/qc"
 	"github.com/nathanhack/ecc/cmd/internal/create/rcj"
+	"github.com/nathanhack/ecc/cmd/internal/create/reedmuller"
+	"github.com/nathanhack/ecc/cmd/internal/create/turbo"
 
 	"github.com/spf13/cobra"
 )
@@ -52,6 +63,60 @@ var createGCECmd = &cobra.Command{
 	Run:   gce.GCERun,
 }
 
+// createEGCmd represents the eg command
+var createEGCmd = &cobra.Command{
+	Use:   "eg OUTPUT_LDPC_JSON",
+	Short: "Creates a new Euclidean geometry (EG) LDPC based ECC",
+	Long:  `Creates a new type-I cyclic EG-LDPC based ECC from the lines and points of EG(m,2^s), it has girth >=6 and suits one-step majority-logic decoding.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   finitegeometry.EGRun,
+}
+
+// createPGCmd represents the pg command
+var createPGCmd = &cobra.Command{
+	Use:   "pg OUTPUT_LDPC_JSON",
+	Short: "Creates a new projective geometry (PG) LDPC based ECC",
+	Long:  `Creates a new type-I cyclic PG-LDPC based ECC from the lines and points of PG(m,2^s), it has girth >=6 and suits one-step majority-logic decoding.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   finitegeometry.PGRun,
+}
+
+// createPEGCmd represents the peg command
+var createPEGCmd = &cobra.Command{
+	Use:   "peg OUTPUT_LDPC_JSON",
+	Short: "Creates a new progressive edge growth (PEG) LDPC based ECC",
+	Long:  `Creates a new LDPC based ECC by progressive edge growth, each edge is placed to make the local girth as large as possible. The variable and check node degree distributions are given as degree=fraction pairs and the ACE option avoids short cycles made mostly of low degree variable nodes.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   peg.PEGRun,
+}
+
+// createQCCmd represents the qc command
+var createQCCmd = &cobra.Command{
+	Use:   "qc OUTPUT_LDPC_JSON",
+	Short: "Creates a new quasi-cyclic (QC) LDPC based ECC",
+	Long:  `Creates a new QC-LDPC based ECC from an exponent matrix lifted by Z, every exponent is a ZxZ circulant (-1 is the zero block). Either the exponent matrix is given or its shifts are searched for a base matrix (all ones by default) to reach the girth. The exponent matrix is saved and every linearblock tool expands it when loading.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   qc.QCRun,
+}
+
+// createIRACmd represents the ira command
+var createIRACmd = &cobra.Command{
+	Use:   "ira OUTPUT_LDPC_JSON",
+	Short: "Creates a new (irregular) repeat-accumulate LDPC based ECC",
+	Long:  `Creates a new systematic repeat-accumulate (RA) or irregular repeat-accumulate (IRA) LDPC based ECC. Every message bit is repeated as given by the degree distribution, the repetitions are interleaved, combined into checks and accumulated so H=[H1|H2] with H2 dual-diagonal. A single repetition degree gives a regular RA code. Only H is stored, the tools encode with the accumulator instead of a generator matrix.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   ira.IRARun,
+}
+
+// createProtographCmd represents the protograph command
+var createProtographCmd = &cobra.Command{
+	Use:   "protograph OUTPUT_LDPC_JSON",
+	Short: "Creates a new protograph based LDPC ECC",
+	Long:  `Creates a new LDPC based ECC by lifting a protograph, a small base graph where entry (i,j) is the number of edges between check i and variable j. It's optionally lifted first with random permutations (needed to separate parallel edges) and then with circulants searched to reach the girth, saved like a QC-LDPC, or with random permutations. The AR4JA protograph is used when no protograph is given. Punctured variables are kept in the code, their columns are logged.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   protograph.ProtographRun,
+}
+
 // createHammingCmd represents the Hamming command
 var createHammingCmd = &cobra.Command{
 	Use:     "hamming OUTPUT_HAMMING_JSON",
@@ -62,6 +127,62 @@ var createHammingCmd = &cobra.Command{
 	Run:     hamming.HammingRun,
 }
 
+// createBCHCmd represents the BCH command
+var createBCHCmd = &cobra.Command{
+	Use:   "bch OUTPUT_BCH_JSON",
+	Short: "Creates a new binary BCH code based ECC",
+	Long:  `Creates a new narrow-sense primitive binary BCH code based ECC with codeword size 2^m-1 correcting ⌊(distance-1)/2⌋ errors.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   bch.BCHRun,
+}
+
+// createReedMullerCmd represents the Reed-Muller command
+var createReedMullerCmd = &cobra.Command{
+	Use:     "reedmuller OUTPUT_REEDMULLER_JSON",
+	Aliases: []string{"rm"},
+	Short:   "Creates a new Reed-Muller code based ECC",
+	Long:    `Creates a new Reed-Muller RM(r,m) code based ECC with codeword size 2^m and minimum distance 2^(m-r).`,
+	Args:    cobra.ExactArgs(1),
+	Run:     reedmuller.ReedMullerRun,
+}
+
+// createGolayCmd represents the Golay command
+var createGolayCmd = &cobra.Command{
+	Use:   "golay OUTPUT_GOLAY_JSON",
+	Short: "Creates a new Golay code based ECC",
+	Long:  `Creates a new binary (23,12) Golay code based ECC, or the extended (24,12) code, both correct any 3 errors.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   golay.GolayRun,
+}
+
+// createPolarCmd represents the polar command
+var createPolarCmd = &cobra.Command{
+	Use:   "polar OUTPUT_POLAR_JSON",
+	Short: "Creates a new polar code based ECC",
+	Long:  `Creates a new polar code based ECC. The frozen set is chosen for the design E_b/N_0 and stored alongside the equivalent linearblock code, so it can be used with the polar and the linearblock tools.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   polar.PolarRun,
+}
+
+// createConvolutionalCmd represents the convolutional command
+var createConvolutionalCmd = &cobra.Command{
+	Use:     "convolutional OUTPUT_CONVOLUTIONAL_JSON",
+	Aliases: []string{"conv"},
+	Short:   "Creates a new convolutional code based ECC",
+	Long:    `Creates a new rate 1/n convolutional code based ECC for blocks of a fixed message length, zero-tail or tail-biting with optional puncturing. It's stored alongside the equivalent linearblock code, so it can be used with the convolutional and the linearblock tools.`,
+	Args:    cobra.ExactArgs(1),
+	Run:     convolutional.ConvolutionalRun,
+}
+
+// createTurboCmd represents the turbo command
+var createTurboCmd = &cobra.Command{
+	Use:   "turbo OUTPUT_TURBO_JSON",
+	Short: "Creates a new turbo code based ECC",
+	Long:  `Creates a new parallel concatenated turbo code based ECC from two recursive systematic convolutional codes, rate matched to the codeword size by puncturing the parity bits.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   turbo.TurboRun,
+}
+
 // createCRJCmd represents the rcj command
 var createRCJCmd = &cobra.Command{
 	Use:   "rcj OUTPUT_LDPC_JSON",
@@ -78,6 +199,56 @@ func init() {
 	createLinearblockCmd.AddCommand(createHammingCmd)
 	createHammingCmd.Flags().UintVarP(&hamming.ParityBits, "parity", "p", 4, "the parity >=2, sets codeword size (cs) == 2^parity-1 and message size == cs-parity")
 	createHammingCmd.Flags().UintVarP(&hamming.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createHammingCmd.Flags().BoolVarP(&hamming.Extended, "extended", "e", false, "add an overall parity bit making it single error correcting and double error detecting (SECDED)")
+	createHammingCmd.Flags().UintVarP(&hamming.Message, "message", "m", 0, "creates a shortened extended (SECDED) code for this many message bits, ex: 64 gives (72,64) and 32 gives (39,32), ignores parity")
+
+	createLinearblockCmd.AddCommand(createBCHCmd)
+	createBCHCmd.Flags().UintVarP(&bch.M, "m", "m", 5, "the field GF(2^m) 2<=m<=16, sets codeword size == 2^m-1")
+	createBCHCmd.Flags().UintVarP(&bch.Distance, "distance", "d", 5, "the designed distance 3<=distance<=2^m-1")
+	createBCHCmd.Flags().UintVarP(&bch.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createBCHCmd.Flags().BoolVarP(&bch.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createLinearblockCmd.AddCommand(createReedMullerCmd)
+	createReedMullerCmd.Flags().UintVarP(&reedmuller.R, "r", "r", 1, "the order 0<=r<m, the codewords are the evaluations of the polynomials with degree <= r")
+	createReedMullerCmd.Flags().UintVarP(&reedmuller.M, "m", "m", 5, "the number of variables 1<=m<=16, sets codeword size == 2^m")
+	createReedMullerCmd.Flags().UintVarP(&reedmuller.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createReedMullerCmd.Flags().BoolVarP(&reedmuller.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createLinearblockCmd.AddCommand(createGolayCmd)
+	createGolayCmd.Flags().BoolVarP(&golay.Extended, "extended", "e", false, "add an overall parity bit giving the (24,12) code which also detects 4 errors")
+	createGolayCmd.Flags().UintVarP(&golay.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createGolayCmd.Flags().BoolVarP(&golay.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createCmd.AddCommand(createConvolutionalCmd)
+	createConvolutionalCmd.Flags().UintVarP(&convolutional.ConstraintLength, "constraint", "k", 7, "the constraint length K, the memory is K-1")
+	createConvolutionalCmd.Flags().StringSliceVarP(&convolutional.Generators, "generators", "g", []string{"171", "133"}, "the octal generator polynomials, one per output, the most significant bit taps the current input")
+	createConvolutionalCmd.Flags().StringVarP(&convolutional.Feedback, "feedback", "f", "", "the octal feedback polynomial of a recursive code, ex: -g 13,15 -f 13 for a RSC; note empty means feedforward")
+	createConvolutionalCmd.Flags().StringVarP(&convolutional.Termination, "termination", "e", "zero", "how the trellis is terminated: zero (zero-tail) or tailbiting")
+	createConvolutionalCmd.Flags().StringSliceVarP(&convolutional.Puncture, "puncture", "p", nil, "the puncturing pattern, a row of 0s and 1s per generator where 1 sends the output, ex: 11,10 for rate 2/3")
+	createConvolutionalCmd.Flags().UintVarP(&convolutional.Length, "length", "l", 1000, "the number of bits in the message")
+	createConvolutionalCmd.Flags().UintVarP(&convolutional.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createConvolutionalCmd.Flags().BoolVarP(&convolutional.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createCmd.AddCommand(createTurboCmd)
+	createTurboCmd.Flags().UintVarP(&turbo.ConstraintLength, "constraint", "k", 4, "the constraint length K of the RSC codes")
+	createTurboCmd.Flags().StringVarP(&turbo.Feedback, "feedback", "f", "13", "the octal feedback polynomial of the RSC codes")
+	createTurboCmd.Flags().StringVarP(&turbo.Parity, "parity", "g", "15", "the octal parity polynomial of the RSC codes")
+	createTurboCmd.Flags().UintVarP(&turbo.Length, "length", "l", 1000, "the number of bits in the message")
+	createTurboCmd.Flags().UintVarP(&turbo.Codeword, "codeword", "n", 0, "the codeword size after rate matching; note 0 sends every parity bit (rate ~1/3)")
+	createTurboCmd.Flags().StringVarP(&turbo.Interleaver, "interleaver", "i", "srandom", "the interleaver: random, srandom or qpp")
+	createTurboCmd.Flags().UintVarP(&turbo.Spread, "spread", "s", 0, "the spread S of the srandom interleaver; note 0 uses ⌊√(length/2)⌋")
+	createTurboCmd.Flags().UintVar(&turbo.F1, "f1", 0, "the f1 coefficient of the qpp interleaver π(i)=(f1·i+f2·i²) mod length")
+	createTurboCmd.Flags().UintVar(&turbo.F2, "f2", 0, "the f2 coefficient of the qpp interleaver π(i)=(f1·i+f2·i²) mod length")
+	createTurboCmd.Flags().BoolVarP(&turbo.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createCmd.AddCommand(createPolarCmd)
+	createPolarCmd.Flags().UintVarP(&polar.Codeword, "codeword", "n", 1024, "the codeword size, a power of 2")
+	createPolarCmd.Flags().UintVarP(&polar.Message, "message", "m", 512, "the number of bits in the message")
+	createPolarCmd.Flags().UintVarP(&polar.CRC, "crc", "c", 0, "the number of CRC bits appended to the message for CRC-aided SC-list decoding: 0 (none), 6, 8, 11, 16 or 24")
+	createPolarCmd.Flags().StringVarP(&polar.Construction, "construction", "k", "ga", "how the frozen set is chosen: ga (Gaussian approximation) or bhattacharyya")
+	createPolarCmd.Flags().Float64VarP(&polar.DesignEbN0, "snr", "s", 2.0, "the design E_b/N_0 (linear, not dB) the frozen set is chosen for")
+	createPolarCmd.Flags().UintVarP(&polar.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createPolarCmd.Flags().BoolVarP(&polar.Verbose, "verbose", "v", false, "enable verbose info")
 
 	createLinearblockCmd.AddCommand(createLdpcCmd)
 
@@ -99,6 +270,52 @@ func init() {
 	createGCECmd.Flags().BoolVarP(&gce.Force, "force", "f", false, "to enable forcing")
 	createGCECmd.Flags().BoolVarP(&gce.Verbose, "verbose", "v", false, "enable verbose info")
 
+	createLdpcCmd.AddCommand(createEGCmd)
+	createLdpcCmd.AddCommand(createPGCmd)
+	for _, c := range []*cobra.Command{createEGCmd, createPGCmd} {
+		c.Flags().UintVarP(&finitegeometry.M, "m", "m", 2, "the dimension m>=2 of the geometry")
+		c.Flags().UintVarP(&finitegeometry.S, "s", "s", 3, "the geometry is over GF(2^s), s>=1")
+		c.Flags().UintVarP(&finitegeometry.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+		c.Flags().BoolVarP(&finitegeometry.Verbose, "verbose", "v", false, "enable verbose info")
+	}
+
+	createLdpcCmd.AddCommand(createPEGCmd)
+	createPEGCmd.Flags().UintVarP(&peg.MessageSize, "message", "m", 1000, "the number of bits in the message")
+	createPEGCmd.Flags().UintVarP(&peg.CodewordSize, "codeword", "c", 2000, "the number of bits for the whole codeword(message+ecc)")
+	createPEGCmd.Flags().StringToStringVarP(&peg.VariableDegrees, "variable-degrees", "d", map[string]string{"3": "1"}, "the variable node degree distribution as degree=fraction pairs (ex: 2=0.5,3=0.3,8=0.2)")
+	createPEGCmd.Flags().StringToStringVarP(&peg.CheckDegrees, "check-degrees", "e", nil, "the check node degree distribution as degree=fraction pairs; note empty means the edges are spread evenly")
+	createPEGCmd.Flags().UintVarP(&peg.ACE, "ace", "a", 0, "the ACE cycle distance; note 0 disables the ACE criterion")
+	createPEGCmd.Flags().UintVarP(&peg.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createPEGCmd.Flags().BoolVarP(&peg.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createLdpcCmd.AddCommand(createQCCmd)
+	createQCCmd.Flags().StringVarP(&qc.ExponentsFile, "exponents", "e", "", "a file with the exponent matrix to use as is, one row per line")
+	createQCCmd.Flags().StringVarP(&qc.BaseFile, "base", "b", "", "a file with the 0/1 base matrix to search shifts for, one row per line")
+	createQCCmd.Flags().UintVarP(&qc.ColumnWeight, "column-weight", "j", 3, "the rows of the all ones base matrix used when no base matrix is given")
+	createQCCmd.Flags().UintVarP(&qc.RowWeight, "row-weight", "k", 6, "the columns of the all ones base matrix used when no base matrix is given")
+	createQCCmd.Flags().UintVarP(&qc.Lifting, "lifting", "z", 64, "the lifting size Z, the size of the circulants")
+	createQCCmd.Flags().UintVarP(&qc.Girth, "girth", "g", 8, "the smallest girth allowed when searching")
+	createQCCmd.Flags().UintVarP(&qc.Attempts, "attempts", "i", 1000, "the number of attempts before terminating the search")
+	createQCCmd.Flags().BoolVarP(&qc.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createLdpcCmd.AddCommand(createIRACmd)
+	createIRACmd.Flags().UintVarP(&ira.MessageSize, "message", "m", 1000, "the number of bits in the message")
+	createIRACmd.Flags().StringToStringVarP(&ira.Repetitions, "repetitions", "d", map[string]string{"3": "1"}, "the message bit repetition degree distribution as degree=fraction pairs (ex: 2=0.5,3=0.3,8=0.2)")
+	createIRACmd.Flags().UintVarP(&ira.Combining, "combining", "a", 3, "the number of repetitions combined into each check")
+	createIRACmd.Flags().BoolVarP(&ira.Verbose, "verbose", "v", false, "enable verbose info")
+
+	createLdpcCmd.AddCommand(createProtographCmd)
+	createProtographCmd.Flags().StringVarP(&protograph.ProtographFile, "protograph", "p", "", "a file with the protograph's base matrix, one row per line")
+	createProtographCmd.Flags().IntSliceVarP(&protograph.Punctured, "punctured", "u", nil, "the punctured variables of the protograph file")
+	createProtographCmd.Flags().StringVarP(&protograph.Rate, "rate", "r", "1/2", "the rate of the AR4JA protograph used when no protograph is given: 1/2, 2/3, 3/4 or 4/5")
+	createProtographCmd.Flags().UintVarP(&protograph.Prelift, "prelift", "l", 4, "the size of the first lifting with random permutations; note 1 skips it")
+	createProtographCmd.Flags().UintVarP(&protograph.Lifting, "lifting", "z", 64, "the size of the final lifting")
+	createProtographCmd.Flags().BoolVar(&protograph.Random, "random", false, "use random permutations for the final lifting instead of circulants")
+	createProtographCmd.Flags().UintVarP(&protograph.Girth, "girth", "g", 8, "the smallest girth allowed when searching circulants")
+	createProtographCmd.Flags().UintVarP(&protograph.Attempts, "attempts", "i", 1000, "the number of attempts before terminating the circulant search")
+	createProtographCmd.Flags().UintVarP(&protograph.Threads, "threads", "t", 0, "the number of threads to use; note 0 means use the number of cpus")
+	createProtographCmd.Flags().BoolVarP(&protograph.Verbose, "verbose", "v", false, "enable verbose info")
+
 	createLdpcCmd.AddCommand(createRCJCmd)
 	createRCJCmd.Flags().UintVarP(&rcj.Count, "count", "c", 128, "the number of loops of with the requested girth")
 	createRCJCmd.Flags().UintVarP(&rcj.Girth, "girth", "g", 20, "the girth to use")
@@ -0,0 +1,65 @@
+package bch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nathanhack/ecc/linearblock/bch"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	M        uint
+	Distance uint
+	Threads  uint
+	Verbose  bool
+)
+var BCHRun = func(cmd *cobra.Command, args []string) {
+	//we seed the randomizer so we get something different every time
+	rand.Seed(time.Now().Unix())
+
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	g, err := bch.New(ctx, int(M), int(Distance), int(Threads))
+	if err != nil {
+		fmt.Println("Unable to create BCH code: ", err)
+		return
+	}
+
+	if g == nil {
+		fmt.Println("Unable to create BCH code try again")
+		return
+	}
+
+	bs, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println("Unable to serialize the BCH code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
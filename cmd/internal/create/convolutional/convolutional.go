@@ -0,0 +1,112 @@
+package convolutional
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/nathanhack/ecc/convolutional"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ConstraintLength uint
+	Generators       []string
+	Feedback         string
+	Termination      string
+	Puncture         []string
+	Length           uint
+	Threads          uint
+	Verbose          bool
+)
+
+// File is the linear block code of the convolutional code stored alongside it, so the
+// file is usable by both the convolutional and the linearblock tools.
+type File struct {
+	*linearblock.LinearBlock
+	Convolutional *convolutional.Convolutional
+}
+
+var ConvolutionalRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	generators := make([]int, len(Generators))
+	for i, g := range Generators {
+		value, err := strconv.ParseInt(g, 8, 64)
+		if err != nil {
+			fmt.Printf("generator polynomials must be octal but found %v\n", g)
+			return
+		}
+		generators[i] = int(value)
+	}
+
+	feedback := int64(0)
+	if Feedback != "" {
+		var err error
+		feedback, err = strconv.ParseInt(Feedback, 8, 64)
+		if err != nil {
+			fmt.Printf("the feedback polynomial must be octal but found %v\n", Feedback)
+			return
+		}
+	}
+
+	var puncture [][]int
+	for _, row := range Puncture {
+		p := make([]int, len(row))
+		for i, r := range row {
+			switch r {
+			case '0':
+			case '1':
+				p[i] = 1
+			default:
+				fmt.Printf("puncturing pattern rows must only contain 0 or 1 but found %v\n", row)
+				return
+			}
+		}
+		puncture = append(puncture, p)
+	}
+
+	c, err := convolutional.NewRecursive(int(ConstraintLength), generators, int(feedback), convolutional.Termination(Termination), puncture, int(Length))
+	if err != nil {
+		fmt.Println("Unable to create convolutional code: ", err)
+		return
+	}
+	logrus.Debugf("%v has rate %v", c, c.CodeRate())
+
+	g, err := c.LinearBlock(ctx, int(Threads))
+	if err != nil {
+		fmt.Println("Unable to create convolutional code: ", err)
+		return
+	}
+
+	bs, err := json.Marshal(File{LinearBlock: g, Convolutional: c})
+	if err != nil {
+		fmt.Println("Unable to serialize the convolutional code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
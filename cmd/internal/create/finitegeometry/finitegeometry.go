@@ -0,0 +1,68 @@
+package finitegeometry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/ldpc/finitegeometry"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	M       uint
+	S       uint
+	Threads uint
+	Verbose bool
+)
+
+var EGRun = func(cmd *cobra.Command, args []string) {
+	run(args[0], "EG", finitegeometry.EuclideanGeometry)
+}
+
+var PGRun = func(cmd *cobra.Command, args []string) {
+	run(args[0], "PG", finitegeometry.ProjectiveGeometry)
+}
+
+func run(filename, name string, create func(ctx context.Context, m, s int, threads int) (*linearblock.LinearBlock, error)) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	g, err := create(ctx, int(M), int(S), int(Threads))
+	if err != nil {
+		fmt.Printf("Unable to create %v-LDPC: %v\n", name, err)
+		return
+	}
+
+	rows, _ := g.H.Dims()
+	logrus.Debugf("%v(%v,2^%v)-LDPC Message Size:%v Codeword Size:%v Parity Checks:%v Code Rate: %v", name, M, S, g.MessageLength(), g.CodewordLength(), rows, g.CodeRate())
+
+	bs, err := json.Marshal(g)
+	if err != nil {
+		fmt.Printf("Unable to serialize the %v-LDPC: %v\n", name, err)
+		return
+	}
+
+	err = ioutil.WriteFile(filename, bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
@@ -0,0 +1,66 @@
+package golay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/golay"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Extended bool
+	Threads  uint
+	Verbose  bool
+)
+var GolayRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	var g *linearblock.LinearBlock
+	var err error
+	if Extended {
+		g, err = golay.NewExtended(ctx, int(Threads))
+	} else {
+		g, err = golay.New(ctx, int(Threads))
+	}
+	if err != nil {
+		fmt.Println("Unable to create Golay code: ", err)
+		return
+	}
+
+	if g == nil {
+		fmt.Println("Unable to create Golay code try again")
+		return
+	}
+
+	bs, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println("Unable to serialize the Golay code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
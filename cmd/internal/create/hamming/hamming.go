@@ -11,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nathanhack/ecc/linearblock"
 	"github.com/nathanhack/ecc/linearblock/hamming"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -18,6 +19,8 @@ import (
 
 var (
 	ParityBits uint
+	Extended   bool
+	Message    uint
 	Threads    uint
 	Verbose    bool
 )
@@ -40,7 +43,16 @@ var HammingRun = func(cmd *cobra.Command, args []string) {
 		cancel()
 	}()
 
-	g, err := hamming.New(ctx, int(ParityBits), int(Threads))
+	var g *linearblock.LinearBlock
+	var err error
+	switch {
+	case Message > 0:
+		g, err = hamming.NewSECDED(ctx, int(Message), int(Threads))
+	case Extended:
+		g, err = hamming.NewExtended(ctx, int(ParityBits), int(Threads))
+	default:
+		g, err = hamming.New(ctx, int(ParityBits), int(Threads))
+	}
 	if err != nil {
 		fmt.Println("Unable to create gallager LDPC: ", err)
 		return
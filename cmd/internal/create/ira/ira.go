@@ -0,0 +1,60 @@
+package ira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/ldpc/ira"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	MessageSize uint
+	Repetitions map[string]string
+	Combining   uint
+	Verbose     bool
+)
+
+// File stores the IRA code as its H only, the tools encode with its accumulator
+// instead of a generator matrix, see tools.LoadLinearBlockECC.
+type File struct {
+	IRA *ira.IRA
+}
+
+var IRARun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+
+	repetitions, err := tools.ParseDistribution(Repetitions)
+	if err != nil {
+		fmt.Println("Invalid repetitions: ", err)
+		return
+	}
+
+	code, err := ira.NewIRA(int(MessageSize), repetitions, int(Combining))
+	if err != nil {
+		fmt.Println("Unable to create IRA: ", err)
+		return
+	}
+
+	block := code.LinearBlock()
+	rows, _ := block.H.Dims()
+	logrus.Debugf("IRA Message Size:%v Codeword Size:%v Parity Checks:%v Code Rate: %v", block.MessageLength(), block.CodewordLength(), rows, block.CodeRate())
+
+	bs, err := json.Marshal(File{IRA: code})
+	if err != nil {
+		fmt.Println("Unable to serialize the IRA: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
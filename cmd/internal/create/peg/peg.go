@@ -0,0 +1,79 @@
+package peg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/ldpc/peg"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	MessageSize     uint
+	CodewordSize    uint
+	VariableDegrees map[string]string
+	CheckDegrees    map[string]string
+	ACE             uint
+	Threads         uint
+	Verbose         bool
+)
+
+var PEGRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	if MessageSize >= CodewordSize {
+		fmt.Println("required MessageSize < CodewordSize")
+		return
+	}
+
+	variableDegrees, err := tools.ParseDistribution(VariableDegrees)
+	if err != nil {
+		fmt.Println("Invalid variable node degrees: ", err)
+		return
+	}
+	checkDegrees, err := tools.ParseDistribution(CheckDegrees)
+	if err != nil {
+		fmt.Println("Invalid check node degrees: ", err)
+		return
+	}
+
+	g, err := peg.New(ctx, int(CodewordSize-MessageSize), int(CodewordSize), variableDegrees, checkDegrees, int(ACE), int(Threads))
+	if err != nil {
+		fmt.Println("Unable to create PEG-LDPC: ", err)
+		return
+	}
+
+	rows, _ := g.H.Dims()
+	logrus.Debugf("PEG-LDPC Message Size:%v Codeword Size:%v Parity Checks:%v Code Rate: %v", g.MessageLength(), g.CodewordLength(), rows, g.CodeRate())
+
+	bs, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println("Unable to serialize the PEG-LDPC: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
@@ -0,0 +1,74 @@
+package polar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/polar"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Codeword     uint
+	Message      uint
+	CRC          uint
+	Construction string
+	DesignEbN0   float64
+	Threads      uint
+	Verbose      bool
+)
+
+// File is the linear block code of the polar code stored alongside it, so the
+// file is usable by both the polar and the linearblock tools.
+type File struct {
+	*linearblock.LinearBlock
+	Polar *polar.Polar
+}
+
+var PolarRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	p, err := polar.New(int(Codeword), int(Message), int(CRC), polar.Construction(Construction), DesignEbN0)
+	if err != nil {
+		fmt.Println("Unable to create polar code: ", err)
+		return
+	}
+	logrus.Debugf("%v frozen bits: %v", p, p.Frozen)
+
+	g, err := p.LinearBlock(ctx, int(Threads))
+	if err != nil {
+		fmt.Println("Unable to create polar code: ", err)
+		return
+	}
+
+	bs, err := json.Marshal(File{LinearBlock: g, Polar: p})
+	if err != nil {
+		fmt.Println("Unable to serialize the polar code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
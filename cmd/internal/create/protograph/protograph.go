@@ -0,0 +1,133 @@
+package protograph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/ldpc/protograph"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ProtographFile string
+	Punctured      []int
+	Rate           string
+	Prelift        uint
+	Lifting        uint
+	Girth          uint
+	Attempts       uint
+	Random         bool
+	Threads        uint
+	Verbose        bool
+)
+
+// ar4jaExtensions are the number of AR4JA extensions for each rate
+var ar4jaExtensions = map[string]int{
+	"1/2": 0,
+	"2/3": 1,
+	"3/4": 2,
+	"4/5": 3,
+}
+
+var ProtographRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	var p *protograph.Protograph
+	var err error
+	if ProtographFile != "" {
+		base, err := tools.ReadMatrix(ProtographFile)
+		if err != nil {
+			fmt.Println("Unable to read the protograph: ", err)
+			return
+		}
+		p, err = protograph.New(base, Punctured)
+		if err != nil {
+			fmt.Println("Invalid protograph: ", err)
+			return
+		}
+	} else {
+		extensions, ok := ar4jaExtensions[Rate]
+		if !ok {
+			fmt.Printf("AR4JA rate must be one of 1/2, 2/3, 3/4 or 4/5 but found %v\n", Rate)
+			return
+		}
+		p, err = protograph.AR4JA(extensions)
+		if err != nil {
+			fmt.Println("Unable to create AR4JA protograph: ", err)
+			return
+		}
+	}
+	logrus.Debugf("%v", p)
+
+	if Prelift > 1 {
+		p, err = p.Lift(int(Prelift))
+		if err != nil {
+			fmt.Println("Unable to lift the protograph: ", err)
+			return
+		}
+	}
+
+	var bs []byte
+	var punctured []int
+	if Random {
+		lifted, err := p.Lift(int(Lifting))
+		if err != nil {
+			fmt.Println("Unable to lift the protograph: ", err)
+			return
+		}
+		block, err := lifted.LinearBlock(ctx, int(Threads))
+		if err != nil {
+			fmt.Println("Unable to create Protograph-LDPC: ", err)
+			return
+		}
+		rows, _ := block.H.Dims()
+		logrus.Debugf("Protograph-LDPC Message Size:%v Codeword Size:%v Parity Checks:%v Code Rate: %v", block.MessageLength(), block.CodewordLength(), rows, block.CodeRate())
+
+		punctured = lifted.Punctured
+		bs, err = json.Marshal(block)
+		if err != nil {
+			fmt.Println("Unable to serialize the Protograph-LDPC: ", err)
+			return
+		}
+	} else {
+		q, err := p.QC(ctx, int(Lifting), int(Girth), int(Attempts))
+		if err != nil {
+			fmt.Println("Unable to create Protograph-LDPC: ", err)
+			return
+		}
+		logrus.Debugf("Protograph-LDPC %vx%v base matrix Z:%v Codeword Size:%v Parity Checks:%v", len(q.Exponents), len(q.Exponents[0]), q.Z, len(q.Exponents[0])*q.Z, len(q.Exponents)*q.Z)
+
+		punctured = p.PuncturedColumns(int(Lifting))
+		bs, err = json.Marshal(q)
+		if err != nil {
+			fmt.Println("Unable to serialize the Protograph-LDPC: ", err)
+			return
+		}
+	}
+	logrus.Debugf("Design Rate:%v Punctured Columns:%v", p.Rate(), punctured)
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
@@ -0,0 +1,98 @@
+package qc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/ldpc/qc"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ExponentsFile string
+	BaseFile      string
+	ColumnWeight  uint
+	RowWeight     uint
+	Lifting       uint
+	Girth         uint
+	Attempts      uint
+	Verbose       bool
+)
+
+var QCRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	var q *qc.QC
+	switch {
+	case ExponentsFile != "":
+		exponents, err := tools.ReadMatrix(ExponentsFile)
+		if err != nil {
+			fmt.Println("Unable to read the exponent matrix: ", err)
+			return
+		}
+		q, err = qc.New(exponents, int(Lifting))
+		if err != nil {
+			fmt.Println("Unable to create QC-LDPC: ", err)
+			return
+		}
+	default:
+		base := make([][]int, ColumnWeight)
+		for i := range base {
+			base[i] = make([]int, RowWeight)
+			for j := range base[i] {
+				base[i][j] = 1
+			}
+		}
+		var err error
+		if BaseFile != "" {
+			base, err = tools.ReadMatrix(BaseFile)
+			if err != nil {
+				fmt.Println("Unable to read the base matrix: ", err)
+				return
+			}
+		}
+
+		q, err = qc.Search(ctx, base, int(Lifting), int(Girth), int(Attempts))
+		if err != nil {
+			fmt.Println("Unable to create QC-LDPC: ", err)
+			return
+		}
+	}
+
+	girth := fmt.Sprint(q.Girth(12))
+	if girth == "-1" {
+		girth = ">12"
+	}
+	logrus.Debugf("QC-LDPC %vx%v base matrix Z:%v Codeword Size:%v Parity Checks:%v Girth:%v", len(q.Exponents), len(q.Exponents[0]), q.Z, len(q.Exponents[0])*q.Z, len(q.Exponents)*q.Z, girth)
+
+	bs, err := json.Marshal(q)
+	if err != nil {
+		fmt.Println("Unable to serialize the QC-LDPC: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
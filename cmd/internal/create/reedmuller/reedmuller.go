@@ -0,0 +1,60 @@
+package reedmuller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nathanhack/ecc/linearblock/reedmuller"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	R       uint
+	M       uint
+	Threads uint
+	Verbose bool
+)
+var ReedMullerRun = func(cmd *cobra.Command, args []string) {
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	g, err := reedmuller.New(ctx, int(R), int(M), int(Threads))
+	if err != nil {
+		fmt.Println("Unable to create Reed-Muller code: ", err)
+		return
+	}
+
+	if g == nil {
+		fmt.Println("Unable to create Reed-Muller code try again")
+		return
+	}
+
+	bs, err := json.Marshal(g)
+	if err != nil {
+		fmt.Println("Unable to serialize the Reed-Muller code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
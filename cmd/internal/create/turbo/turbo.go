@@ -0,0 +1,89 @@
+package turbo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/nathanhack/ecc/turbo"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ConstraintLength uint
+	Feedback         string
+	Parity           string
+	Length           uint
+	Codeword         uint
+	Interleaver      string
+	Spread           uint
+	F1               uint
+	F2               uint
+	Verbose          bool
+)
+
+var TurboRun = func(cmd *cobra.Command, args []string) {
+	//we seed the randomizer so we get something different every time
+	rand.Seed(time.Now().Unix())
+
+	if Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+
+	feedback, err := strconv.ParseInt(Feedback, 8, 64)
+	if err != nil {
+		fmt.Printf("the feedback polynomial must be octal but found %v\n", Feedback)
+		return
+	}
+	parity, err := strconv.ParseInt(Parity, 8, 64)
+	if err != nil {
+		fmt.Printf("the parity polynomial must be octal but found %v\n", Parity)
+		return
+	}
+
+	var interleaver []int
+	switch Interleaver {
+	case "random":
+		interleaver = turbo.RandomInterleaver(int(Length))
+	case "srandom":
+		spread := int(Spread)
+		if spread == 0 {
+			spread = int(math.Sqrt(float64(Length) / 2))
+		}
+		logrus.Debugf("using a spread of %v", spread)
+		interleaver, err = turbo.SRandomInterleaver(int(Length), spread)
+	case "qpp":
+		interleaver, err = turbo.QPPInterleaver(int(Length), int(F1), int(F2))
+	default:
+		err = fmt.Errorf("interleaver must be random, srandom or qpp but found %v", Interleaver)
+	}
+	if err != nil {
+		fmt.Println("Unable to create the interleaver: ", err)
+		return
+	}
+
+	t, err := turbo.New(int(ConstraintLength), int(feedback), int(parity), interleaver, int(Codeword))
+	if err != nil {
+		fmt.Println("Unable to create turbo code: ", err)
+		return
+	}
+	logrus.Debugf("%v has rate %v", t, t.CodeRate())
+
+	bs, err := json.Marshal(t)
+	if err != nil {
+		fmt.Println("Unable to serialize the turbo code: ", err)
+		return
+	}
+
+	err = ioutil.WriteFile(args[0], bs, 0644)
+	if err != nil {
+		fmt.Println("unable to write file: ", err)
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/nathanhack/ecc/benchmarking"
 	"github.com/nathanhack/ecc/linearblock"
-	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
 	mat "github.com/nathanhack/sparsemat"
 )
 
@@ -17,7 +16,7 @@ const bitLimit = 30
 func RunBEC(ctx context.Context,
 	l *linearblock.LinearBlock,
 	percentage float64, trials, threads int,
-	correctionAlg benchmarking.BinaryErasureChannelCorrection,
+	newDecoder benchmarking.DecoderConstructor,
 	previousStats benchmarking.Stats,
 	checkpoints benchmarking.Checkpoints,
 	showProgressBar bool) benchmarking.Stats {
@@ -58,26 +57,11 @@ func RunBEC(ctx context.Context,
 		return message
 	}
 
-	encode := func(message mat.SparseVector) (codeword []bec.ErasureBit) {
-		return l.EncodeBE(message)
+	channel := func(originalCodeword mat.SparseVector) (received linearblock.Received) {
+		erasures := benchmarking.BitsToErased(originalCodeword)
+		count := int(percentage * float64(len(erasures)))
+		return linearblock.Received{Erasures: benchmarking.RandomEraseCount(erasures, count)}
 	}
 
-	channel := func(originalCodeword []bec.ErasureBit) (erroredCodeword []bec.ErasureBit) {
-		count := int(percentage * float64(len(originalCodeword)))
-		return benchmarking.RandomEraseCount(originalCodeword, count)
-	}
-
-	metrics := func(originalMessage mat.SparseVector, originalCodeword, fixedChannelInducedCodeword []bec.ErasureBit) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64) {
-		codewordErrors := benchmarking.ErasedCount(fixedChannelInducedCodeword)
-		message := l.DecodeBE(fixedChannelInducedCodeword)
-		messageErrors := benchmarking.ErasedCount(message)
-		parityErrors := codewordErrors - messageErrors
-
-		percentFixedCodewordErrors = float64(codewordErrors) / float64(l.CodewordLength())
-		percentFixedMessageErrors = float64(messageErrors) / float64(l.MessageLength())
-		percentFixedParityErrors = float64(parityErrors) / float64(l.ParitySymbols())
-		return
-	}
-
-	return benchmarking.BenchmarkBECContinueStats(ctx, trials, threads, createMessage, encode, channel, correctionAlg, metrics, checkpoints, previousStats, showProgressBar)
+	return benchmarking.BenchmarkDecoderContinueStats(ctx, l, trials, threads, createMessage, channel, newDecoder, checkpoints, previousStats, showProgressBar)
 }
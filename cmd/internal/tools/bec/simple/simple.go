@@ -17,6 +17,7 @@ import (
 	"github.com/nathanhack/ecc/linearblock"
 	bec2 "github.com/nathanhack/ecc/linearblock/messagepassing/bec"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bec/iterative"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec/ml"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,8 @@ var (
 	Trials           uint
 	ErrorProbability []float64
 	Threads          uint
+	MaxIter          uint
+	Decoder          string
 )
 var BecRun = func(cmd *cobra.Command, args []string) {
 	if len(args) != 2 {
@@ -38,6 +41,12 @@ var BecRun = func(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	alg, err := newAlg(ecc)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
 	data, err := tools.LoadResults(args[1])
 	if err != nil {
@@ -48,15 +57,15 @@ var BecRun = func(cmd *cobra.Command, args []string) {
 	//if data is nil then we create it
 	if data == nil {
 		data = &tools.SimulationStats{
-			TypeInfo: typeInfo(),
+			TypeInfo: typeInfo(alg),
 			ECCInfo:  tools.Md5Sum(ecc.H),
 			Stats:    make(map[float64]benchmarking.Stats),
 		}
 	}
 
 	//in either case lets validate it
-	if data.TypeInfo != typeInfo() {
-		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(), data.TypeInfo)
+	if data.TypeInfo != typeInfo(alg) {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(alg), data.TypeInfo)
 		return
 	}
 	if data.ECCInfo != tools.Md5Sum(ecc.H) {
@@ -75,7 +84,7 @@ var BecRun = func(cmd *cobra.Command, args []string) {
 		cancel()
 	}()
 
-	runSimulation(ctx, data, ecc, args[1])
+	runSimulation(ctx, data, ecc, alg, args[1])
 
 	err = tools.SaveResults(args[1], data)
 	if err != nil {
@@ -83,8 +92,22 @@ var BecRun = func(cmd *cobra.Command, args []string) {
 	}
 }
 
-func typeInfo() string {
-	t := reflect.TypeOf(iterative.Simple{})
+// newAlg creates the BEC decoder selected by Decoder
+func newAlg(ecc *linearblock.LinearBlock) (bec2.BECFlippingAlg, error) {
+	switch Decoder {
+	case "", "peeling":
+		return iterative.NewSimple(ecc.H), nil
+	case "ml":
+		//the simulation already runs trials in parallel
+		return &ml.ML{H: ecc.H, Threads: 1}, nil
+	case "hybrid":
+		return &ml.Hybrid{H: ecc.H, Threads: 1}, nil
+	}
+	return nil, fmt.Errorf("unknown decoder %v expected one of peeling, ml, or hybrid", Decoder)
+}
+
+func typeInfo(alg bec2.BECFlippingAlg) string {
+	t := reflect.TypeOf(alg).Elem()
 	return fmt.Sprintf("BEC:%v/%v", t.PkgPath(), t.Name())
 
 }
@@ -96,15 +119,12 @@ func min(a, b int) int {
 	return b
 }
 
-func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linearblock.LinearBlock, outputFilename string) {
+func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linearblock.LinearBlock, alg bec2.BECFlippingAlg, outputFilename string) {
 	checkpointMux := sync.Mutex{}
 	checkpointCount := 0
 
-	alg := &iterative.Simple{
-		H: ecc.H,
-	}
-	correctionAlg := func(originalCodeword, channelInducedCodeword []bec2.ErasureBit) (fixedChannelInducedCodeword []bec2.ErasureBit) {
-		return bec2.Flipping(alg, channelInducedCodeword)
+	newDecoder := func() linearblock.Decoder {
+		return &linearblock.ErasureDecoder{Block: ecc, Alg: alg, MaxIter: int(MaxIter)}
 	}
 
 	numberOfThread := int(Threads)
@@ -138,7 +158,7 @@ trialLoops:
 				}
 				checkpointCount++
 			}
-			data.Stats[p] = bec.RunBEC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, correctionAlg, data.Stats[p], checkpoint, false)
+			data.Stats[p] = bec.RunBEC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
 			bar.Add(trialsPerIter)
 		}
 	}
@@ -0,0 +1,29 @@
+package bpsk
+
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func RunBPSK(ctx context.Context,
+	l *linearblock.LinearBlock,
+	E_bPerN_0 float64, trials, threads int,
+	newDecoder benchmarking.DecoderConstructor,
+	previousStats benchmarking.Stats,
+	checkpoints benchmarking.Checkpoints,
+	showProgress bool) benchmarking.Stats {
+
+	createMessage := func(trial int) mat.SparseVector {
+		return benchmarking.RandomMessage(l.MessageLength())
+	}
+
+	channel := func(codeword mat.SparseVector) (received linearblock.Received) {
+		noisy := benchmarking.RandomNoiseBPSK(benchmarking.BitsToBPSK(codeword), E_bPerN_0)
+		return linearblock.Received{LLR: benchmarking.BPSKToLLR(noisy, E_bPerN_0)}
+	}
+
+	return benchmarking.BenchmarkDecoderContinueStats(ctx, l, trials, threads, createMessage, channel, newDecoder, checkpoints, previousStats, showProgress)
+}
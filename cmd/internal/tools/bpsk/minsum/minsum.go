@@ -0,0 +1,173 @@
+package minsum
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/softdecision"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Trials     uint
+	EbN0       []float64
+	Threads    uint
+	MaxIter    uint
+	Alpha      float64
+	BetaOffset float64
+	Layered    bool
+	LayerSize  uint
+	OSDOrder   int
+)
+
+var MinSumRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) softdecision.IterativeDecoder {
+		return &softdecision.MinSum{H: ecc.H, Schedule: tools.Schedule(Layered), LayerSize: int(LayerSize)}
+	})
+}
+
+var NormalizedMinSumRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) softdecision.IterativeDecoder {
+		return &softdecision.NormalizedMinSum{H: ecc.H, AlphaFactor: Alpha, Schedule: tools.Schedule(Layered), LayerSize: int(LayerSize)}
+	})
+}
+
+var OffsetMinSumRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) softdecision.IterativeDecoder {
+		return &softdecision.OffsetMinSum{H: ecc.H, BetaOffset: BetaOffset, Schedule: tools.Schedule(Layered), LayerSize: int(LayerSize)}
+	})
+}
+
+func run(args []string, newDecoder func(ecc *linearblock.LinearBlock) softdecision.IterativeDecoder) {
+	if len(args) != 2 {
+		fmt.Println("requires both ECC_JSON_FILE RESULT_JSON")
+		return
+	}
+
+	//first get the ECC to use
+	ecc, err := tools.LoadLinearBlockECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	alg := newDecoder(ecc)
+	if OSDOrder >= 0 {
+		alg = &softdecision.WithOSD{Decoder: alg, OSD: &softdecision.OSD{H: ecc.H, Order: OSDOrder, Threads: 1}}
+	}
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(alg),
+			ECCInfo:  tools.Md5Sum(ecc.H),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo(alg) {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(alg), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != tools.Md5Sum(ecc.H) {
+		fmt.Printf("csv loaded does not match the ECC")
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, ecc, alg, args[1])
+
+	err = tools.SaveResults(args[1], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func typeInfo(alg softdecision.IterativeDecoder) string {
+	if w, ok := alg.(*softdecision.WithOSD); ok {
+		alg = w.Decoder
+	}
+	t := reflect.TypeOf(alg).Elem()
+	return fmt.Sprintf("BPSK:%v/%v%v%v", t.PkgPath(), t.Name(), tools.ScheduleInfo(tools.Schedule(Layered), int(LayerSize)), tools.OSDInfo(OSDOrder))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linearblock.LinearBlock, alg softdecision.IterativeDecoder, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	decoder := &softdecision.Decoder{Block: ecc, Alg: alg, MaxIter: int(MaxIter)}
+	newDecoder := func() linearblock.Decoder {
+		return decoder
+	}
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(EbN0))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range EbN0 {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = bpsk.RunBPSK(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
@@ -0,0 +1,200 @@
+package polar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/polar"
+	mat "github.com/nathanhack/sparsemat"
+	"github.com/spf13/cobra"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+var (
+	Trials   uint
+	EbN0     []float64
+	Threads  uint
+	ListSize uint
+)
+
+var PolarRun = func(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		fmt.Println("requires both ECC_JSON_FILE RESULT_JSON")
+		return
+	}
+	if ListSize < 1 {
+		fmt.Printf("list size must be >=1 but found %v\n", ListSize)
+		return
+	}
+
+	//first get the ECC to use
+	code, err := tools.LoadPolarECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ecc, err := tools.LoadLinearBlockECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(),
+			ECCInfo:  tools.Md5Sum(ecc.H),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo() {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != tools.Md5Sum(ecc.H) {
+		fmt.Printf("csv loaded does not match the ECC")
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, code, args[1])
+
+	err = tools.SaveResults(args[1], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func typeInfo() string {
+	if ListSize == 1 {
+		t := reflect.TypeOf(polar.SC{})
+		return fmt.Sprintf("BPSK:%v/%v", t.PkgPath(), t.Name())
+	}
+	t := reflect.TypeOf(polar.SCL{})
+	return fmt.Sprintf("BPSK:%v/%v(%v)", t.PkgPath(), t.Name(), ListSize)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, code *polar.Polar, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	var decoder polar.Decoder = &polar.SC{Code: code}
+	if ListSize > 1 {
+		decoder = &polar.SCL{Code: code, ListSize: int(ListSize)}
+	}
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(EbN0))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range EbN0 {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = RunPolar(ctx, code, decoder, p, min(t, int(Trials)), numberOfThread, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
+
+// RunPolar simulates the polar code over the BPSK channel with the decoder. Polar codes aren't systematic
+// so the parity errors are the errors in the CRC bits of the decoded info set, always 0 without a CRC.
+func RunPolar(ctx context.Context,
+	code *polar.Polar,
+	decoder polar.Decoder,
+	E_bPerN_0 float64, trials, threads int,
+	previousStats benchmarking.Stats,
+	checkpoints benchmarking.Checkpoints,
+	showProgress bool) benchmarking.Stats {
+
+	createMessage := func(trial int) mat.SparseVector {
+		return benchmarking.RandomMessage(code.K)
+	}
+
+	encode := func(message mat.SparseVector) (codeword mat2.Vector) {
+		return benchmarking.BitsToBPSK(code.Encode(message))
+	}
+
+	channel := func(codeword mat2.Vector) (channelInducedCodeword mat2.Vector) {
+		return benchmarking.RandomNoiseBPSK(codeword, E_bPerN_0)
+	}
+
+	codewordRepair := func(originalCodeword, channelInducedCodeword mat2.Vector) (fixedChannelInducedCodeword mat2.Vector) {
+		return benchmarking.BitsToBPSK(decoder.Decode(benchmarking.BPSKToLLR(channelInducedCodeword, E_bPerN_0)))
+	}
+
+	metrics := func(message mat.SparseVector, originalCodeword, fixedChannelInducedCodeword mat2.Vector) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64) {
+		codewordErrors := benchmarking.HammingDistanceBPSK(originalCodeword, fixedChannelInducedCodeword)
+		info := code.InfoBits(benchmarking.BPSKToBits(fixedChannelInducedCodeword, 0))
+		originalInfo := code.InfoBits(benchmarking.BPSKToBits(originalCodeword, 0))
+		messageErrors := info.Slice(0, code.K).HammingDistance(message)
+
+		percentFixedCodewordErrors = float64(codewordErrors) / float64(code.N)
+		percentFixedMessageErrors = float64(messageErrors) / float64(code.K)
+		if code.CRC > 0 {
+			parityErrors := info.Slice(code.K, code.CRC).HammingDistance(originalInfo.Slice(code.K, code.CRC))
+			percentFixedParityErrors = float64(parityErrors) / float64(code.CRC)
+		}
+		return
+	}
+
+	return benchmarking.BenchmarkBPSKContinueStats(ctx, trials, threads, createMessage, encode, channel, codewordRepair, metrics, checkpoints, previousStats, showProgress)
+}
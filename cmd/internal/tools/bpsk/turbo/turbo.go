@@ -0,0 +1,193 @@
+package turbo
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/turbo"
+	mat "github.com/nathanhack/sparsemat"
+	"github.com/spf13/cobra"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+var (
+	Trials  uint
+	EbN0    []float64
+	Threads uint
+	MaxIter uint
+	MaxLog  bool
+)
+
+var TurboRun = func(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		fmt.Println("requires both ECC_JSON_FILE RESULT_JSON")
+		return
+	}
+
+	//first get the ECC to use
+	code, err := tools.LoadTurboECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(),
+			ECCInfo:  eccInfo(code),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo() {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != eccInfo(code) {
+		fmt.Printf("csv loaded does not match the ECC")
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, code, args[1])
+
+	err = tools.SaveResults(args[1], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func typeInfo() string {
+	t := reflect.TypeOf(turbo.Decoder{})
+	alg := "LogMAP"
+	if MaxLog {
+		alg = "MaxLogMAP"
+	}
+	return fmt.Sprintf("BPSK:%v/%v(%v,%v)", t.PkgPath(), t.Name(), alg, MaxIter)
+}
+
+// eccInfo identifies the turbo code, its interleaver is hashed since it's as long as the message
+func eccInfo(code *turbo.Turbo) string {
+	return fmt.Sprintf("%v:%x", code, md5.Sum([]byte(fmt.Sprint(code.Interleaver))))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, code *turbo.Turbo, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	decoder := &turbo.Decoder{Code: code, MaxLog: MaxLog}
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(EbN0))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range EbN0 {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = RunTurbo(ctx, code, decoder, int(MaxIter), p, min(t, int(Trials)), numberOfThread, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
+
+// RunTurbo simulates the turbo code over the BPSK channel with the decoder, the E_b/N_0 is per channel
+// bit like every other BPSK simulation so results at the same rate and block length are comparable.
+func RunTurbo(ctx context.Context,
+	code *turbo.Turbo,
+	decoder *turbo.Decoder,
+	maxIter int,
+	E_bPerN_0 float64, trials, threads int,
+	previousStats benchmarking.Stats,
+	checkpoints benchmarking.Checkpoints,
+	showProgress bool) benchmarking.Stats {
+
+	createMessage := func(trial int) mat.SparseVector {
+		return benchmarking.RandomMessage(code.MessageLength())
+	}
+
+	encode := func(message mat.SparseVector) (codeword mat2.Vector) {
+		return benchmarking.BitsToBPSK(code.Encode(message))
+	}
+
+	channel := func(codeword mat2.Vector) (channelInducedCodeword mat2.Vector) {
+		return benchmarking.RandomNoiseBPSK(codeword, E_bPerN_0)
+	}
+
+	codewordRepair := func(originalCodeword, channelInducedCodeword mat2.Vector) (fixedChannelInducedCodeword mat2.Vector, iterations int) {
+		codeword, iterations, _ := decoder.DecodeContext(ctx, benchmarking.BPSKToLLR(channelInducedCodeword, E_bPerN_0), maxIter)
+		return benchmarking.BitsToBPSK(codeword), iterations
+	}
+
+	metrics := func(message mat.SparseVector, originalCodeword, fixedChannelInducedCodeword mat2.Vector) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64) {
+		codewordErrors := benchmarking.HammingDistanceBPSK(originalCodeword, fixedChannelInducedCodeword)
+		messageErrors := code.Message(benchmarking.BPSKToBits(fixedChannelInducedCodeword, 0)).HammingDistance(message)
+		parityErrors := codewordErrors - messageErrors
+
+		percentFixedCodewordErrors = float64(codewordErrors) / float64(code.CodewordLength())
+		percentFixedMessageErrors = float64(messageErrors) / float64(code.MessageLength())
+		percentFixedParityErrors = float64(parityErrors) / float64(code.CodewordLength()-code.MessageLength())
+		return
+	}
+
+	return benchmarking.BenchmarkBPSKIterativeContinueStats(ctx, trials, threads, createMessage, encode, channel, codewordRepair, metrics, checkpoints, previousStats, showProgress)
+}
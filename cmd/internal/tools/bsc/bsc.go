@@ -16,7 +16,7 @@ const bitLimit = 64
 func RunBSC(ctx context.Context,
 	l *linearblock.LinearBlock,
 	crossoverProbability float64, trials, threads int,
-	correctionAlg benchmarking.BinarySymmetricChannelCorrection,
+	newDecoder benchmarking.DecoderConstructor,
 	previousStats benchmarking.Stats,
 	checkpoints benchmarking.Checkpoints,
 	showProgress bool) benchmarking.Stats {
@@ -53,26 +53,10 @@ func RunBSC(ctx context.Context,
 		return message
 	}
 
-	encode := func(message mat.SparseVector) (codeword mat.SparseVector) {
-		return l.Encode(message)
-	}
-
-	channel := func(originalCodeword mat.SparseVector) (erroredCodeword mat.SparseVector) {
+	channel := func(originalCodeword mat.SparseVector) (received linearblock.Received) {
 		count := int(crossoverProbability * float64(originalCodeword.Len()))
-		return benchmarking.RandomFlipBitCount(originalCodeword, count)
-	}
-
-	metrics := func(originalMessage, originalCodeword, fixedChannelInducedCodeword mat.SparseVector) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64) {
-		codewordErrors := originalCodeword.HammingDistance(fixedChannelInducedCodeword)
-		message := l.Decode(fixedChannelInducedCodeword)
-		messageErrors := message.HammingDistance(originalMessage)
-		parityErrors := codewordErrors - messageErrors
-
-		percentFixedCodewordErrors = float64(codewordErrors) / float64(l.CodewordLength())
-		percentFixedMessageErrors = float64(messageErrors) / float64(l.MessageLength())
-		percentFixedParityErrors = float64(parityErrors) / float64(l.ParitySymbols())
-		return
+		return linearblock.Received{Bits: benchmarking.RandomFlipBitCount(originalCodeword, count)}
 	}
 
-	return benchmarking.BenchmarkBSCContinueStats(ctx, trials, threads, createMessage, encode, channel, correctionAlg, metrics, checkpoints, previousStats, showProgress)
+	return benchmarking.BenchmarkDecoderContinueStats(ctx, l, trials, threads, createMessage, channel, newDecoder, checkpoints, previousStats, showProgress)
 }
@@ -16,7 +16,6 @@ import (
 	"github.com/nathanhack/ecc/cmd/internal/tools/bsc"
 	"github.com/nathanhack/ecc/linearblock"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bitflipping/harddecision"
-	mat "github.com/nathanhack/sparsemat"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +25,8 @@ var (
 	Threads          uint
 	Verbose          bool
 	MaxIter          uint
+	Layered          bool
+	LayerSize        uint
 	Alpha            float64
 	EtaThreshold     float64
 )
@@ -90,7 +91,7 @@ var DwbfRun = func(cmd *cobra.Command, args []string) {
 
 func typeInfo() string {
 	t := reflect.TypeOf(harddecision.DWBF_F{})
-	return fmt.Sprintf("BSC:%v/%v", t.PkgPath(), t.Name())
+	return fmt.Sprintf("BSC:%v/%v%v", t.PkgPath(), t.Name(), tools.ScheduleInfo(tools.Schedule(Layered), int(LayerSize)))
 }
 
 func min(a, b int) int {
@@ -104,14 +105,18 @@ func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linear
 	checkpointMux := sync.Mutex{}
 	checkpointCount := 0
 
-	correctionAlg := func(originalCodeword, channelInducedCodeword mat.SparseVector) (fixedChannelInducedCodeword mat.SparseVector) {
-		//since this is parallel there is no way to isolate data from one codeword from the next
-		// this alg has internal state
-		alg := &harddecision.DWBF_F{
-			AlphaFactor: Alpha,
-			H:           ecc.H,
+	newDecoder := func() linearblock.Decoder {
+		//this alg has internal state so each thread needs its own
+		return &harddecision.Decoder{
+			Block: ecc,
+			Alg: &harddecision.DWBF_F{
+				AlphaFactor: Alpha,
+				H:           ecc.H,
+			},
+			MaxIter:   int(MaxIter),
+			Schedule:  tools.Schedule(Layered),
+			LayerSize: int(LayerSize),
 		}
-		return harddecision.BitFlipping(alg, ecc.H, channelInducedCodeword, int(MaxIter))
 	}
 
 	numberOfThread := int(Threads)
@@ -145,7 +150,7 @@ trialLoops:
 				}
 				checkpointCount++
 			}
-			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, correctionAlg, data.Stats[p], checkpoint, false)
+			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
 			bar.Add(trialsPerIter)
 		}
 	}
@@ -16,7 +16,6 @@ import (
 	"github.com/nathanhack/ecc/cmd/internal/tools/bsc"
 	"github.com/nathanhack/ecc/linearblock"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bitflipping/harddecision"
-	mat "github.com/nathanhack/sparsemat"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +24,8 @@ var (
 	ErrorProbability []float64
 	Threads          uint
 	MaxIter          uint
+	Layered          bool
+	LayerSize        uint
 )
 
 var GallagerRun = func(cmd *cobra.Command, args []string) {
@@ -86,7 +87,7 @@ var GallagerRun = func(cmd *cobra.Command, args []string) {
 
 func typeInfo() string {
 	t := reflect.TypeOf(harddecision.Gallager{})
-	return fmt.Sprintf("BSC:%v/%v", t.PkgPath(), t.Name())
+	return fmt.Sprintf("BSC:%v/%v%v", t.PkgPath(), t.Name(), tools.ScheduleInfo(tools.Schedule(Layered), int(LayerSize)))
 }
 
 func min(a, b int) int {
@@ -100,13 +101,14 @@ func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linear
 	checkpointMux := sync.Mutex{}
 	checkpointCount := 0
 
-	alg := &harddecision.Gallager{
-		H: ecc.H,
-	}
-
-	correctionAlg := func(originalCodeword, channelInducedCodeword mat.SparseVector) (fixedChannelInducedCodeword mat.SparseVector) {
-		alg.Reset() //must be called before correcting the next codeword
-		return harddecision.BitFlipping(alg, ecc.H, channelInducedCodeword, int(MaxIter))
+	newDecoder := func() linearblock.Decoder {
+		return &harddecision.Decoder{
+			Block:     ecc,
+			Alg:       &harddecision.Gallager{H: ecc.H},
+			MaxIter:   int(MaxIter),
+			Schedule:  tools.Schedule(Layered),
+			LayerSize: int(LayerSize),
+		}
 	}
 
 	numberOfThread := int(Threads)
@@ -140,7 +142,7 @@ trialLoops:
 				}
 				checkpointCount++
 			}
-			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, correctionAlg, data.Stats[p], checkpoint, false)
+			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
 			bar.Add(trialsPerIter)
 		}
 	}
@@ -0,0 +1,175 @@
+package gdbf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bitflipping/harddecision"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Trials           uint
+	ErrorProbability []float64
+	Threads          uint
+	MaxIter          uint
+	Layered          bool
+	LayerSize        uint
+	Threshold        float64
+	Weight           float64
+	Sigma            float64
+)
+
+var GdbfRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) harddecision.BitFlippingAlg {
+		return &harddecision.GDBF{H: ecc.H}
+	})
+}
+
+var MultiGdbfRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) harddecision.BitFlippingAlg {
+		return &harddecision.MultiGDBF{H: ecc.H, Threshold: Threshold}
+	})
+}
+
+var NoisyGdbfRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) harddecision.BitFlippingAlg {
+		return &harddecision.NoisyGDBF{H: ecc.H, Threshold: Threshold, WeightW: Weight, SigmaNoise: Sigma}
+	})
+}
+
+func run(args []string, newAlg func(ecc *linearblock.LinearBlock) harddecision.BitFlippingAlg) {
+	if len(args) != 2 {
+		fmt.Println("requires both ECC_JSON_FILE RESULT_JSON")
+		return
+	}
+
+	//first get the ECC to use
+	ecc, err := tools.LoadLinearBlockECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	alg := newAlg(ecc)
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(alg),
+			ECCInfo:  tools.Md5Sum(ecc.H),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo(alg) {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(alg), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != tools.Md5Sum(ecc.H) {
+		fmt.Printf("csv laoded does not match the ECC")
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, ecc, newAlg, args[1])
+
+	err = tools.SaveResults(args[1], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func typeInfo(alg harddecision.BitFlippingAlg) string {
+	t := reflect.TypeOf(alg).Elem()
+	return fmt.Sprintf("BSC:%v/%v%v", t.PkgPath(), t.Name(), tools.ScheduleInfo(tools.Schedule(Layered), int(LayerSize)))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linearblock.LinearBlock, newAlg func(ecc *linearblock.LinearBlock) harddecision.BitFlippingAlg, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	//these algs keep per codeword state so each thread needs its own,
+	// the decoders are reused so the Tanner graph isn't rebuilt
+	newDecoder := func() linearblock.Decoder {
+		return &harddecision.Decoder{
+			Block:     ecc,
+			Alg:       newAlg(ecc),
+			MaxIter:   int(MaxIter),
+			Schedule:  tools.Schedule(Layered),
+			LayerSize: int(LayerSize),
+		}
+	}
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(ErrorProbability))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range ErrorProbability {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
@@ -0,0 +1,189 @@
+package hardmessage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/hardmessage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Trials           uint
+	ErrorProbability []float64
+	Threads          uint
+	MaxIter          uint
+	Thresholds       map[string]int
+)
+
+var GallagerARun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) (linearblock.Decoder, error) {
+		return &hardmessage.Decoder{Block: ecc, Alg: &hardmessage.GallagerA{H: ecc.H}, MaxIter: int(MaxIter)}, nil
+	})
+}
+
+var GallagerBRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) (linearblock.Decoder, error) {
+		thresholds := make(map[int]int)
+		for d, b := range Thresholds {
+			degree, err := strconv.Atoi(d)
+			if err != nil {
+				return nil, fmt.Errorf("threshold degree must be an integer but found %v", d)
+			}
+			if b < 1 || degree-1 < b {
+				return nil, fmt.Errorf("threshold 1<=b<=%v is required for degree %v but found %v", degree-1, degree, b)
+			}
+			thresholds[degree] = b
+		}
+		return &hardmessage.Decoder{Block: ecc, Alg: &hardmessage.GallagerB{H: ecc.H, Thresholds: thresholds}, MaxIter: int(MaxIter)}, nil
+	})
+}
+
+var MajorityLogicRun = func(cmd *cobra.Command, args []string) {
+	run(args, func(ecc *linearblock.LinearBlock) (linearblock.Decoder, error) {
+		if !hardmessage.Orthogonal(ecc.H) {
+			fmt.Println("warning: the check sums are not orthogonal, majority-logic decoding will be weaker than ⌊J/2⌋")
+		}
+		return &hardmessage.MajorityLogicDecoder{Block: ecc, Alg: &hardmessage.MajorityLogic{H: ecc.H}}, nil
+	})
+}
+
+func run(args []string, newDecoder func(ecc *linearblock.LinearBlock) (linearblock.Decoder, error)) {
+	if len(args) != 2 {
+		fmt.Println("requires both ECC_JSON_FILE RESULT_JSON")
+		return
+	}
+
+	//first get the ECC to use
+	ecc, err := tools.LoadLinearBlockECC(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	alg, err := newDecoder(ecc)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(alg),
+			ECCInfo:  tools.Md5Sum(ecc.H),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo(alg) {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(alg), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != tools.Md5Sum(ecc.H) {
+		fmt.Printf("csv laoded does not match the ECC")
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, ecc, alg, args[1])
+
+	err = tools.SaveResults(args[1], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func typeInfo(alg linearblock.Decoder) string {
+	t := reflect.TypeOf(alg).Elem()
+	switch d := alg.(type) {
+	case *hardmessage.Decoder:
+		t = reflect.TypeOf(d.Alg).Elem()
+	case *hardmessage.MajorityLogicDecoder:
+		t = reflect.TypeOf(d.Alg).Elem()
+	}
+	return fmt.Sprintf("BSC:%v/%v", t.PkgPath(), t.Name())
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, ecc *linearblock.LinearBlock, alg linearblock.Decoder, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	//the decoders only keep the Tanner graph so they can be shared between threads
+	newDecoder := func() linearblock.Decoder {
+		return alg
+	}
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(ErrorProbability))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range ErrorProbability {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = bsc.RunBSC(ctx, ecc, p, min(t, int(Trials)), numberOfThread, newDecoder, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
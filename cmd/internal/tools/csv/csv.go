@@ -15,6 +15,7 @@ import (
 var OutputFile string
 var MessageError bool
 var ParityError bool
+var Iterations bool
 
 var CSVRun = func(cmd *cobra.Command, args []string) {
 	if len(args) < 1 {
@@ -76,6 +77,8 @@ var CSVRun = func(cmd *cobra.Command, args []string) {
 					record[i+1] = fmt.Sprintf("%v", v.ChannelMessageError.Mean)
 				case ParityError:
 					record[i+1] = fmt.Sprintf("%v", v.ChannelParityError.Mean)
+				case Iterations:
+					record[i+1] = fmt.Sprintf("%v", v.Iterations.Mean)
 				default:
 					record[i+1] = fmt.Sprintf("%v", v.ChannelCodewordError.Mean)
 				}
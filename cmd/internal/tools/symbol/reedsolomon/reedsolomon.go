@@ -0,0 +1,185 @@
+package reedsolomon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/cmd/internal/tools"
+	"github.com/nathanhack/ecc/linearblock/reedsolomon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	M                uint
+	N                uint
+	K                uint
+	Trials           uint
+	ErrorProbability []float64
+	Erasures         float64
+	Threads          uint
+)
+
+var ReedSolomonRun = func(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Println("requires RESULT_JSON")
+		return
+	}
+
+	//first create the ECC to use
+	rs, err := reedsolomon.New(int(M), int(N), int(K))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if Erasures < 0 || 1 < Erasures {
+		fmt.Printf("erasures must be in [0,1] but found %v\n", Erasures)
+		return
+	}
+
+	//next we see if the RESULT_JSON exists if so we load it and validate we're running it against the right thing
+	data, err := tools.LoadResults(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	//if data is nil then we create it
+	if data == nil {
+		data = &tools.SimulationStats{
+			TypeInfo: typeInfo(),
+			ECCInfo:  rs.String(),
+			Stats:    make(map[float64]benchmarking.Stats),
+		}
+	}
+
+	//in either case lets validate it
+	if data.TypeInfo != typeInfo() {
+		fmt.Printf("csv loaded does not match the same type expected %v but found %v\n", typeInfo(), data.TypeInfo)
+		return
+	}
+	if data.ECCInfo != rs.String() {
+		fmt.Printf("csv loaded does not match the ECC expected %v but found %v\n", rs, data.ECCInfo)
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		sig := <-sigs
+		fmt.Println()
+		fmt.Println(sig)
+		cancel()
+	}()
+
+	runSimulation(ctx, data, rs, args[0])
+
+	err = tools.SaveResults(args[0], data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// typeInfo keeps results with a different fraction of erasures apart, without erasures nothing is added
+func typeInfo() string {
+	t := reflect.TypeOf(reedsolomon.ReedSolomon{})
+	info := fmt.Sprintf("QSC:%v/%v", t.PkgPath(), t.Name())
+	if Erasures > 0 {
+		info += fmt.Sprintf("+Erasures(%v)", Erasures)
+	}
+	return info
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func runSimulation(ctx context.Context, data *tools.SimulationStats, rs *reedsolomon.ReedSolomon, outputFilename string) {
+	checkpointMux := sync.Mutex{}
+	checkpointCount := 0
+
+	numberOfThread := int(Threads)
+	if numberOfThread == 0 {
+		numberOfThread = runtime.NumCPU()
+	}
+
+	trialsPerIter := numberOfThread * 10
+	bar := pb.StartNew(int(Trials) * len(ErrorProbability))
+trialLoops:
+	for t := 0; t <= int(Trials); t += trialsPerIter {
+		select {
+		case <-ctx.Done():
+			break trialLoops
+		default:
+		}
+
+		for _, p := range ErrorProbability {
+			checkpoint := func(stats benchmarking.Stats) {
+				//we want to save the checkpoint
+				checkpointMux.Lock()
+				defer checkpointMux.Unlock()
+
+				data.Stats[p] = stats
+
+				if checkpointCount%trialsPerIter == 0 {
+					err := tools.SaveResults(outputFilename, data)
+					if err != nil {
+						fmt.Println(err)
+					}
+				}
+				checkpointCount++
+			}
+			data.Stats[p] = RunSymbols(ctx, rs, p, Erasures, min(t, int(Trials)), numberOfThread, data.Stats[p], checkpoint, false)
+			bar.Add(trialsPerIter)
+		}
+	}
+	bar.Finish()
+}
+
+// RunSymbols simulates the code over a q-ary symmetric channel. Each codeword has ⌊errorProbability*n⌋ symbols
+// changed to another value and ⌊erasureProbability*n⌋ other symbols erased, the stats are symbol error probabilities.
+func RunSymbols(ctx context.Context,
+	rs *reedsolomon.ReedSolomon,
+	errorProbability, erasureProbability float64,
+	trials, threads int,
+	previousStats benchmarking.Stats,
+	checkpoints benchmarking.Checkpoints,
+	showProgress bool) benchmarking.Stats {
+	n := rs.CodewordLength()
+	errors := int(errorProbability * float64(n))
+	erasures := min(int(erasureProbability*float64(n)), n-errors)
+
+	createMessage := func(trial int) []int {
+		return benchmarking.RandomSymbols(rs.MessageLength(), rs.SymbolSize())
+	}
+
+	channel := func(codeword []int) ([]int, []int) {
+		return benchmarking.RandomSymbolErrorCount(codeword, rs.SymbolSize(), errors, erasures)
+	}
+
+	codewordRepair := func(channelInducedCodeword []int, erasures []int) ([]int, int) {
+		fixed, _, _ := rs.Decode(channelInducedCodeword, erasures)
+		return fixed, 0
+	}
+
+	metrics := func(originalMessage, originalCodeword, fixedChannelInducedCodeword []int) (percentFixedCodewordErrors, percentFixedMessageErrors, percentFixedParityErrors float64) {
+		k := rs.MessageLength()
+		percentFixedCodewordErrors = float64(benchmarking.HammingDistanceSymbols(originalCodeword, fixedChannelInducedCodeword)) / float64(n)
+		percentFixedMessageErrors = float64(benchmarking.HammingDistanceSymbols(originalMessage, rs.Message(fixedChannelInducedCodeword))) / float64(k)
+		percentFixedParityErrors = float64(benchmarking.HammingDistanceSymbols(originalCodeword[k:], fixedChannelInducedCodeword[k:])) / float64(n-k)
+		return
+	}
+
+	return benchmarking.BenchmarkSymbolsContinueStats(ctx, trials, threads, createMessage, rs.Encode, channel, codewordRepair, metrics, checkpoints, previousStats, showProgress)
+}
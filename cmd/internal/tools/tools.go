@@ -1,15 +1,24 @@
 package tools
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/convolutional"
 	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/ldpc/ira"
+	"github.com/nathanhack/ecc/linearblock/ldpc/peg"
+	"github.com/nathanhack/ecc/linearblock/ldpc/qc"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	"github.com/nathanhack/ecc/linearblock/polar"
+	"github.com/nathanhack/ecc/turbo"
 	mat "github.com/nathanhack/sparsemat"
 )
 
@@ -72,6 +81,81 @@ func Md5Sum(H mat.SparseMat) string {
 	return fmt.Sprintf("%x", m.Sum(nil))
 }
 
+// Schedule returns the message passing schedule selected by the layered flag
+func Schedule(layered bool) messagepassing.Schedule {
+	if layered {
+		return messagepassing.Layered
+	}
+	return messagepassing.Flooding
+}
+
+// ScheduleInfo is appended to a TypeInfo to keep results of different schedules apart.
+// Flooding adds nothing so results created before schedules existed remain valid.
+func ScheduleInfo(schedule messagepassing.Schedule, layerSize int) string {
+	if schedule == messagepassing.Flooding {
+		return ""
+	}
+	return fmt.Sprintf("/%v(%v)", schedule, layerSize)
+}
+
+// OSDInfo is appended to a TypeInfo when an OSD post-processor of the given order is used, a negative order
+// means there isn't one and adds nothing.
+func OSDInfo(order int) string {
+	if order < 0 {
+		return ""
+	}
+	return fmt.Sprintf("+OSD(%v)", order)
+}
+
+// ReadMatrix reads a matrix of integers, one row per line with the entries separated by spaces or commas
+func ReadMatrix(filename string) ([][]int, error) {
+	bs, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]int, 0)
+	for _, line := range strings.Split(string(bs), "\n") {
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == '\r'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+
+		row := make([]int, len(fields))
+		for i, f := range fields {
+			row[i], err = strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("row %v entry %v is not an integer: %v", len(result), i, f)
+			}
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// ParseDistribution converts degree=fraction pairs into a DegreeDistribution, no pairs gives nil
+func ParseDistribution(pairs map[string]string) (peg.DegreeDistribution, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	result := peg.DegreeDistribution{}
+	for d, f := range pairs {
+		degree, err := strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("degree %v is not an integer", d)
+		}
+		fraction, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fraction %v for degree %v is not a number", f, d)
+		}
+		result[degree] = fraction
+	}
+	return result, nil
+}
+
 func LoadLinearBlockECC(filepath string) (*linearblock.LinearBlock, error) {
 	if _, err := os.Stat(filepath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("the ECC_JSON_FILE must exist")
@@ -82,6 +166,28 @@ func LoadLinearBlockECC(filepath string) (*linearblock.LinearBlock, error) {
 		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
 	}
 
+	// QC-LDPC codes are saved as their exponent matrix and are expanded here
+	var compact qc.QC
+	if err := json.Unmarshal(bs, &compact); err == nil && compact.Exponents != nil {
+		ecc, err := compact.LinearBlock(context.Background(), 0)
+		if err != nil {
+			return nil, fmt.Errorf("error while expanding QC-LDPC in file %v: %v\n", filepath, err)
+		}
+		return ecc, nil
+	}
+
+	// IRA codes are saved as their H and encode with the accumulator instead of a generator matrix
+	var accumulated struct {
+		IRA *ira.IRA
+	}
+	err = json.Unmarshal(bs, &accumulated)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+	if accumulated.IRA != nil {
+		return accumulated.IRA.LinearBlock(), nil
+	}
+
 	var ecc linearblock.LinearBlock
 	err = json.Unmarshal(bs, &ecc)
 	if err != nil {
@@ -91,6 +197,76 @@ func LoadLinearBlockECC(filepath string) (*linearblock.LinearBlock, error) {
 	return &ecc, nil
 }
 
+// LoadPolarECC loads the polar code stored alongside its linear block code, see create polar.
+func LoadPolarECC(filepath string) (*polar.Polar, error) {
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("the ECC_JSON_FILE must exist")
+	}
+
+	bs, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+
+	var ecc struct {
+		Polar *polar.Polar
+	}
+	err = json.Unmarshal(bs, &ecc)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+	if ecc.Polar == nil {
+		return nil, fmt.Errorf("the ECC_JSON_FILE %v is not a polar code", filepath)
+	}
+
+	return ecc.Polar, nil
+}
+
+// LoadConvolutionalECC loads the convolutional code stored alongside its linear block code, see create convolutional.
+func LoadConvolutionalECC(filepath string) (*convolutional.Convolutional, error) {
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("the ECC_JSON_FILE must exist")
+	}
+
+	bs, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+
+	var ecc struct {
+		Convolutional *convolutional.Convolutional
+	}
+	err = json.Unmarshal(bs, &ecc)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+	if ecc.Convolutional == nil {
+		return nil, fmt.Errorf("the ECC_JSON_FILE %v is not a convolutional code", filepath)
+	}
+
+	return ecc.Convolutional, nil
+}
+
+// LoadTurboECC loads a turbo code, see create turbo.
+func LoadTurboECC(filepath string) (*turbo.Turbo, error) {
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("the ECC_JSON_FILE must exist")
+	}
+
+	bs, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+
+	var ecc turbo.Turbo
+	err = json.Unmarshal(bs, &ecc)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %v: %v\n", filepath, err)
+	}
+
+	return turbo.New(ecc.ConstraintLength, ecc.Feedback, ecc.Parity, ecc.Interleaver, ecc.N)
+}
+
 func LoadResults(filepath string) (*SimulationStats, error) {
 	if _, err := os.Stat(filepath); os.IsNotExist(err) {
 		return nil, nil
@@ -117,7 +293,7 @@ func SaveResults(filepath string, data *SimulationStats) error {
 
 	err = ioutil.WriteFile(filepath, bs, 0644)
 	if err != nil {
-		fmt.Errorf("error while saving csv to %v: %v\n", filepath, err)
+		return fmt.Errorf("error while saving csv to %v: %v\n", filepath, err)
 	}
 	return nil
 }
@@ -2,10 +2,27 @@ package cmd
 
 import (
 	"github.com/nathanhack/ecc/cmd/internal/tools/bec/simple"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/bitflipping"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/convolutional"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/minsum"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/osd"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/polar"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/reedmuller"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/sumproduct"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bpsk/turbo"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/bch"
 	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/dwbf"
 	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/gallager"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/gdbf"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/golay"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/hardmessage"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/reed"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/syndrome"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/viterbi"
+	"github.com/nathanhack/ecc/cmd/internal/tools/bsc/wbf"
 	"github.com/nathanhack/ecc/cmd/internal/tools/chart"
 	"github.com/nathanhack/ecc/cmd/internal/tools/csv"
+	"github.com/nathanhack/ecc/cmd/internal/tools/symbol/reedsolomon"
 
 	"github.com/spf13/cobra"
 )
@@ -23,7 +40,7 @@ var toolsChansimCmd = &cobra.Command{
 	Use:     "chansim",
 	Aliases: []string{"cs", "c"},
 	Short:   "Channel simulators",
-	Long:    `Channel simulators for linearblock ECCs`,
+	Long:    `Channel simulators for ECCs`,
 }
 
 // toolsLinearblockCmd represents the linearblock command
@@ -34,6 +51,30 @@ var toolsLinearblockCmd = &cobra.Command{
 	Long:    `Channel simulators for linearblock ECCs`,
 }
 
+// toolsConvolutionalCmd represents the convolutional command
+var toolsConvolutionalCmd = &cobra.Command{
+	Use:     "convolutional",
+	Aliases: []string{"conv"},
+	Short:   "Convolutional channel simulators",
+	Long:    `Channel simulators for convolutional codes made by create convolutional`,
+}
+
+// toolsViterbiCmd represents the viterbi command
+var toolsViterbiCmd = &cobra.Command{
+	Use:   "viterbi",
+	Short: "Convolutional channel simulators with Viterbi decoding",
+	Long:  `Channel simulators for convolutional codes made by create convolutional, using Viterbi decoding`,
+}
+
+// toolsReedSolomonCmd represents the reedsolomon command
+var toolsReedSolomonCmd = &cobra.Command{
+	Use:     "reedsolomon RESULT_JSON",
+	Aliases: []string{"rs"},
+	Short:   "A Reed-Solomon symbol channel simulator",
+	Long:    `A q-ary symmetric channel simulator with erasures for Reed-Solomon codes over GF(2^m) with errors-and-erasures decoding, the results are symbol error probabilities`,
+	Run:     reedsolomon.ReedSolomonRun,
+}
+
 // toolsHarddecisionCmd represents the harddecision command
 var toolsHarddecisionCmd = &cobra.Command{
 	Use:     "harddecision",
@@ -75,6 +116,280 @@ var toolsGallagerCmd = &cobra.Command{
 	Run:     gallager.GallagerRun,
 }
 
+// toolsWbfCmd represents the wbf command
+var toolsWbfCmd = &cobra.Command{
+	Use:     "wbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"w"},
+	Short:   "A linearblock BSC simulator with wbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with weighted bit flipping (WBF) based bit flipping algorithm, on the BSC every |y| is 1 so the weights carry no information, see "softdecision bpsk wbf" for the channel reliabilities`,
+	Run:     wbf.WbfRun,
+}
+
+// toolsMwbfCmd represents the mwbf command
+var toolsMwbfCmd = &cobra.Command{
+	Use:     "mwbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"mw"},
+	Short:   "A linearblock BSC simulator with mwbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with modified weighted bit flipping (MWBF) based bit flipping algorithm, on the BSC every |y| is 1 so the weights carry no information, see "softdecision bpsk wbf" for the channel reliabilities`,
+	Run:     wbf.MwbfRun,
+}
+
+// toolsImwbfCmd represents the imwbf command
+var toolsImwbfCmd = &cobra.Command{
+	Use:     "imwbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"imw"},
+	Short:   "A linearblock BSC simulator with imwbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with improved modified weighted bit flipping (IMWBF) based bit flipping algorithm, on the BSC every |y| is 1 so the weights carry no information, see "softdecision bpsk wbf" for the channel reliabilities`,
+	Run:     wbf.ImwbfRun,
+}
+
+// toolsGdbfCmd represents the gdbf command
+var toolsGdbfCmd = &cobra.Command{
+	Use:     "gdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"gd"},
+	Short:   "A linearblock BSC simulator with gdbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with single bit gradient descent bit flipping (GDBF) based bit flipping algorithm`,
+	Run:     gdbf.GdbfRun,
+}
+
+// toolsMultiGdbfCmd represents the mgdbf command
+var toolsMultiGdbfCmd = &cobra.Command{
+	Use:     "mgdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"mgd"},
+	Short:   "A linearblock BSC simulator with mgdbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with multi-bit gradient descent bit flipping (M-GDBF) based bit flipping algorithm`,
+	Run:     gdbf.MultiGdbfRun,
+}
+
+// toolsNoisyGdbfCmd represents the ngdbf command
+var toolsNoisyGdbfCmd = &cobra.Command{
+	Use:     "ngdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"ngd"},
+	Short:   "A linearblock BSC simulator with ngdbf based bit flipping algorithm",
+	Long:    `A linearblock BSC simulator with noisy gradient descent bit flipping (NGDBF) based bit flipping algorithm`,
+	Run:     gdbf.NoisyGdbfRun,
+}
+
+// toolsGallagerACmd represents the gallager-a command
+var toolsGallagerACmd = &cobra.Command{
+	Use:     "gallager-a ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"ga"},
+	Short:   "A linearblock BSC simulator with Gallager-A message passing decoding",
+	Long:    `A linearblock BSC simulator with Gallager-A message passing decoding`,
+	Run:     hardmessage.GallagerARun,
+}
+
+// toolsGallagerBCmd represents the gallager-b command
+var toolsGallagerBCmd = &cobra.Command{
+	Use:     "gallager-b ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"gb"},
+	Short:   "A linearblock BSC simulator with Gallager-B message passing decoding",
+	Long:    `A linearblock BSC simulator with Gallager-B message passing decoding`,
+	Run:     hardmessage.GallagerBRun,
+}
+
+// toolsMajorityLogicCmd represents the majority command
+var toolsMajorityLogicCmd = &cobra.Command{
+	Use:     "majority ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"mlg"},
+	Short:   "A linearblock BSC simulator with one-step majority-logic decoding",
+	Long:    `A linearblock BSC simulator with one-step majority-logic decoding`,
+	Run:     hardmessage.MajorityLogicRun,
+}
+
+// toolsSyndromeCmd represents the syndrome command
+var toolsSyndromeCmd = &cobra.Command{
+	Use:     "syndrome ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"table"},
+	Short:   "A linearblock BSC simulator with syndrome table decoding",
+	Long:    `A linearblock BSC simulator with syndrome table (coset leader) decoding, only practical for small n-k`,
+	Run:     syndrome.SyndromeRun,
+}
+
+// toolsBCHCmd represents the bch command
+var toolsBCHCmd = &cobra.Command{
+	Use:   "bch ECC_JSON_FILE RESULT_JSON",
+	Short: "A BCH BSC simulator with Berlekamp-Massey decoding",
+	Long:  `A BSC simulator for BCH codes (see create linearblock bch) with algebraic Berlekamp-Massey decoding`,
+	Run:   bch.BCHRun,
+}
+
+// toolsReedCmd represents the reed command
+var toolsReedCmd = &cobra.Command{
+	Use:   "reed ECC_JSON_FILE RESULT_JSON",
+	Short: "A Reed-Muller BSC simulator with Reed majority-logic decoding",
+	Long:  `A BSC simulator for Reed-Muller codes (see create linearblock reedmuller) with Reed's majority-logic decoding`,
+	Run:   reed.ReedRun,
+}
+
+// toolsGolayCmd represents the golay command
+var toolsGolayCmd = &cobra.Command{
+	Use:   "golay ECC_JSON_FILE RESULT_JSON",
+	Short: "A Golay BSC simulator with complete decoding",
+	Long:  `A BSC simulator for Golay codes (see create linearblock golay) with complete coset leader decoding, every 3 errors are corrected`,
+	Run:   golay.GolayRun,
+}
+
+// toolsSoftdecisionCmd represents the softdecision command
+var toolsSoftdecisionCmd = &cobra.Command{
+	Use:     "softdecision",
+	Aliases: []string{"soft", "s"},
+	Short:   "Using soft decisions",
+	Long:    `Channel simulators for linearblock ECCs using soft decisions`,
+}
+
+// toolsBpskCmd represents the bpsk command
+var toolsBpskCmd = &cobra.Command{
+	Use:   "bpsk",
+	Short: "A BPSK over AWGN channel simulator",
+	Long:  `A BPSK over additive white gaussian noise channel simulator for linearblock ECCs`,
+}
+
+// toolsSumProductCmd represents the sumproduct command
+var toolsSumProductCmd = &cobra.Command{
+	Use:     "sumproduct ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"sp"},
+	Short:   "A linearblock BPSK simulator with sum-product decoding",
+	Long:    `A linearblock BPSK simulator with log domain sum-product (belief propagation) decoding`,
+	Run:     sumproduct.SumProductRun,
+}
+
+// toolsMinSumCmd represents the minsum command
+var toolsMinSumCmd = &cobra.Command{
+	Use:     "minsum ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"ms"},
+	Short:   "A linearblock BPSK simulator with min-sum decoding",
+	Long:    `A linearblock BPSK simulator with min-sum decoding`,
+	Run:     minsum.MinSumRun,
+}
+
+// toolsNormalizedMinSumCmd represents the nms command
+var toolsNormalizedMinSumCmd = &cobra.Command{
+	Use:     "nms ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"normalized"},
+	Short:   "A linearblock BPSK simulator with normalized min-sum decoding",
+	Long:    `A linearblock BPSK simulator with normalized min-sum decoding`,
+	Run:     minsum.NormalizedMinSumRun,
+}
+
+// toolsOffsetMinSumCmd represents the oms command
+var toolsOffsetMinSumCmd = &cobra.Command{
+	Use:     "oms ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"offset"},
+	Short:   "A linearblock BPSK simulator with offset min-sum decoding",
+	Long:    `A linearblock BPSK simulator with offset min-sum decoding`,
+	Run:     minsum.OffsetMinSumRun,
+}
+
+// toolsOSDCmd represents the osd command
+var toolsOSDCmd = &cobra.Command{
+	Use:   "osd ECC_JSON_FILE RESULT_JSON",
+	Short: "A linearblock BPSK simulator with ordered statistics decoding",
+	Long:  `A linearblock BPSK simulator with order-i ordered statistics decoding (OSD-i)`,
+	Run:   osd.OSDRun,
+}
+
+// toolsBpskWbfCmd represents the bpsk wbf command
+var toolsBpskWbfCmd = &cobra.Command{
+	Use:   "wbf ECC_JSON_FILE RESULT_JSON",
+	Short: "A linearblock BPSK simulator with wbf based bit flipping algorithm",
+	Long:  `A linearblock BPSK simulator with weighted bit flipping (WBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:   bitflipping.WbfRun,
+}
+
+// toolsBpskMwbfCmd represents the bpsk mwbf command
+var toolsBpskMwbfCmd = &cobra.Command{
+	Use:   "mwbf ECC_JSON_FILE RESULT_JSON",
+	Short: "A linearblock BPSK simulator with mwbf based bit flipping algorithm",
+	Long:  `A linearblock BPSK simulator with modified weighted bit flipping (MWBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:   bitflipping.MwbfRun,
+}
+
+// toolsBpskImwbfCmd represents the bpsk imwbf command
+var toolsBpskImwbfCmd = &cobra.Command{
+	Use:   "imwbf ECC_JSON_FILE RESULT_JSON",
+	Short: "A linearblock BPSK simulator with imwbf based bit flipping algorithm",
+	Long:  `A linearblock BPSK simulator with improved modified weighted bit flipping (IMWBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:   bitflipping.ImwbfRun,
+}
+
+// toolsBpskGdbfCmd represents the bpsk gdbf command
+var toolsBpskGdbfCmd = &cobra.Command{
+	Use:     "gdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"gd"},
+	Short:   "A linearblock BPSK simulator with gdbf based bit flipping algorithm",
+	Long:    `A linearblock BPSK simulator with single bit gradient descent bit flipping (GDBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:     bitflipping.GdbfRun,
+}
+
+// toolsBpskMultiGdbfCmd represents the bpsk mgdbf command
+var toolsBpskMultiGdbfCmd = &cobra.Command{
+	Use:     "mgdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"mgd"},
+	Short:   "A linearblock BPSK simulator with mgdbf based bit flipping algorithm",
+	Long:    `A linearblock BPSK simulator with multi-bit gradient descent bit flipping (M-GDBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:     bitflipping.MultiGdbfRun,
+}
+
+// toolsBpskNoisyGdbfCmd represents the bpsk ngdbf command
+var toolsBpskNoisyGdbfCmd = &cobra.Command{
+	Use:     "ngdbf ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"ngd"},
+	Short:   "A linearblock BPSK simulator with ngdbf based bit flipping algorithm",
+	Long:    `A linearblock BPSK simulator with noisy gradient descent bit flipping (NGDBF) based bit flipping algorithm, the channel LLRs are the reliabilities`,
+	Run:     bitflipping.NoisyGdbfRun,
+}
+
+// toolsViterbiBscCmd represents the viterbi bsc command
+var toolsViterbiBscCmd = &cobra.Command{
+	Use:   "bsc ECC_JSON_FILE RESULT_JSON",
+	Short: "A convolutional code BSC simulator with hard decision Viterbi decoding",
+	Long:  `A BSC simulator for convolutional codes made by create convolutional, using hard decision (Hamming distance) Viterbi decoding`,
+	Run:   viterbi.ViterbiRun,
+}
+
+// toolsViterbiBpskCmd represents the viterbi bpsk command
+var toolsViterbiBpskCmd = &cobra.Command{
+	Use:   "bpsk ECC_JSON_FILE RESULT_JSON",
+	Short: "A convolutional code BPSK simulator with soft decision Viterbi decoding",
+	Long:  `A BPSK simulator for convolutional codes made by create convolutional, using soft decision Viterbi decoding`,
+	Run:   convolutional.ViterbiRun,
+}
+
+// toolsBCJRCmd represents the bcjr command
+var toolsBCJRCmd = &cobra.Command{
+	Use:     "bcjr ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"map"},
+	Short:   "A convolutional code BPSK simulator with BCJR decoding",
+	Long:    `A BPSK simulator for convolutional codes made by create convolutional, using BCJR (log-MAP or max-log-MAP) decoding of each message bit`,
+	Run:     convolutional.BCJRRun,
+}
+
+// toolsPolarCmd represents the polar command
+var toolsPolarCmd = &cobra.Command{
+	Use:   "polar ECC_JSON_FILE RESULT_JSON",
+	Short: "A polar code BPSK simulator",
+	Long:  `A BPSK simulator for polar codes made by create polar, using successive cancellation (SC) decoding or SC-list decoding when the list size is >1 (CRC-aided when the code has a CRC)`,
+	Run:   polar.PolarRun,
+}
+
+// toolsTurboCmd represents the turbo command
+var toolsTurboCmd = &cobra.Command{
+	Use:   "turbo ECC_JSON_FILE RESULT_JSON",
+	Short: "A turbo code BPSK simulator",
+	Long:  `A BPSK simulator for turbo codes made by create turbo, using iterative log-MAP or max-log-MAP decoding`,
+	Run:   turbo.TurboRun,
+}
+
+// toolsReedMullerCmd represents the reedmuller command
+var toolsReedMullerCmd = &cobra.Command{
+	Use:     "reedmuller ECC_JSON_FILE RESULT_JSON",
+	Aliases: []string{"rm"},
+	Short:   "A Reed-Muller BPSK simulator with recursive soft decoding",
+	Long:    `A BPSK simulator for Reed-Muller codes (see create linearblock reedmuller) with recursive soft decision decoding over the Plotkin |u|u+v| structure`,
+	Run:     reedmuller.RecursiveRun,
+}
+
 // toolsResultsCmd represents the csv command
 var toolsResultsCmd = &cobra.Command{
 	Use:     "results",
@@ -107,12 +422,47 @@ func init() {
 	toolsCmd.AddCommand(toolsResultsCmd)
 
 	toolsChansimCmd.AddCommand(toolsLinearblockCmd)
+
+	toolsChansimCmd.AddCommand(toolsConvolutionalCmd)
+	toolsConvolutionalCmd.AddCommand(toolsViterbiCmd)
+
+	toolsViterbiCmd.AddCommand(toolsViterbiBscCmd)
+	toolsViterbiBscCmd.Flags().UintVarP(&viterbi.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsViterbiBscCmd.Flags().Float64SliceVarP(&viterbi.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+	toolsViterbiBscCmd.Flags().UintVar(&viterbi.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsViterbiCmd.AddCommand(toolsViterbiBpskCmd)
+	toolsConvolutionalCmd.AddCommand(toolsBCJRCmd)
+	for _, c := range []*cobra.Command{toolsViterbiBpskCmd, toolsBCJRCmd} {
+		c.Flags().UintVarP(&convolutional.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&convolutional.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+		c.Flags().UintVar(&convolutional.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	}
+	toolsBCJRCmd.Flags().BoolVarP(&convolutional.MaxLog, "max-log", "m", false, "use max-log-MAP instead of log-MAP")
+
+	toolsChansimCmd.AddCommand(toolsTurboCmd)
+	toolsTurboCmd.Flags().UintVarP(&turbo.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsTurboCmd.Flags().Float64SliceVarP(&turbo.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+	toolsTurboCmd.Flags().UintVar(&turbo.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	toolsTurboCmd.Flags().UintVarP(&turbo.MaxIter, "iters", "i", 8, "max number of iterations the decoder is allowed")
+	toolsTurboCmd.Flags().BoolVarP(&turbo.MaxLog, "max-log", "m", false, "use max-log-MAP instead of log-MAP")
+
+	toolsChansimCmd.AddCommand(toolsReedSolomonCmd)
+	toolsReedSolomonCmd.Flags().UintVarP(&reedsolomon.M, "m", "m", 8, "the symbol size in bits, symbols are in GF(2^m)")
+	toolsReedSolomonCmd.Flags().UintVarP(&reedsolomon.N, "codeword", "n", 255, "the codeword size in symbols <=2^m-1, smaller is a shortened code")
+	toolsReedSolomonCmd.Flags().UintVarP(&reedsolomon.K, "message", "k", 223, "the message size in symbols <codeword")
+	toolsReedSolomonCmd.Flags().UintVarP(&reedsolomon.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsReedSolomonCmd.Flags().Float64SliceVarP(&reedsolomon.ErrorProbability, "probability", "p", []float64{0.01, 0.02, 0.04, 0.06, 0.08, 0.10, 0.12, 0.14, 0.16}, "probability of symbol errors to test [0, 1]")
+	toolsReedSolomonCmd.Flags().Float64VarP(&reedsolomon.Erasures, "erasures", "e", 0, "probability of symbol erasures [0, 1], in addition to the errors")
+	toolsReedSolomonCmd.Flags().UintVar(&reedsolomon.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
 	toolsLinearblockCmd.AddCommand(toolsHarddecisionCmd)
 
 	toolsHarddecisionCmd.AddCommand(toolsBecCmd)
 	toolsBecCmd.Flags().UintVarP(&simple.Trials, "trials", "t", 1_000_000, "the number of trials per step")
 	toolsBecCmd.Flags().Float64SliceVarP(&simple.ErrorProbability, "probability", "p", []float64{0.01, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 0.99}, "probability of erasure [0, 1)")
 	toolsBecCmd.Flags().UintVar(&simple.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	toolsBecCmd.Flags().UintVarP(&simple.MaxIter, "iters", "i", 0, "max number of flips the decoder is allowed (0 means the codeword length + 1)")
+	toolsBecCmd.Flags().StringVarP(&simple.Decoder, "decoder", "d", "peeling", "the decoder to use: peeling, ml (maximum-likelihood elimination), or hybrid (peeling then ml)")
 
 	toolsHarddecisionCmd.AddCommand(toolsBscCmd)
 
@@ -123,6 +473,8 @@ func init() {
 	toolsDwbfCmd.Flags().UintVarP(&dwbf.MaxIter, "iters", "i", 20, "max number of iterations the bitflip algorithm is allowed")
 	toolsDwbfCmd.Flags().Float64VarP(&dwbf.Alpha, "alpha", "a", .5, "hyperparameter 0<α<1")
 	toolsDwbfCmd.Flags().Float64VarP(&dwbf.EtaThreshold, "eta", "e", 0.0, "hyperparameter η threshold: no requirement but frequently 0.0 is a good value")
+	toolsDwbfCmd.Flags().BoolVarP(&dwbf.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+	toolsDwbfCmd.Flags().UintVar(&dwbf.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
 
 	toolsBscCmd.AddCommand(toolsGallagerCmd)
 
@@ -130,11 +482,129 @@ func init() {
 	toolsGallagerCmd.Flags().Float64SliceVarP(&gallager.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
 	toolsGallagerCmd.Flags().UintVar(&gallager.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
 	toolsGallagerCmd.Flags().UintVarP(&gallager.MaxIter, "iters", "i", 20, "max number of iterations the bitflip algorithm is allowed")
+	toolsGallagerCmd.Flags().BoolVarP(&gallager.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+	toolsGallagerCmd.Flags().UintVar(&gallager.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+
+	for _, c := range []*cobra.Command{toolsWbfCmd, toolsMwbfCmd, toolsImwbfCmd} {
+		toolsBscCmd.AddCommand(c)
+		c.Flags().UintVarP(&wbf.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&wbf.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+		c.Flags().UintVar(&wbf.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+		c.Flags().UintVarP(&wbf.MaxIter, "iters", "i", 20, "max number of iterations the bitflip algorithm is allowed")
+		c.Flags().BoolVarP(&wbf.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+		c.Flags().UintVar(&wbf.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+	}
+	toolsMwbfCmd.Flags().Float64VarP(&wbf.Alpha, "alpha", "a", .2, "hyperparameter α>=0 weighting the bit's own reliability")
+	toolsImwbfCmd.Flags().Float64VarP(&wbf.Alpha, "alpha", "a", .2, "hyperparameter α>=0 weighting the bit's own reliability")
+
+	for _, c := range []*cobra.Command{toolsGdbfCmd, toolsMultiGdbfCmd, toolsNoisyGdbfCmd} {
+		toolsBscCmd.AddCommand(c)
+		c.Flags().UintVarP(&gdbf.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&gdbf.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+		c.Flags().UintVar(&gdbf.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+		c.Flags().UintVarP(&gdbf.MaxIter, "iters", "i", 20, "max number of iterations the bitflip algorithm is allowed")
+		c.Flags().BoolVarP(&gdbf.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+		c.Flags().UintVar(&gdbf.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+	}
+	toolsMultiGdbfCmd.Flags().Float64Var(&gdbf.Threshold, "threshold", -.5, "hyperparameter θ<0, bits with an inversion function below θ are flipped")
+	toolsNoisyGdbfCmd.Flags().Float64Var(&gdbf.Threshold, "threshold", -.5, "hyperparameter θ, bits with an inversion function below θ are flipped")
+	toolsNoisyGdbfCmd.Flags().Float64VarP(&gdbf.Weight, "weight", "w", 1, "hyperparameter w>0 weighting the syndrome term")
+	toolsNoisyGdbfCmd.Flags().Float64VarP(&gdbf.Sigma, "sigma", "s", .5, "hyperparameter σ>=0 the standard deviation of the perturbation noise")
+
+	for _, c := range []*cobra.Command{toolsGallagerACmd, toolsGallagerBCmd, toolsMajorityLogicCmd} {
+		toolsBscCmd.AddCommand(c)
+		c.Flags().UintVarP(&hardmessage.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&hardmessage.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+		c.Flags().UintVar(&hardmessage.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	}
+	toolsGallagerACmd.Flags().UintVarP(&hardmessage.MaxIter, "iters", "i", 20, "max number of iterations the decoder is allowed")
+	toolsGallagerBCmd.Flags().UintVarP(&hardmessage.MaxIter, "iters", "i", 20, "max number of iterations the decoder is allowed")
+	toolsGallagerBCmd.Flags().StringToIntVarP(&hardmessage.Thresholds, "thresholds", "b", map[string]int{}, "vote threshold b per variable node degree as degree=b (ex: 4=2,5=3), degrees not listed use a simple majority")
+
+	toolsBscCmd.AddCommand(toolsSyndromeCmd)
+	toolsSyndromeCmd.Flags().UintVarP(&syndrome.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsSyndromeCmd.Flags().Float64SliceVarP(&syndrome.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+	toolsSyndromeCmd.Flags().UintVar(&syndrome.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsBscCmd.AddCommand(toolsBCHCmd)
+	toolsBCHCmd.Flags().UintVarP(&bch.Distance, "distance", "d", 5, "the designed distance the BCH code was created with")
+	toolsBCHCmd.Flags().UintVarP(&bch.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsBCHCmd.Flags().Float64SliceVarP(&bch.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+	toolsBCHCmd.Flags().UintVar(&bch.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsBscCmd.AddCommand(toolsReedCmd)
+	toolsReedCmd.Flags().UintVarP(&reed.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsReedCmd.Flags().Float64SliceVarP(&reed.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+	toolsReedCmd.Flags().UintVar(&reed.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsBscCmd.AddCommand(toolsGolayCmd)
+	toolsGolayCmd.Flags().UintVarP(&golay.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsGolayCmd.Flags().Float64SliceVarP(&golay.ErrorProbability, "probability", "p", []float64{0.01, 0.05, 0.10, 0.15, 0.20, 0.25, 0.30, 0.35, 0.40, 0.45, 0.50}, "probability of crossover errors to test [0, 0.5]")
+	toolsGolayCmd.Flags().UintVar(&golay.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsLinearblockCmd.AddCommand(toolsSoftdecisionCmd)
+	toolsSoftdecisionCmd.AddCommand(toolsBpskCmd)
+
+	toolsBpskCmd.AddCommand(toolsSumProductCmd)
+	toolsSumProductCmd.Flags().UintVarP(&sumproduct.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsSumProductCmd.Flags().Float64SliceVarP(&sumproduct.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+	toolsSumProductCmd.Flags().UintVar(&sumproduct.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	toolsSumProductCmd.Flags().UintVarP(&sumproduct.MaxIter, "iters", "i", 50, "max number of iterations the decoder is allowed")
+	toolsSumProductCmd.Flags().BoolVarP(&sumproduct.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+	toolsSumProductCmd.Flags().UintVar(&sumproduct.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+	toolsSumProductCmd.Flags().IntVar(&sumproduct.OSDOrder, "osd", -1, "order of the OSD post-processor used when decoding fails to converge (-1 disables it)")
+
+	for _, c := range []*cobra.Command{toolsMinSumCmd, toolsNormalizedMinSumCmd, toolsOffsetMinSumCmd} {
+		toolsBpskCmd.AddCommand(c)
+		c.Flags().UintVarP(&minsum.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&minsum.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+		c.Flags().UintVar(&minsum.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+		c.Flags().UintVarP(&minsum.MaxIter, "iters", "i", 50, "max number of iterations the decoder is allowed")
+		c.Flags().BoolVarP(&minsum.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+		c.Flags().UintVar(&minsum.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+		c.Flags().IntVar(&minsum.OSDOrder, "osd", -1, "order of the OSD post-processor used when decoding fails to converge (-1 disables it)")
+	}
+	toolsNormalizedMinSumCmd.Flags().Float64VarP(&minsum.Alpha, "alpha", "a", .8, "hyperparameter normalization factor 0<α<=1")
+	toolsOffsetMinSumCmd.Flags().Float64VarP(&minsum.BetaOffset, "beta", "b", .25, "hyperparameter offset β>=0")
+
+	toolsBpskCmd.AddCommand(toolsOSDCmd)
+	toolsOSDCmd.Flags().UintVarP(&osd.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsOSDCmd.Flags().Float64SliceVarP(&osd.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+	toolsOSDCmd.Flags().UintVar(&osd.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	toolsOSDCmd.Flags().UintVarP(&osd.Order, "order", "o", 2, "the order i of OSD-i, the maximum number of most reliable bits flipped per test pattern")
+
+	for _, c := range []*cobra.Command{toolsBpskWbfCmd, toolsBpskMwbfCmd, toolsBpskImwbfCmd, toolsBpskGdbfCmd, toolsBpskMultiGdbfCmd, toolsBpskNoisyGdbfCmd} {
+		toolsBpskCmd.AddCommand(c)
+		c.Flags().UintVarP(&bitflipping.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+		c.Flags().Float64SliceVarP(&bitflipping.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+		c.Flags().UintVar(&bitflipping.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+		c.Flags().UintVarP(&bitflipping.MaxIter, "iters", "i", 20, "max number of iterations the bitflip algorithm is allowed")
+		c.Flags().BoolVarP(&bitflipping.Layered, "layered", "l", false, "use a layered schedule instead of flooding")
+		c.Flags().UintVar(&bitflipping.LayerSize, "layer-size", 1, "the number of check nodes (rows) per layer when layered (circulant size for QC codes)")
+	}
+	toolsBpskMwbfCmd.Flags().Float64VarP(&bitflipping.Alpha, "alpha", "a", .2, "hyperparameter α>=0 weighting the bit's own reliability")
+	toolsBpskImwbfCmd.Flags().Float64VarP(&bitflipping.Alpha, "alpha", "a", .2, "hyperparameter α>=0 weighting the bit's own reliability")
+	toolsBpskMultiGdbfCmd.Flags().Float64Var(&bitflipping.Threshold, "threshold", -.5, "hyperparameter θ<0, bits with an inversion function below θ are flipped")
+	toolsBpskNoisyGdbfCmd.Flags().Float64Var(&bitflipping.Threshold, "threshold", -.5, "hyperparameter θ, bits with an inversion function below θ are flipped")
+	toolsBpskNoisyGdbfCmd.Flags().Float64VarP(&bitflipping.Weight, "weight", "w", 1, "hyperparameter w>0 weighting the syndrome term")
+	toolsBpskNoisyGdbfCmd.Flags().Float64VarP(&bitflipping.Sigma, "sigma", "s", .5, "hyperparameter σ>=0 the standard deviation of the perturbation noise")
+
+	toolsBpskCmd.AddCommand(toolsReedMullerCmd)
+	toolsReedMullerCmd.Flags().UintVarP(&reedmuller.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsReedMullerCmd.Flags().Float64SliceVarP(&reedmuller.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+	toolsReedMullerCmd.Flags().UintVar(&reedmuller.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+
+	toolsBpskCmd.AddCommand(toolsPolarCmd)
+	toolsPolarCmd.Flags().UintVarP(&polar.Trials, "trials", "t", 1_000_000, "the number of trials per step")
+	toolsPolarCmd.Flags().Float64SliceVarP(&polar.EbN0, "ebn0", "e", []float64{0.5, 1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0}, "the E_b/N_0 (linear, not dB) values to test")
+	toolsPolarCmd.Flags().UintVar(&polar.Threads, "threads", 0, "number of threads to use (0 means to use the # of threads equal to the # of CPUs)")
+	toolsPolarCmd.Flags().UintVarP(&polar.ListSize, "list", "l", 1, "the list size L of the SC-list decoder, 1 is the SC decoder")
 
 	toolsResultsCmd.AddCommand(toolsCSVCmd)
 	toolsCSVCmd.Flags().StringVarP(&csv.OutputFile, "output", "o", "results.csv", "filename of the combined csv")
 	toolsCSVCmd.Flags().BoolVarP(&csv.MessageError, "message", "m", false, "outputs the MessageError instead of CodewordError or ParityError")
 	toolsCSVCmd.Flags().BoolVarP(&csv.ParityError, "parity", "p", false, "outputs the ParityError instead of CodewordError or MessageError")
+	toolsCSVCmd.Flags().BoolVarP(&csv.Iterations, "iterations", "i", false, "outputs the average number of decoder iterations instead of an error rate")
 
 	toolsResultsCmd.AddCommand(toolsChartCmd)
 	toolsChartCmd.Flags().StringVarP(&chart.OutputFile, "output", "o", "results.html", "filename of the combined results in a html page")
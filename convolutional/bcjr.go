@@ -0,0 +1,156 @@
+package convolutional
+
+import (
+	"fmt"
+	"math"
+
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// BCJR is the maximum a posteriori (MAP) bit decoder from "Optimal Decoding of Linear Codes for Minimizing
+// Symbol Error Rate" by Bahl, Cocke, Jelinek and Raviv, run in the log domain (log-MAP). With MaxLog the
+// Jacobian logarithm max*(a,b) = max(a,b)+ln(1+exp(-|a-b|)) is approximated by max(a,b) (max-log-MAP).
+// Tail-biting codes don't have a known starting state, so the forward and backward recursions are first
+// run once around the block from uniform states and their ends are used as the starting states.
+type BCJR struct {
+	Code   *Convolutional
+	MaxLog bool
+}
+
+// Decode returns the a posteriori LLRs of the message bits for the channel LLRs,
+// where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)). A negative LLR decides a 1.
+func (b *BCJR) Decode(channelLLR mat2.Vector) (messageLLR mat2.Vector) {
+	llr := depuncture(b.Code, channelLLR)
+	return mat2.NewVecDense(b.Code.Length, b.Code.bcjr(llr, nil, b.MaxLog))
+}
+
+// APosteriori returns the a posteriori LLRs of the message bits given the LLRs of every output before puncturing,
+// output j of step t is at t*n+j with 0 for the punctured ones, and the a priori LLRs of the message bits, nil when
+// there are none. It's the soft-in soft-out (SISO) decoder used by iterative decoders, the extrinsic LLRs are the a
+// posteriori LLRs less the a priori and, for systematic codes, the systematic output's LLRs.
+func (b *BCJR) APosteriori(streamLLR, aPriori []float64) []float64 {
+	if b.Code == nil {
+		panic("convolutional code must be set before decoding")
+	}
+	b.Code.init()
+	if len(streamLLR) != b.Code.trellis*len(b.Code.Generators) {
+		panic(fmt.Sprintf("stream LLR length == %v required but found %v", b.Code.trellis*len(b.Code.Generators), len(streamLLR)))
+	}
+	if aPriori != nil && len(aPriori) != b.Code.Length {
+		panic(fmt.Sprintf("a priori LLR length == %v required but found %v", b.Code.Length, len(aPriori)))
+	}
+	return b.Code.bcjr(streamLLR, aPriori, b.MaxLog)
+}
+
+func (c *Convolutional) bcjr(llr, aPriori []float64, maxLog bool) []float64 {
+	states := c.States()
+	n := len(c.Generators)
+	maxStar := jacobian
+	if maxLog {
+		maxStar = math.Max
+	}
+
+	gamma := func(t, s, b int) float64 {
+		result := branch(llr[t*n:(t+1)*n], c.output[s][b])
+		if aPriori != nil && t < c.Length {
+			result += float64(1-2*b) * aPriori[t] / 2
+		}
+		return result
+	}
+
+	forward := func(alpha []float64, t int) []float64 {
+		result := filled(states, math.Inf(-1))
+		for s, a := range alpha {
+			if math.IsInf(a, -1) {
+				continue
+			}
+			first, last := c.inputs(t, s)
+			for b := first; b <= last; b++ {
+				ns := c.next[s][b]
+				result[ns] = maxStar(result[ns], a+gamma(t, s, b))
+			}
+		}
+		return normalize(result)
+	}
+	backward := func(beta []float64, t int) []float64 {
+		result := filled(states, math.Inf(-1))
+		for s := range result {
+			first, last := c.inputs(t, s)
+			for b := first; b <= last; b++ {
+				result[s] = maxStar(result[s], beta[c.next[s][b]]+gamma(t, s, b))
+			}
+		}
+		return normalize(result)
+	}
+
+	start := filled(states, math.Inf(-1))
+	start[0] = 0
+	end := append([]float64{}, start...)
+	if c.Termination == TailBiting {
+		start = filled(states, 0)
+		for t := 0; t < c.trellis; t++ {
+			start = forward(start, t)
+		}
+		end = filled(states, 0)
+		for t := c.trellis - 1; t >= 0; t-- {
+			end = backward(end, t)
+		}
+	}
+
+	alpha := make([][]float64, c.trellis+1)
+	alpha[0] = start
+	for t := 0; t < c.trellis; t++ {
+		alpha[t+1] = forward(alpha[t], t)
+	}
+	beta := end
+	result := make([]float64, c.Length)
+	for t := c.trellis - 1; t >= 0; t-- {
+		if t < c.Length {
+			likelihood := [2]float64{math.Inf(-1), math.Inf(-1)}
+			for s, a := range alpha[t] {
+				if math.IsInf(a, -1) {
+					continue
+				}
+				for b := 0; b < 2; b++ {
+					likelihood[b] = maxStar(likelihood[b], a+gamma(t, s, b)+beta[c.next[s][b]])
+				}
+			}
+			result[t] = likelihood[0] - likelihood[1]
+		}
+		beta = backward(beta, t)
+	}
+	return result
+}
+
+// jacobian is max*(a,b) = ln(exp(a)+exp(b))
+func jacobian(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	return math.Max(a, b) + math.Log1p(math.Exp(-math.Abs(a-b)))
+}
+
+// normalize subtracts the largest value to keep the metrics from growing without bound
+func normalize(values []float64) []float64 {
+	largest := math.Inf(-1)
+	for _, v := range values {
+		largest = math.Max(largest, v)
+	}
+	if !math.IsInf(largest, 0) {
+		for i := range values {
+			values[i] -= largest
+		}
+	}
+	return values
+}
+
+func filled(length int, value float64) []float64 {
+	result := make([]float64, length)
+	for i := range result {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,291 @@
+package convolutional
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MaxConstraintLength is the largest constraint length supported, the trellis has 2^(K-1) states.
+const MaxConstraintLength = 16
+
+// Termination is how the trellis of a block is started and ended.
+type Termination string
+
+const (
+	// ZeroTail starts in the zero state and appends K-1 zeros to the message to return to it.
+	ZeroTail Termination = "zero"
+	// TailBiting starts in the state of the last K-1 message bits so it ends in the state it started
+	// in, avoiding the rate loss of the tail.
+	TailBiting Termination = "tailbiting"
+)
+
+// Convolutional is a rate 1/n convolutional code used as a block code of Length message bits.
+// Generator j is a polynomial over the last K inputs in the usual octal convention, the most significant
+// bit (bit K-1) taps the current input and bit 0 the oldest, so the K=3 (7,5) code is Generators []int{07, 05}.
+// The n outputs of each step are sent in order then punctured by the Puncture pattern, if any.
+//
+// With a Feedback polynomial the code is recursive, the register holds w_t = u_t+Σ_{i>=1} f_i w_{t-i} instead of
+// the inputs and the generators tap it. Using the feedback polynomial as a generator outputs u_t, so the recursive
+// systematic convolutional (RSC) code (1, 15/13) is Generators []int{013, 015} with Feedback 013.
+type Convolutional struct {
+	ConstraintLength int         // K: the number of inputs each output depends on, the memory is K-1
+	Generators       []int       // g_j: the generator polynomials, one per output
+	Feedback         int         // f: the feedback polynomial of a recursive code, bit K-1 must be set, 0 for a feedforward code
+	Termination      Termination // how the trellis is started and ended
+	Puncture         [][]int     // the puncturing pattern, Puncture[j][t%period] is 1 when output j of step t is sent, nil sends everything
+	Length           int         // L: the number of message bits in a block
+
+	once    sync.Once
+	next    [][2]int // next[s][b] is the state after input b in state s
+	tail    []int    // tail[s] is the input that shifts a zero into the register in state s
+	output  [][2]int // output[s][b] has bit j set when output j is 1 for input b in state s
+	kept    []int    // the positions of the unpunctured bits in the steps*n output stream
+	trellis int      // the number of trellis steps in a block
+}
+
+// New creates the feedforward convolutional code and checks that it's valid, see Convolutional.
+func New(constraintLength int, generators []int, termination Termination, puncture [][]int, length int) (*Convolutional, error) {
+	return NewRecursive(constraintLength, generators, 0, termination, puncture, length)
+}
+
+// NewRecursive creates the convolutional code with the feedback polynomial, 0 for a feedforward code,
+// and checks that it's valid, see Convolutional.
+func NewRecursive(constraintLength int, generators []int, feedback int, termination Termination, puncture [][]int, length int) (*Convolutional, error) {
+	c := &Convolutional{
+		ConstraintLength: constraintLength,
+		Generators:       generators,
+		Feedback:         feedback,
+		Termination:      termination,
+		Puncture:         puncture,
+		Length:           length,
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Convolutional) validate() error {
+	k := c.ConstraintLength
+	if k < 2 || MaxConstraintLength < k {
+		return fmt.Errorf("constraint length must be 2<=K<=%v but found %v", MaxConstraintLength, k)
+	}
+	if len(c.Generators) < 1 {
+		return fmt.Errorf("at least one generator polynomial is required")
+	}
+	current, oldest := false, false
+	for _, g := range c.Generators {
+		if g <= 0 || 1<<k <= g {
+			return fmt.Errorf("generator polynomials must be in [1,%o] (octal) for K=%v but found %o", 1<<k-1, k, g)
+		}
+		current = current || g&(1<<(k-1)) != 0
+		oldest = oldest || g&1 != 0
+	}
+	if c.Feedback != 0 {
+		if c.Feedback < 1<<(k-1) || 1<<k <= c.Feedback {
+			return fmt.Errorf("the feedback polynomial must be in [%o,%o] (octal) for K=%v but found %o", 1<<(k-1), 1<<k-1, k, c.Feedback)
+		}
+		current = true
+		oldest = oldest || c.Feedback&1 != 0
+	}
+	if !current || !oldest {
+		return fmt.Errorf("the generator polynomials %o must tap both the current and the oldest input for K=%v", c.Generators, k)
+	}
+
+	switch c.Termination {
+	case ZeroTail:
+		if c.Length < 1 {
+			return fmt.Errorf("length must be >=1 but found %v", c.Length)
+		}
+	case TailBiting:
+		if c.Feedback != 0 {
+			return fmt.Errorf("tail-biting is only supported for feedforward codes")
+		}
+		if c.Length < k-1 {
+			return fmt.Errorf("tail-biting requires a length >=K-1=%v but found %v", k-1, c.Length)
+		}
+	default:
+		return fmt.Errorf("termination must be %v or %v but found %v", ZeroTail, TailBiting, c.Termination)
+	}
+
+	if len(c.Puncture) > 0 {
+		if len(c.Puncture) != len(c.Generators) {
+			return fmt.Errorf("the puncturing pattern must have a row per generator (%v) but found %v", len(c.Generators), len(c.Puncture))
+		}
+		sent := 0
+		for _, row := range c.Puncture {
+			if len(row) == 0 || len(row) != len(c.Puncture[0]) {
+				return fmt.Errorf("the puncturing pattern rows must all have the same period >=1")
+			}
+			for _, p := range row {
+				if p != 0 && p != 1 {
+					return fmt.Errorf("the puncturing pattern must only contain 0 or 1 but found %v", p)
+				}
+				sent += p
+			}
+		}
+		if sent == 0 {
+			return fmt.Errorf("the puncturing pattern must send at least one bit")
+		}
+	}
+	return nil
+}
+
+func (c *Convolutional) init() {
+	c.once.Do(func() {
+		if err := c.validate(); err != nil {
+			panic(err)
+		}
+
+		states := c.States()
+		c.next = make([][2]int, states)
+		c.tail = make([]int, states)
+		c.output = make([][2]int, states)
+		for s := 0; s < states; s++ {
+			// the state holds the previous K-1 register bits, the newest at bit K-2, which is where the feedback taps them
+			c.tail[s] = bits.OnesCount(uint(c.Feedback&s)) & 1
+			for b := 0; b < 2; b++ {
+				// the register holds the current bit at bit K-1 and the state below it
+				register := (b^c.tail[s])<<(c.ConstraintLength-1) | s
+				c.next[s][b] = register >> 1
+				for j, g := range c.Generators {
+					c.output[s][b] |= (bits.OnesCount(uint(register&g)) & 1) << j
+				}
+			}
+		}
+
+		c.trellis = c.Length
+		if c.Termination == ZeroTail {
+			c.trellis += c.ConstraintLength - 1
+		}
+		n := len(c.Generators)
+		c.kept = make([]int, 0, c.trellis*n)
+		for t := 0; t < c.trellis; t++ {
+			for j := 0; j < n; j++ {
+				if len(c.Puncture) == 0 || c.Puncture[j][t%len(c.Puncture[j])] == 1 {
+					c.kept = append(c.kept, t*n+j)
+				}
+			}
+		}
+	})
+}
+
+func (c *Convolutional) String() string {
+	puncture := ""
+	if len(c.Puncture) > 0 {
+		puncture = fmt.Sprintf("+Puncture%v", c.Puncture)
+	}
+	feedback := ""
+	if c.Feedback != 0 {
+		feedback = fmt.Sprintf("/%o", c.Feedback)
+	}
+	return fmt.Sprintf("Convolutional(K=%v,%o%v,%v,L=%v)%v", c.ConstraintLength, c.Generators, feedback, c.Termination, c.Length, puncture)
+}
+
+// States is the number of trellis states 2^(K-1).
+func (c *Convolutional) States() int {
+	return 1 << (c.ConstraintLength - 1)
+}
+
+// MessageLength is L.
+func (c *Convolutional) MessageLength() int {
+	return c.Length
+}
+
+// CodewordLength is the number of bits sent for a block after puncturing.
+func (c *Convolutional) CodewordLength() int {
+	c.init()
+	return len(c.kept)
+}
+
+// CodeRate is the message length over the codeword length, including the tail.
+func (c *Convolutional) CodeRate() float64 {
+	return float64(c.MessageLength()) / float64(c.CodewordLength())
+}
+
+// start returns the starting state of the message, zero unless it's tail-biting
+func (c *Convolutional) start(message []int) int {
+	state := 0
+	if c.Termination == TailBiting {
+		for _, b := range message[len(message)-(c.ConstraintLength-1):] {
+			state = c.next[state][b]
+		}
+	}
+	return state
+}
+
+// Encode returns the punctured codeword of the message.
+func (c *Convolutional) Encode(message mat.SparseVector) (codeword mat.SparseVector) {
+	c.init()
+	if message.Len() != c.Length {
+		panic(fmt.Sprintf("message length == %v required but found %v", c.Length, message.Len()))
+	}
+
+	inputs := make([]int, c.Length)
+	for _, i := range message.NonzeroArray() {
+		inputs[i] = 1
+	}
+
+	n := len(c.Generators)
+	stream := make([]int, c.trellis*n)
+	state := c.start(inputs)
+	for t := 0; t < c.trellis; t++ {
+		b := c.tail[state]
+		if t < c.Length {
+			b = inputs[t]
+		}
+		for j := 0; j < n; j++ {
+			stream[t*n+j] = (c.output[state][b] >> j) & 1
+		}
+		state = c.next[state][b]
+	}
+
+	codeword = mat.CSRVec(len(c.kept))
+	for i, p := range c.kept {
+		if stream[p] == 1 {
+			codeword.Set(i, 1)
+		}
+	}
+	return codeword
+}
+
+// LinearBlock returns the equivalent linear block code so the code can be simulated like any other linear block
+// code. The generator matrix has the codewords of the unit messages as rows, its parity check matrix comes from
+// the generator matrix of the dual code, which is the linear block code that has it as a parity check matrix.
+func (c *Convolutional) LinearBlock(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	c.init()
+	n := len(c.kept)
+
+	G := mat.CSRMat(c.Length, n)
+	for i := 0; i < c.Length; i++ {
+		unit := mat.CSRVec(c.Length)
+		unit.Set(i, 1)
+		G.SetRow(i, c.Encode(unit))
+	}
+
+	dual := linearblock.SystematicLinearBlock(ctx, G, threads)
+	if dual == nil {
+		return nil, fmt.Errorf("unable to create the dual code")
+	}
+	if dual.MessageLength() != n-c.Length {
+		return nil, fmt.Errorf("the generator matrix of %v is not full rank", c)
+	}
+
+	H := mat.CSRMat(dual.MessageLength(), n)
+	for i := 0; i < dual.MessageLength(); i++ {
+		unit := mat.CSRVec(dual.MessageLength())
+		unit.Set(i, 1)
+		H.SetRow(i, dual.Encode(unit))
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+	return result, nil
+}
@@ -0,0 +1,375 @@
+package convolutional
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func fromBits(bits []int) mat.SparseVector {
+	return toVector(bits)
+}
+
+func TestConvolutional_Encode(t *testing.T) {
+	tests := []struct {
+		code     *Convolutional
+		message  []int
+		expected []int
+	}{
+		{
+			&Convolutional{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Length: 4},
+			[]int{1, 0, 1, 1},
+			[]int{1, 1, 1, 0, 0, 0, 0, 1, 0, 1, 1, 1},
+		},
+		{
+			// rate 2/3 by sending the second output every other step
+			&Convolutional{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Puncture: [][]int{{1, 1}, {1, 0}}, Length: 4},
+			[]int{1, 0, 1, 1},
+			[]int{1, 1, 1, 0, 0, 0, 0, 1, 1},
+		},
+		{
+			// starts in the state of the last two message bits (1,1)
+			&Convolutional{ConstraintLength: 3, Generators: []int{07, 05}, Termination: TailBiting, Length: 4},
+			[]int{1, 0, 1, 1},
+			[]int{1, 0, 0, 1, 0, 0, 0, 1},
+		},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			expected := fromBits(test.expected)
+			if actual := test.code.Encode(fromBits(test.message)); !actual.Equals(expected) {
+				t.Fatalf("expected %v but found %v", expected, actual)
+			}
+			if test.code.CodewordLength() != len(test.expected) {
+				t.Fatalf("expected codeword length %v but found %v", len(test.expected), test.code.CodewordLength())
+			}
+		})
+	}
+}
+
+func TestConvolutional_TailBitingIsCyclic(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	code, err := New(7, []int{0171, 0133}, TailBiting, nil, 20)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		message := randomMessage(random, code.Length)
+		shifted := mat.CSRVec(code.Length)
+		for j := 0; j < code.Length; j++ {
+			shifted.Set((j+1)%code.Length, message.At(j))
+		}
+
+		codeword := code.Encode(message)
+		expected := mat.CSRVec(codeword.Len())
+		for j := 0; j < codeword.Len(); j++ {
+			expected.Set((j+2)%codeword.Len(), codeword.At(j))
+		}
+		if actual := code.Encode(shifted); !actual.Equals(expected) {
+			t.Fatalf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		constraintLength int
+		generators       []int
+		termination      Termination
+		puncture         [][]int
+		length           int
+	}{
+		{1, []int{01}, ZeroTail, nil, 10},
+		{17, []int{0400000, 1}, ZeroTail, nil, 10},
+		{3, []int{}, ZeroTail, nil, 10},
+		{3, []int{010, 05}, ZeroTail, nil, 10},
+		{3, []int{06, 06}, ZeroTail, nil, 10},
+		{3, []int{03, 03}, ZeroTail, nil, 10},
+		{3, []int{07, 05}, "other", nil, 10},
+		{3, []int{07, 05}, ZeroTail, nil, 0},
+		{7, []int{0171, 0133}, TailBiting, nil, 5},
+		{3, []int{07, 05}, ZeroTail, [][]int{{1, 1}}, 10},
+		{3, []int{07, 05}, ZeroTail, [][]int{{1, 1}, {1}}, 10},
+		{3, []int{07, 05}, ZeroTail, [][]int{{1, 2}, {1, 0}}, 10},
+		{3, []int{07, 05}, ZeroTail, [][]int{{0, 0}, {0, 0}}, 10},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(test.constraintLength, test.generators, test.termination, test.puncture, test.length); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestConvolutional_LinearBlock(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	codes := []*Convolutional{
+		{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Length: 16},
+		{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: TailBiting, Length: 24},
+		{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: ZeroTail, Puncture: [][]int{{1, 1, 0}, {1, 0, 1}}, Length: 30},
+	}
+	for i, code := range codes {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := code.LinearBlock(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if block.MessageLength() != code.MessageLength() || block.CodewordLength() != code.CodewordLength() {
+				t.Fatalf("expected a (%v,%v) code but found %v", code.CodewordLength(), code.MessageLength(), block)
+			}
+			for j := 0; j < 10; j++ {
+				codeword := code.Encode(randomMessage(random, code.Length))
+				if !block.Syndrome(codeword).IsZero() {
+					t.Fatalf("expected the codeword %v to satisfy H", codeword)
+				}
+			}
+		})
+	}
+}
+
+func TestViterbi_DecodeHard(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	tests := []struct {
+		code   *Convolutional
+		errors int
+	}{
+		// d_free is 5 for (7,5) and 10 for (171,133)
+		{&Convolutional{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Length: 4}, 2},
+		{&Convolutional{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: ZeroTail, Length: 50}, 4},
+		{&Convolutional{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: TailBiting, Length: 40}, 4},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			viterbi := &Viterbi{Code: test.code}
+			for j := 0; j < 50; j++ {
+				message := randomMessage(random, test.code.Length)
+				codeword := test.code.Encode(message)
+				for _, p := range random.Perm(codeword.Len())[:test.errors] {
+					codeword.Set(p, codeword.At(p)+1)
+				}
+				if actual := viterbi.DecodeHard(codeword); !actual.Equals(message) {
+					t.Fatalf("expected %v but found %v", message, actual)
+				}
+			}
+		})
+	}
+}
+
+// TestViterbi_MaximumLikelihood compares against the most likely codeword found by trying every message
+func TestViterbi_MaximumLikelihood(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	codes := []*Convolutional{
+		{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Length: 8},
+		{ConstraintLength: 4, Generators: []int{017, 013}, Termination: TailBiting, Length: 8},
+		{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Puncture: [][]int{{1, 1}, {1, 0}}, Length: 8},
+		{ConstraintLength: 4, Generators: []int{013, 015}, Feedback: 013, Termination: ZeroTail, Length: 8},
+	}
+	for i, code := range codes {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			viterbi := &Viterbi{Code: code}
+			for j := 0; j < 30; j++ {
+				llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(randomMessage(random, code.Length))), 0.5), 0.5)
+
+				best, bestMetric := mat.SparseVector(nil), math.Inf(-1)
+				for m := 0; m < 1<<code.Length; m++ {
+					message := mat.CSRVec(code.Length)
+					for b := 0; b < code.Length; b++ {
+						message.Set(b, (m>>b)&1)
+					}
+					codeword := code.Encode(message)
+					metric := 0.0
+					for n := 0; n < codeword.Len(); n++ {
+						metric += float64(1-2*codeword.At(n)) * llr.AtVec(n)
+					}
+					if metric > bestMetric {
+						best, bestMetric = message, metric
+					}
+				}
+				if actual := viterbi.Decode(llr); !actual.Equals(best) {
+					t.Fatalf("expected %v but found %v", best, actual)
+				}
+			}
+		})
+	}
+}
+
+// TestBCJR_APosteriori compares log-MAP against the a posteriori LLRs found by summing over every message
+func TestBCJR_APosteriori(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	code := &Convolutional{ConstraintLength: 3, Generators: []int{07, 05}, Termination: ZeroTail, Puncture: [][]int{{1, 1}, {1, 0}}, Length: 8}
+	bcjr := &BCJR{Code: code}
+	for j := 0; j < 20; j++ {
+		llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(randomMessage(random, code.Length))), 0.5), 0.5)
+
+		likelihood := make([][2]float64, code.Length)
+		for m := 0; m < 1<<code.Length; m++ {
+			message := mat.CSRVec(code.Length)
+			for b := 0; b < code.Length; b++ {
+				message.Set(b, (m>>b)&1)
+			}
+			codeword := code.Encode(message)
+			metric := 0.0
+			for n := 0; n < codeword.Len(); n++ {
+				metric += float64(1-2*codeword.At(n)) * llr.AtVec(n) / 2
+			}
+			for b := 0; b < code.Length; b++ {
+				likelihood[b][(m>>b)&1] += math.Exp(metric)
+			}
+		}
+
+		actual := bcjr.Decode(llr)
+		for b := range likelihood {
+			expected := math.Log(likelihood[b][0] / likelihood[b][1])
+			if math.Abs(actual.AtVec(b)-expected) > 1e-9*math.Max(1, math.Abs(expected)) {
+				t.Fatalf("expected LLR %v for bit %v but found %v", expected, b, actual.AtVec(b))
+			}
+		}
+	}
+}
+
+// TestBCJR_APrioriRecursive compares log-MAP of an RSC code with a priori LLRs against the a posteriori
+// LLRs found by summing over every message
+func TestBCJR_APrioriRecursive(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+	code := &Convolutional{ConstraintLength: 4, Generators: []int{013, 015}, Feedback: 013, Termination: ZeroTail, Length: 7}
+	bcjr := &BCJR{Code: code}
+	for j := 0; j < 20; j++ {
+		llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(randomMessage(random, code.Length))), 0.5), 0.5)
+		stream := make([]float64, llr.Len())
+		for n := range stream {
+			stream[n] = llr.AtVec(n)
+		}
+		aPriori := make([]float64, code.Length)
+		for b := range aPriori {
+			aPriori[b] = random.NormFloat64()
+		}
+
+		likelihood := make([][2]float64, code.Length)
+		for m := 0; m < 1<<code.Length; m++ {
+			message := mat.CSRVec(code.Length)
+			metric := 0.0
+			for b := 0; b < code.Length; b++ {
+				message.Set(b, (m>>b)&1)
+				metric += float64(1-2*((m>>b)&1)) * aPriori[b] / 2
+			}
+			codeword := code.Encode(message)
+			for n := 0; n < codeword.Len(); n++ {
+				metric += float64(1-2*codeword.At(n)) * stream[n] / 2
+			}
+			for b := 0; b < code.Length; b++ {
+				likelihood[b][(m>>b)&1] += math.Exp(metric)
+			}
+		}
+
+		actual := bcjr.APosteriori(stream, aPriori)
+		for b := range likelihood {
+			expected := math.Log(likelihood[b][0] / likelihood[b][1])
+			if math.Abs(actual[b]-expected) > 1e-9*math.Max(1, math.Abs(expected)) {
+				t.Fatalf("expected LLR %v for bit %v but found %v", expected, b, actual[b])
+			}
+		}
+	}
+}
+
+func TestConvolutional_Recursive(t *testing.T) {
+	random := rand.New(rand.NewSource(9))
+	code, err := NewRecursive(4, []int{013, 015}, 013, ZeroTail, nil, 20)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	block, err := code.LinearBlock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	for j := 0; j < 20; j++ {
+		message := randomMessage(random, code.Length)
+		codeword := code.Encode(message)
+		for b := 0; b < code.Length; b++ {
+			if codeword.At(2*b) != message.At(b) {
+				t.Fatalf("expected the first output to be the message %v but found %v", message, codeword)
+			}
+		}
+		if !block.Syndrome(codeword).IsZero() {
+			t.Fatalf("expected the codeword %v to satisfy H", codeword)
+		}
+	}
+
+	for _, feedback := range []int{03, 020} {
+		if _, err := NewRecursive(4, []int{013, 015}, feedback, ZeroTail, nil, 20); err == nil {
+			t.Fatalf("expected an error for feedback %o", feedback)
+		}
+	}
+	if _, err := NewRecursive(4, []int{013, 015}, 013, TailBiting, nil, 20); err == nil {
+		t.Fatalf("expected an error for a tail-biting recursive code")
+	}
+}
+
+func TestBCJR_Decode(t *testing.T) {
+	random := rand.New(rand.NewSource(6))
+	codes := []*Convolutional{
+		{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: ZeroTail, Length: 64},
+		{ConstraintLength: 7, Generators: []int{0171, 0133}, Termination: TailBiting, Length: 64},
+	}
+	for i, code := range codes {
+		for _, maxLog := range []bool{false, true} {
+			t.Run(strconv.Itoa(i)+"/"+strconv.FormatBool(maxLog), func(t *testing.T) {
+				bcjr := &BCJR{Code: code, MaxLog: maxLog}
+				for j := 0; j < 20; j++ {
+					message := randomMessage(random, code.Length)
+					llr := bcjr.Decode(benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(message)), 4), 4))
+					for b := 0; b < code.Length; b++ {
+						if (llr.AtVec(b) < 0) != (message.At(b) == 1) {
+							t.Fatalf("expected bit %v to be %v but found LLR %v", b, message.At(b), llr.AtVec(b))
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDecoders(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	code := &Convolutional{ConstraintLength: 5, Generators: []int{023, 035}, Termination: ZeroTail, Length: 32}
+	block, err := code.LinearBlock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	decoders := map[string]linearblock.Decoder{
+		"Viterbi": &ViterbiDecoder{Block: block, Viterbi: &Viterbi{Code: code}},
+		"BCJR":    &BCJRDecoder{Block: block, BCJR: &BCJR{Code: code}},
+	}
+	for name, decoder := range decoders {
+		t.Run(name, func(t *testing.T) {
+			for j := 0; j < 20; j++ {
+				message := randomMessage(random, block.MessageLength())
+				codeword := block.Encode(message)
+				received := mat.CSRVecCopy(codeword)
+				received.Set(3, received.At(3)+1)
+
+				for _, r := range []linearblock.Received{{Bits: received}, {LLR: benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(codeword), 4), 4)}} {
+					result := decoder.Decode(context.Background(), r)
+					if !result.Converged || !result.Message.Equals(message) {
+						t.Fatalf("expected %v but found %v", message, result.Message)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package convolutional
+
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// ViterbiDecoder adapts a Viterbi decoder to a linearblock.Decoder, the Block is the code's LinearBlock.
+type ViterbiDecoder struct {
+	Block   *linearblock.LinearBlock
+	Viterbi *Viterbi
+}
+
+// Decode uses hard decision Viterbi decoding when the received word has Bits, otherwise soft decisions.
+// The decoded message is re-encoded so the codeword always satisfies every parity check.
+func (v *ViterbiDecoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	if ctx.Err() != nil {
+		return v.Block.NewDecodeResult(received.HardDecision(), 0, messagepassing.Canceled)
+	}
+
+	var message mat.SparseVector
+	if received.Bits != nil {
+		message = v.Viterbi.DecodeHard(received.Bits)
+	} else {
+		message = v.Viterbi.Decode(received.SoftDecision())
+	}
+	return v.Block.NewDecodeResult(v.Viterbi.Code.Encode(message), 0, messagepassing.Stalled)
+}
+
+// BCJRDecoder adapts a BCJR decoder to a linearblock.Decoder, the Block is the code's LinearBlock.
+type BCJRDecoder struct {
+	Block *linearblock.LinearBlock
+	BCJR  *BCJR
+}
+
+// Decode decides each message bit from its a posteriori LLR and re-encodes the message,
+// so the codeword always satisfies every parity check.
+func (b *BCJRDecoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	if ctx.Err() != nil {
+		return b.Block.NewDecodeResult(received.HardDecision(), 0, messagepassing.Canceled)
+	}
+
+	llr := b.BCJR.Decode(received.SoftDecision())
+	message := mat.CSRVec(llr.Len())
+	for i := 0; i < llr.Len(); i++ {
+		if llr.AtVec(i) < 0 {
+			message.Set(i, 1)
+		}
+	}
+	return b.Block.NewDecodeResult(b.BCJR.Code.Encode(message), 0, messagepassing.Stalled)
+}
@@ -0,0 +1,149 @@
+package convolutional
+
+import (
+	"fmt"
+	"math"
+
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// Viterbi is the maximum likelihood sequence decoder from "Error Bounds for Convolutional Codes and an
+// Asymptotically Optimum Decoding Algorithm" by Andrew J. Viterbi. Soft decisions use the correlation of
+// the channel LLRs with each branch's outputs, hard decisions are the same with every LLR ±1 which is the
+// Hamming distance. Tail-biting codes are decoded by trying every starting state, so it's exact but costs
+// 2^(K-1) times as much as a zero-tail code.
+type Viterbi struct {
+	Code *Convolutional
+}
+
+// Decode returns the most likely message for the channel LLRs, where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)).
+func (v *Viterbi) Decode(channelLLR mat2.Vector) (message mat.SparseVector) {
+	llr := depuncture(v.Code, channelLLR)
+
+	starts := []int{0}
+	if v.Code.Termination == TailBiting {
+		starts = make([]int, v.Code.States())
+		for s := range starts {
+			starts[s] = s
+		}
+	}
+
+	var best []int
+	bestMetric := math.Inf(-1)
+	for _, start := range starts {
+		inputs, metric := v.Code.viterbi(llr, start)
+		if best == nil || metric > bestMetric {
+			best, bestMetric = inputs, metric
+		}
+	}
+	return toVector(best)
+}
+
+// DecodeHard returns the message of the codeword closest to the received codeword in Hamming distance.
+func (v *Viterbi) DecodeHard(codeword mat.SparseVector) (message mat.SparseVector) {
+	llr := mat2.NewVecDense(codeword.Len(), nil)
+	for i := 0; i < codeword.Len(); i++ {
+		llr.SetVec(i, float64(1-2*codeword.At(i)))
+	}
+	return v.Decode(llr)
+}
+
+// viterbi returns the inputs of the best path through the trellis that starts and ends in the start state
+// along with its metric
+func (c *Convolutional) viterbi(llr []float64, start int) (inputs []int, metric float64) {
+	states := c.States()
+	n := len(c.Generators)
+
+	metrics := make([]float64, states)
+	next := make([]float64, states)
+	for s := range metrics {
+		metrics[s] = math.Inf(-1)
+	}
+	metrics[start] = 0
+
+	// survivors[t][s] is the state before the best path into s at step t, the newest register
+	// bit is the top bit of s so the input is found from the two states, see input
+	survivors := make([][]int32, c.trellis)
+	for t := 0; t < c.trellis; t++ {
+		survivors[t] = make([]int32, states)
+		for s := range next {
+			next[s] = math.Inf(-1)
+		}
+		for s, m := range metrics {
+			if math.IsInf(m, -1) {
+				continue
+			}
+			first, last := c.inputs(t, s)
+			for b := first; b <= last; b++ {
+				ns := c.next[s][b]
+				candidate := m + branch(llr[t*n:(t+1)*n], c.output[s][b])
+				if candidate > next[ns] {
+					next[ns] = candidate
+					survivors[t][ns] = int32(s)
+				}
+			}
+		}
+		metrics, next = next, metrics
+	}
+
+	inputs = make([]int, c.Length)
+	state := start
+	for t := c.trellis - 1; t >= 0; t-- {
+		previous := int(survivors[t][state])
+		if t < c.Length {
+			inputs[t] = (state >> (c.ConstraintLength - 2)) ^ c.tail[previous]
+		}
+		state = previous
+	}
+	return inputs, metrics[start]
+}
+
+// inputs returns the range of inputs allowed in state s at step t, in the tail only the
+// input that shifts a zero into the register is allowed
+func (c *Convolutional) inputs(t, s int) (first, last int) {
+	if t < c.Length {
+		return 0, 1
+	}
+	return c.tail[s], c.tail[s]
+}
+
+// branch is the log likelihood of the outputs, up to a constant, Σ±LLR_j/2 with + when output j is 0
+func branch(llr []float64, outputs int) float64 {
+	result := 0.0
+	for j, l := range llr {
+		if (outputs>>j)&1 == 1 {
+			result -= l
+		} else {
+			result += l
+		}
+	}
+	return result / 2
+}
+
+// depuncture returns the LLRs of the whole output stream, the punctured bits have an LLR of 0
+func depuncture(code *Convolutional, channelLLR mat2.Vector) []float64 {
+	if code == nil {
+		panic("convolutional code must be set before decoding")
+	}
+	code.init()
+	if channelLLR.Len() != len(code.kept) {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", len(code.kept), channelLLR.Len()))
+	}
+
+	llr := make([]float64, code.trellis*len(code.Generators))
+	for i, p := range code.kept {
+		llr[p] = channelLLR.AtVec(i)
+	}
+	return llr
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package bch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// New creates the systematic narrow-sense primitive binary BCH code of length n=2^m-1 with the
+// designed distance δ, so its roots include α^1,...,α^(δ-1) and it corrects t=⌊(δ-1)/2⌋ errors.
+// The codeword bits are the coefficients c_0,...,c_(n-1) of the code polynomial c(x), see Decoder.
+func New(ctx context.Context, m, designedDistance int, threads int) (*linearblock.LinearBlock, error) {
+	g, err := GeneratorPolynomial(m, designedDistance)
+	if err != nil {
+		return nil, err
+	}
+	n := 1<<m - 1
+
+	// the parity check polynomial h(x) = (x^n-1)/g(x) has degree k, each row of H
+	// is a shift of its reciprocal which makes the n-k rows linearly independent
+	xn1 := make(gf2m.Polynomial, n+1)
+	xn1[0] = 1
+	xn1[n] = 1
+	f, _ := gf2m.New(m)
+	h, _ := f.PolyDivMod(xn1, g)
+	k := h.Degree()
+
+	H := mat.CSRMat(n-k, n)
+	for r := 0; r < n-k; r++ {
+		for i := 0; i <= k; i++ {
+			if h[k-i] == 1 {
+				H.Set(r, r+i, 1)
+			}
+		}
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+// GeneratorPolynomial returns the generator polynomial g(x) of the narrow-sense primitive binary BCH code
+// of length 2^m-1 with the designed distance δ. It's the least common multiple of the minimal polynomials
+// of α^1,...,α^(δ-1). The code has 2^m-1-deg(g) message bits.
+func GeneratorPolynomial(m, designedDistance int) (gf2m.Polynomial, error) {
+	f, err := gf2m.New(m)
+	if err != nil {
+		return nil, err
+	}
+	n := f.Order()
+	if designedDistance < 3 || n < designedDistance {
+		return nil, fmt.Errorf("BCH codes require 3<=designed distance<=%v but found %v", n, designedDistance)
+	}
+
+	// the conjugates α^j, for j in the cyclotomic coset of i, share a minimal polynomial
+	g := gf2m.Polynomial{1}
+	used := make([]bool, n)
+	for i := 1; i < designedDistance; i++ {
+		if used[i] {
+			continue
+		}
+		for _, j := range gf2m.CyclotomicCoset(i, n) {
+			used[j] = true
+		}
+		g = f.PolyMul(g, f.MinimalPolynomial(f.Exp(i)))
+	}
+	return g, nil
+}
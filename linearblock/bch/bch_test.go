@@ -0,0 +1,192 @@
+package bch
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestGeneratorPolynomial(t *testing.T) {
+	tests := []struct {
+		m, designedDistance int
+		expected            gf2m.Polynomial
+	}{
+		{4, 3, []int{1, 1, 0, 0, 1}},                   // x^4+x+1
+		{4, 5, []int{1, 0, 0, 0, 1, 0, 1, 1, 1}},       // x^8+x^7+x^6+x^4+1
+		{4, 7, []int{1, 1, 1, 0, 1, 1, 0, 0, 1, 0, 1}}, // x^10+x^8+x^5+x^4+x^2+x+1
+		{5, 5, []int{1, 0, 0, 1, 0, 1, 1, 0, 1, 1, 1}}, // x^10+x^9+x^8+x^6+x^5+x^3+1
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := GeneratorPolynomial(test.m, test.designedDistance)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !actual.Equals(test.expected) {
+				t.Fatalf("expected %v but found %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGeneratorPolynomial_Invalid(t *testing.T) {
+	tests := []struct {
+		m, designedDistance int
+	}{
+		{1, 3},
+		{17, 3},
+		{4, 2},
+		{4, 16},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := GeneratorPolynomial(test.m, test.designedDistance); err == nil {
+				t.Fatalf("expected an error for m=%v δ=%v", test.m, test.designedDistance)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		m, designedDistance int
+		n, k                int
+	}{
+		{4, 3, 15, 11},
+		{4, 5, 15, 7},
+		{4, 7, 15, 5},
+		{5, 5, 31, 21},
+		{5, 7, 31, 16},
+		{6, 5, 63, 51},
+		{7, 11, 127, 92},
+		{4, 9, 15, 1}, // the repetition code
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := New(context.Background(), test.m, test.designedDistance, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !actual.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.k {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.n, test.k, actual.CodewordLength(), actual.MessageLength())
+			}
+		})
+	}
+}
+
+func TestDecoder_Correct(t *testing.T) {
+	tests := []struct {
+		m, designedDistance int
+	}{
+		{4, 5},
+		{5, 7},
+		{6, 9},
+		{7, 11},
+	}
+	random := rand.New(rand.NewSource(1))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := New(context.Background(), test.m, test.designedDistance, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			decoder := &Decoder{Block: block, M: test.m, DesignedDistance: test.designedDistance}
+			correctable := (test.designedDistance - 1) / 2
+
+			for trial := 0; trial < 100; trial++ {
+				codeword := block.Encode(randomMessage(random, block.MessageLength()))
+				errors := trial % (correctable + 1)
+
+				received := mat.CSRVecCopy(codeword)
+				for _, p := range random.Perm(block.CodewordLength())[:errors] {
+					received.Set(p, received.At(p)+1)
+				}
+
+				actual, corrected, ok := decoder.Correct(received)
+				if !ok || corrected != errors {
+					t.Fatalf("expected %v errors corrected but found %v %v", errors, corrected, ok)
+				}
+				if !actual.Equals(codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_Uncorrectable(t *testing.T) {
+	block, err := New(context.Background(), 5, 7, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Decoder{Block: block, M: 5, DesignedDistance: 7}
+
+	// beyond t errors the decoder either detects them or miscorrects
+	// to another codeword, it never returns a non-codeword as corrected
+	random := rand.New(rand.NewSource(2))
+	detected := 0
+	for trial := 0; trial < 100; trial++ {
+		codeword := block.Encode(randomMessage(random, block.MessageLength()))
+		received := mat.CSRVecCopy(codeword)
+		for _, p := range random.Perm(block.CodewordLength())[:4] {
+			received.Set(p, received.At(p)+1)
+		}
+
+		actual, _, ok := decoder.Correct(received)
+		if !ok {
+			detected++
+			if !actual.Equals(received) {
+				t.Fatalf("expected the received word to be returned when detected")
+			}
+			continue
+		}
+		if !block.Syndrome(actual).IsZero() {
+			t.Fatalf("expected a codeword but found %v", actual)
+		}
+	}
+	if detected == 0 {
+		t.Fatalf("expected some of the uncorrectable errors to be detected")
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	block, err := New(context.Background(), 4, 5, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Decoder{Block: block, M: 4, DesignedDistance: 5}
+
+	message := randomMessage(rand.New(rand.NewSource(3)), block.MessageLength())
+	received := block.Encode(message)
+	received.Set(2, received.At(2)+1)
+	received.Set(11, received.At(11)+1)
+
+	actual := decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if !actual.Converged || actual.Stop != messagepassing.Converged || !actual.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v %v", message, actual.Message, actual.Stop)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual = decoder.Decode(ctx, linearblock.Received{Bits: received})
+	if actual.Stop != messagepassing.Canceled {
+		t.Fatalf("expected %v but found %v", messagepassing.Canceled, actual.Stop)
+	}
+}
@@ -0,0 +1,94 @@
+package bch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Decoder is the algebraic decoder of a narrow-sense primitive binary BCH code made by New.
+// The syndromes S_j = r(α^j), j=1,...,2t, of the received word are computed over GF(2^m),
+// the error locator polynomial Λ(x) is found with Berlekamp-Massey and its roots with a
+// Chien search. Any t=⌊(δ-1)/2⌋ or fewer errors are corrected.
+type Decoder struct {
+	Block            *linearblock.LinearBlock
+	M                int // the codeword length is 2^M-1
+	DesignedDistance int // δ
+
+	once  sync.Once
+	field *gf2m.Field
+}
+
+func (d *Decoder) init() {
+	d.once.Do(func() {
+		f, err := gf2m.New(d.M)
+		if err != nil {
+			panic(err)
+		}
+		if d.DesignedDistance < 3 || f.Order() < d.DesignedDistance {
+			panic(fmt.Sprintf("3<=designed distance<=%v is required but found %v", f.Order(), d.DesignedDistance))
+		}
+		d.field = f
+	})
+}
+
+// Correct returns the corrected codeword along with the number of errors corrected. When
+// more than t errors are detected the codeword is returned as received and ok is false.
+func (d *Decoder) Correct(codeword mat.SparseVector) (corrected mat.SparseVector, errors int, ok bool) {
+	d.init()
+	f := d.field
+	n := f.Order()
+	if codeword.Len() != n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", n, codeword.Len()))
+	}
+	t := (d.DesignedDistance - 1) / 2
+
+	ones := codeword.NonzeroArray()
+	syndromes := make([]int, 2*t)
+	zero := true
+	for j := range syndromes {
+		for _, i := range ones {
+			syndromes[j] ^= f.Exp(i * (j + 1))
+		}
+		zero = zero && syndromes[j] == 0
+	}
+	corrected = mat.CSRVecCopy(codeword)
+	if zero {
+		return corrected, 0, true
+	}
+
+	locator, degree := f.BerlekampMassey(syndromes)
+	if degree > t || len(locator)-1 != degree {
+		return corrected, 0, false
+	}
+
+	// Chien search: an error at position i is a root of Λ(x) at α^-i
+	positions := make([]int, 0, degree)
+	for i := 0; i < n; i++ {
+		if f.PolyEval(locator, f.Exp(-i)) == 0 {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) != degree {
+		return corrected, 0, false
+	}
+
+	for _, i := range positions {
+		corrected.Set(i, corrected.At(i)+1)
+	}
+	return corrected, degree, true
+}
+
+// Decode corrects the hard decisions of the received word, see linearblock.CorrectionDecoder.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: d.Block, Correct: d.correct}).Decode(ctx, received)
+}
+
+func (d *Decoder) correct(codeword mat.SparseVector) (mat.SparseVector, bool) {
+	corrected, _, ok := d.Correct(codeword)
+	return corrected, ok
+}
@@ -0,0 +1,155 @@
+package cyclic
+
+import (
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// gf2 is GF(2) where the binary polynomial arithmetic is done
+var gf2, _ = gf2m.New(1)
+
+// Cyclic is a binary cyclic code of length n generated by g(x), a divisor of x^n-1. The codeword bits
+// are the coefficients c_0,...,c_(n-1) of the code polynomial c(x). It's systematic with the n-k parity
+// bits first followed by the k message bits, c(x) = x^(n-k)m(x) + (x^(n-k)m(x) mod g(x)).
+type Cyclic struct {
+	n         int
+	generator gf2m.Polynomial // g(x) of degree n-k
+	check     gf2m.Polynomial // h(x) = (x^n-1)/g(x) of degree k
+}
+
+// New creates the cyclic code of length n generated by the binary polynomial g(x), where generator[i]
+// is the coefficient of x^i. The generator must divide x^n-1 and have a degree in [1,n).
+func New(n int, generator gf2m.Polynomial) (*Cyclic, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("cyclic codes require n>=2 but found %v", n)
+	}
+	generator = generator.Trim()
+	for _, c := range generator {
+		if c != 0 && c != 1 {
+			return nil, fmt.Errorf("generator must be a binary polynomial but found %v", generator)
+		}
+	}
+	if generator.Degree() < 1 || n <= generator.Degree() {
+		return nil, fmt.Errorf("generator degree must be in [1,%v) but found %v", n, generator.Degree())
+	}
+
+	check, remainder := gf2.PolyDivMod(xn1(n), generator)
+	if len(remainder) != 0 {
+		return nil, fmt.Errorf("generator %v does not divide x^%v-1", generator, n)
+	}
+
+	return &Cyclic{
+		n:         n,
+		generator: append(gf2m.Polynomial{}, generator...),
+		check:     check,
+	}, nil
+}
+
+// xn1 returns x^n-1
+func xn1(n int) gf2m.Polynomial {
+	p := make(gf2m.Polynomial, n+1)
+	p[0] = 1
+	p[n] = 1
+	return p
+}
+
+func (c *Cyclic) String() string {
+	return fmt.Sprintf("cyclic (%v,%v) g(x)=%v", c.n, c.MessageLength(), c.generator)
+}
+
+// Generator returns g(x) where index i is the coefficient of x^i.
+func (c *Cyclic) Generator() gf2m.Polynomial {
+	return append(gf2m.Polynomial{}, c.generator...)
+}
+
+// ParityCheckPolynomial returns h(x) = (x^n-1)/g(x) where index i is the coefficient of x^i.
+func (c *Cyclic) ParityCheckPolynomial() gf2m.Polynomial {
+	return append(gf2m.Polynomial{}, c.check...)
+}
+
+func (c *Cyclic) CodewordLength() int {
+	return c.n
+}
+
+func (c *Cyclic) MessageLength() int {
+	return c.check.Degree()
+}
+
+func (c *Cyclic) ParitySymbols() int {
+	return c.generator.Degree()
+}
+
+// Encode returns the systematic codeword of the message, the remainder of x^(n-k)m(x)/g(x) followed by the message.
+func (c *Cyclic) Encode(message mat.SparseVector) mat.SparseVector {
+	k, r := c.MessageLength(), c.ParitySymbols()
+	if message.Len() != k {
+		panic(fmt.Sprintf("message length == %v required but found %v", k, message.Len()))
+	}
+
+	shifted := make(gf2m.Polynomial, c.n)
+	for _, i := range message.NonzeroArray() {
+		shifted[r+i] = 1
+	}
+	parity := gf2.PolyMod(shifted, c.generator)
+
+	codeword := mat.CSRVec(c.n)
+	for i, b := range parity {
+		codeword.Set(i, b)
+	}
+	for _, i := range message.NonzeroArray() {
+		codeword.Set(r+i, 1)
+	}
+	return codeword
+}
+
+// Message returns the message of a systematic codeword, its last k bits.
+func (c *Cyclic) Message(codeword mat.SparseVector) mat.SparseVector {
+	if codeword.Len() != c.n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", c.n, codeword.Len()))
+	}
+	return codeword.Slice(c.ParitySymbols(), c.MessageLength())
+}
+
+// LinearBlock returns the equivalent linear block code. H has the n-k cyclic shifts of the reciprocal of
+// h(x) as its rows and G is the systematic generator, so it encodes exactly like Encode without any elimination.
+func (c *Cyclic) LinearBlock() *linearblock.LinearBlock {
+	k, r := c.MessageLength(), c.ParitySymbols()
+
+	H := mat.CSRMat(r, c.n)
+	for row := 0; row < r; row++ {
+		for i := 0; i <= k; i++ {
+			if c.check[k-i] == 1 {
+				H.Set(row, row+i, 1)
+			}
+		}
+	}
+
+	// row i of G is the codeword of message bit i, in the systematic order the message is
+	// first so the systematic bit j is the codeword bit order[j]
+	G := mat.CSRMat(k, c.n)
+	for i := 0; i < k; i++ {
+		G.Set(i, i, 1)
+		shifted := make(gf2m.Polynomial, r+i+1)
+		shifted[r+i] = 1
+		for j, b := range gf2.PolyMod(shifted, c.generator) {
+			if b == 1 {
+				G.Set(i, k+j, 1)
+			}
+		}
+	}
+	order := make([]int, c.n)
+	for j := range order {
+		order[j] = (j + r) % c.n
+	}
+
+	return &linearblock.LinearBlock{
+		H: H,
+		Processing: &linearblock.Systematic{
+			HColumnOrder: order,
+			G:            G,
+		},
+	}
+}
@@ -0,0 +1,262 @@
+package cyclic
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+var (
+	hamming = gf2m.Polynomial{1, 1, 0, 1}                         // (7,4) 1+x+x^3
+	bch     = gf2m.Polynomial{1, 0, 0, 0, 1, 0, 1, 1, 1}          // (15,7) 1+x^4+x^6+x^7+x^8
+	golay   = gf2m.Polynomial{1, 0, 1, 0, 1, 1, 1, 0, 0, 0, 1, 1} // (23,12) 1+x^2+x^4+x^5+x^6+x^10+x^11
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		n         int
+		generator gf2m.Polynomial
+		k         int
+		valid     bool
+	}{
+		{7, hamming, 4, true},
+		{15, bch, 7, true},
+		{23, golay, 12, true},
+		{7, gf2m.Polynomial{1, 0, 1}, 0, false}, // 1+x^2 doesn't divide x^7-1
+		{7, gf2m.Polynomial{1}, 0, false},
+		{7, gf2m.Polynomial{1, 0, 0, 0, 0, 0, 0, 1}, 0, false},
+		{7, gf2m.Polynomial{1, 2}, 0, false},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := New(test.n, test.generator)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected an error for %v", test.generator)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.k {
+				t.Fatalf("expected (%v,%v) but found %v", test.n, test.k, actual)
+			}
+			if product := gf2.PolyMul(actual.Generator(), actual.ParityCheckPolynomial()); !product.Equals(xn1(test.n)) {
+				t.Fatalf("expected g(x)h(x) == x^n-1 but found %v", product)
+			}
+		})
+	}
+}
+
+func TestCyclic_LinearBlock(t *testing.T) {
+	tests := []struct {
+		n         int
+		generator gf2m.Polynomial
+	}{
+		{7, hamming},
+		{15, bch},
+		{23, golay},
+	}
+	random := rand.New(rand.NewSource(1))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			code, err := New(test.n, test.generator)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			block := code.LinearBlock()
+			if !block.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+
+			for trial := 0; trial < 20; trial++ {
+				message := randomMessage(random, code.MessageLength())
+				codeword := code.Encode(message)
+				if !codeword.Equals(block.Encode(message)) {
+					t.Fatalf("expected %v but found %v", codeword, block.Encode(message))
+				}
+				if !block.Syndrome(codeword).IsZero() || len(code.Syndrome(codeword).Trim()) != 0 {
+					t.Fatalf("expected a zero syndrome for %v", codeword)
+				}
+				if !code.Message(codeword).Equals(message) || !block.Decode(codeword).Equals(message) {
+					t.Fatalf("expected message %v but found %v", message, code.Message(codeword))
+				}
+
+				// the cyclic shift of a codeword is a codeword
+				shifted := mat.CSRVec(code.CodewordLength())
+				for _, j := range codeword.NonzeroArray() {
+					shifted.Set((j+1)%code.CodewordLength(), 1)
+				}
+				if !block.Syndrome(shifted).IsZero() {
+					t.Fatalf("expected the cyclic shift %v to be a codeword", shifted)
+				}
+			}
+		})
+	}
+}
+
+func TestCyclic_Syndrome(t *testing.T) {
+	code, err := New(15, bch)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	random := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 20; trial++ {
+		received := randomMessage(random, 15)
+		r := make(gf2m.Polynomial, 15)
+		for _, i := range received.NonzeroArray() {
+			r[i] = 1
+		}
+		if expected, actual := gf2.PolyMod(r, bch), code.Syndrome(received); !actual.Equals(expected) {
+			t.Fatalf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func TestMeggitt(t *testing.T) {
+	code, err := New(23, golay)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder, err := NewMeggitt(code, 3)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	random := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 200; trial++ {
+		codeword := code.Encode(randomMessage(random, code.MessageLength()))
+		errors := trial % 4
+
+		received := mat.CSRVecCopy(codeword)
+		for _, p := range random.Perm(code.CodewordLength())[:errors] {
+			received.Set(p, received.At(p)+1)
+		}
+
+		actual, corrected, ok := decoder.Correct(received)
+		if !ok || corrected != errors || !actual.Equals(codeword) {
+			t.Fatalf("expected %v errors corrected but found %v %v", errors, corrected, ok)
+		}
+	}
+
+	message := randomMessage(random, code.MessageLength())
+	received := code.Encode(message)
+	received.Set(0, received.At(0)+1)
+	received.Set(22, received.At(22)+1)
+	result := decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if !result.Converged || result.Stop != messagepassing.Converged || !result.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v %v", message, result.Message, result.Stop)
+	}
+}
+
+func TestErrorTrapping(t *testing.T) {
+	code, err := New(15, bch)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder, err := NewErrorTrapping(code, 2)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	// any two errors are within 8 cyclically consecutive positions so they're all trapped
+	codeword := code.Encode(randomMessage(rand.New(rand.NewSource(4)), code.MessageLength()))
+	for i := 0; i < code.CodewordLength(); i++ {
+		for j := i; j < code.CodewordLength(); j++ {
+			received := mat.CSRVecCopy(codeword)
+			received.Set(i, received.At(i)+1)
+			if j != i {
+				received.Set(j, received.At(j)+1)
+			}
+
+			actual, _, ok := decoder.Correct(received)
+			if !ok || !actual.Equals(codeword) {
+				t.Fatalf("expected errors at %v and %v to be corrected", i, j)
+			}
+		}
+	}
+
+	if _, err := NewErrorTrapping(code, 9); err == nil {
+		t.Fatalf("expected an error for more correctable errors than parity symbols")
+	}
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		n       int
+		factors int
+	}{
+		{1, 1},
+		{6, 4}, // (x+1)^2 (x^2+x+1)^2
+		{7, 3},
+		{15, 5},
+		{23, 3},
+		{31, 7},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			factors, err := Factor(test.n)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if len(factors) != test.factors {
+				t.Fatalf("expected %v factors but found %v", test.factors, factors)
+			}
+
+			product := gf2m.Polynomial{1}
+			for _, factor := range factors {
+				product = gf2.PolyMul(product, factor)
+			}
+			if !product.Equals(xn1(test.n)) {
+				t.Fatalf("expected the product x^%v-1 but found %v", test.n, product)
+			}
+		})
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		n          int
+		generators int
+	}{
+		{7, 6},
+		{6, 7},
+		{23, 6},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			generators, err := Generators(test.n)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if len(generators) != test.generators {
+				t.Fatalf("expected %v generators but found %v", test.generators, generators)
+			}
+			for _, g := range generators {
+				code, err := New(test.n, g)
+				if err != nil {
+					t.Fatalf("expected no error but found: %v", err)
+				}
+				if !code.LinearBlock().Validate() {
+					t.Fatalf("expected a valid linearblock for %v", g)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package cyclic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MaxMeggittPatterns is the most error patterns a Meggitt decoder will store the syndromes of.
+const MaxMeggittPatterns = 1 << 20
+
+// Meggitt is the Meggitt decoder. It stores the syndromes of every correctable error pattern with an error
+// in the highest position x^(n-1). The received word is shifted through the syndrome register and whenever
+// the register holds one of them the bit in the highest position is in error, so it's corrected and its
+// effect removed from the register. Only the errors of one position need to be recognized as the cyclic
+// shifts bring every position to the highest one.
+type Meggitt struct {
+	code        *Cyclic
+	block       *linearblock.LinearBlock
+	correctable int
+	highest     gf2m.Polynomial // the syndrome of x^(n-1)
+	syndromes   map[string]bool
+}
+
+// NewMeggitt creates the Meggitt decoder correcting any pattern of correctable or fewer errors. The correctable
+// errors must be at most ⌊(d_min-1)/2⌋ and the table has Σ_{w<correctable} C(n-1,w) entries (see MaxMeggittPatterns).
+func NewMeggitt(code *Cyclic, correctable int) (*Meggitt, error) {
+	if correctable < 1 {
+		return nil, fmt.Errorf("correctable errors must be >=1 but found %v", correctable)
+	}
+	n := code.CodewordLength()
+	patterns := 0.0
+	for w, binomial := 0, 1.0; w < correctable && w < n; w++ {
+		patterns += binomial
+		binomial = binomial * float64(n-1-w) / float64(w+1)
+	}
+	if patterns > MaxMeggittPatterns {
+		return nil, fmt.Errorf("meggitt decoder requires <=%v error patterns but found %v", MaxMeggittPatterns, patterns)
+	}
+
+	// the syndrome of each single error x^i, a pattern's syndrome is the sum of its errors'
+	single := make([]gf2m.Polynomial, n)
+	register := code.NewShiftRegister()
+	register.Shift(1)
+	for i := 0; i < n; i++ {
+		single[i] = register.Contents()
+		register.Shift(0)
+	}
+
+	m := &Meggitt{
+		code:        code,
+		block:       code.LinearBlock(),
+		correctable: correctable,
+		highest:     single[n-1],
+		syndromes:   make(map[string]bool),
+	}
+	var add func(start, remaining int, syndrome gf2m.Polynomial)
+	add = func(start, remaining int, syndrome gf2m.Polynomial) {
+		m.syndromes[key(syndrome)] = true
+		if remaining == 0 {
+			return
+		}
+		for i := start; i < n-1; i++ {
+			add(i+1, remaining-1, gf2.PolyAdd(syndrome, single[i]))
+		}
+	}
+	add(0, correctable-1, m.highest)
+
+	return m, nil
+}
+
+// key is the map key of the syndrome s(x)
+func key(s gf2m.Polynomial) string {
+	buf := strings.Builder{}
+	for _, b := range s.Trim() {
+		buf.WriteByte(byte('0' + b))
+	}
+	return buf.String()
+}
+
+// Correct returns the corrected codeword along with the number of errors corrected. When the
+// errors are uncorrectable the codeword is returned as received and ok is false.
+func (m *Meggitt) Correct(codeword mat.SparseVector) (corrected mat.SparseVector, errors int, ok bool) {
+	n := m.code.CodewordLength()
+	if codeword.Len() != n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", n, codeword.Len()))
+	}
+
+	register := m.code.NewShiftRegister()
+	register.Load(codeword)
+	corrected = mat.CSRVecCopy(codeword)
+	if register.IsZero() {
+		return corrected, 0, true
+	}
+
+	// after i shifts the register holds the syndrome of x^i r(x) where bit n-1-i is the highest
+	for i := 0; i < n; i++ {
+		if m.syndromes[key(register.stages)] {
+			corrected.Set(n-1-i, corrected.At(n-1-i)+1)
+			for j, b := range m.highest {
+				register.stages[j] ^= b
+			}
+			errors++
+		}
+		register.Shift(0)
+	}
+
+	// x^n = 1 mod g(x) so the register is back to the syndrome of the corrected word
+	if !register.IsZero() {
+		return mat.CSRVecCopy(codeword), 0, false
+	}
+	return corrected, errors, true
+}
+
+// Decode corrects the hard decisions of the received word, see linearblock.CorrectionDecoder.
+func (m *Meggitt) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: m.block, Correct: m.correct}).Decode(ctx, received)
+}
+
+func (m *Meggitt) correct(codeword mat.SparseVector) (mat.SparseVector, bool) {
+	corrected, _, ok := m.Correct(codeword)
+	return corrected, ok
+}
+
+// ErrorTrapping is the error-trapping decoder. When the errors of a cyclic shift of the received word are all
+// in the n-k parity positions the syndrome register holds the error pattern itself, which is recognized by its
+// weight being at most the correctable errors. So it corrects the patterns of correctable or fewer errors that
+// fit in n-k cyclically consecutive positions, which includes the bursts of length n-k or less.
+type ErrorTrapping struct {
+	code        *Cyclic
+	block       *linearblock.LinearBlock
+	correctable int
+}
+
+// NewErrorTrapping creates the error-trapping decoder for patterns of correctable or fewer errors,
+// which must be at most ⌊(d_min-1)/2⌋.
+func NewErrorTrapping(code *Cyclic, correctable int) (*ErrorTrapping, error) {
+	if correctable < 1 || code.ParitySymbols() < correctable {
+		return nil, fmt.Errorf("1<=correctable errors<=%v required but found %v", code.ParitySymbols(), correctable)
+	}
+	return &ErrorTrapping{
+		code:        code,
+		block:       code.LinearBlock(),
+		correctable: correctable,
+	}, nil
+}
+
+// Correct returns the corrected codeword along with the number of errors corrected. When the
+// errors can't be trapped the codeword is returned as received and ok is false.
+func (e *ErrorTrapping) Correct(codeword mat.SparseVector) (corrected mat.SparseVector, errors int, ok bool) {
+	n := e.code.CodewordLength()
+	if codeword.Len() != n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", n, codeword.Len()))
+	}
+
+	register := e.code.NewShiftRegister()
+	register.Load(codeword)
+	corrected = mat.CSRVecCopy(codeword)
+	for i := 0; i < n; i++ {
+		if register.Weight() <= e.correctable {
+			// the register is the error pattern of x^i r(x), undoing the shift locates them in r(x)
+			for j, b := range register.stages {
+				if b == 1 {
+					p := (j - i + n) % n
+					corrected.Set(p, corrected.At(p)+1)
+					errors++
+				}
+			}
+			return corrected, errors, true
+		}
+		register.Shift(0)
+	}
+	return corrected, 0, false
+}
+
+// Decode corrects the hard decisions of the received word, see linearblock.CorrectionDecoder.
+func (e *ErrorTrapping) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: e.block, Correct: e.correct}).Decode(ctx, received)
+}
+
+func (e *ErrorTrapping) correct(codeword mat.SparseVector) (mat.SparseVector, bool) {
+	corrected, _, ok := e.Correct(codeword)
+	return corrected, ok
+}
@@ -0,0 +1,108 @@
+package cyclic
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+)
+
+// MaxGenerators is the most generator polynomials Generators will enumerate.
+const MaxGenerators = 1 << 20
+
+// Factor returns the irreducible binary factors of x^n-1, repeated factors are listed with their multiplicity.
+// With n = 2^a n' where n' is odd, x^n-1 = (x^n'-1)^(2^a) and the factors of x^n'-1 are the minimal polynomials
+// of β^s for each cyclotomic coset of s modulo n', where β is a primitive n'-th root of unity in GF(2^m)
+// and m is the order of 2 modulo n'. Since GF(2^m) is limited to m<=gf2m.MaxM so is n'.
+func Factor(n int) ([]gf2m.Polynomial, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("n>=1 is required but found %v", n)
+	}
+	odd, repeat := n, 1
+	for odd%2 == 0 {
+		odd /= 2
+		repeat *= 2
+	}
+
+	m := 1
+	for power := 2 % odd; power != 1%odd; power = 2 * power % odd {
+		m++
+		if m > gf2m.MaxM {
+			return nil, fmt.Errorf("x^%v-1 requires GF(2^m) with m>%v", n, gf2m.MaxM)
+		}
+	}
+	f, err := gf2m.New(m)
+	if err != nil {
+		return nil, err
+	}
+	beta := f.Exp(f.Order() / odd)
+
+	factors := make([]gf2m.Polynomial, 0)
+	for _, coset := range gf2m.CyclotomicCosets(odd) {
+		minimal := f.MinimalPolynomial(f.Pow(beta, coset[0]))
+		for i := 0; i < repeat; i++ {
+			factors = append(factors, minimal)
+		}
+	}
+	sort.SliceStable(factors, func(i, j int) bool {
+		return factors[i].Degree() < factors[j].Degree()
+	})
+	return factors, nil
+}
+
+// Generators returns the generator polynomials of every cyclic code of length n, the products of the factors of
+// x^n-1 (see Factor), ordered by degree. The trivial codes with g(x)=1 and g(x)=x^n-1 are left out. There are
+// 2^(number of factors) of them for an odd n so it's only practical for small families (see MaxGenerators).
+func Generators(n int) ([]gf2m.Polynomial, error) {
+	factors, err := Factor(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// the distinct factors along with their multiplicity
+	distinct := make([]gf2m.Polynomial, 0)
+	multiplicity := make([]int, 0)
+	for _, factor := range factors {
+		found := false
+		for i, d := range distinct {
+			if d.Equals(factor) {
+				multiplicity[i]++
+				found = true
+				break
+			}
+		}
+		if !found {
+			distinct = append(distinct, factor)
+			multiplicity = append(multiplicity, 1)
+		}
+	}
+
+	count := 1.0
+	for _, m := range multiplicity {
+		count *= float64(m + 1)
+	}
+	if count > MaxGenerators {
+		return nil, fmt.Errorf("x^%v-1 has %v divisors, more than %v", n, count, MaxGenerators)
+	}
+
+	generators := make([]gf2m.Polynomial, 0, int(count))
+	var product func(i int, g gf2m.Polynomial)
+	product = func(i int, g gf2m.Polynomial) {
+		if i == len(distinct) {
+			if 0 < g.Degree() && g.Degree() < n {
+				generators = append(generators, g)
+			}
+			return
+		}
+		for e := 0; e <= multiplicity[i]; e++ {
+			product(i+1, g)
+			g = gf2.PolyMul(g, distinct[i])
+		}
+	}
+	product(0, gf2m.Polynomial{1})
+
+	sort.SliceStable(generators, func(i, j int) bool {
+		return generators[i].Degree() < generators[j].Degree()
+	})
+	return generators, nil
+}
@@ -0,0 +1,77 @@
+package cyclic
+
+import (
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// ShiftRegister is the n-k stage division circuit of g(x). Bits shifted in from the highest degree
+// down leave the remainder of the division by g(x) in the register, so it computes the syndrome
+// s(x) = r(x) mod g(x) of a received word. Shifting with no input multiplies the contents by x mod g(x),
+// turning the syndrome of r(x) into the syndrome of its cyclic shift xr(x).
+type ShiftRegister struct {
+	generator gf2m.Polynomial
+	stages    []int // stage i is the coefficient of x^i
+}
+
+// NewShiftRegister creates the cleared division circuit of the code's g(x).
+func (c *Cyclic) NewShiftRegister() *ShiftRegister {
+	return &ShiftRegister{
+		generator: c.generator,
+		stages:    make([]int, c.ParitySymbols()),
+	}
+}
+
+// Shift clocks the register once with the input bit, the contents s(x) become xs(x)+input mod g(x).
+func (s *ShiftRegister) Shift(input int) {
+	last := len(s.stages) - 1
+	feedback := s.stages[last]
+	copy(s.stages[1:], s.stages[:last])
+	s.stages[0] = input & 1
+	if feedback == 1 {
+		for i := range s.stages {
+			s.stages[i] ^= s.generator[i]
+		}
+	}
+}
+
+// Load clears the register and shifts in the received word from r_(n-1) down to r_0.
+func (s *ShiftRegister) Load(received mat.SparseVector) {
+	for i := range s.stages {
+		s.stages[i] = 0
+	}
+	for i := received.Len() - 1; i >= 0; i-- {
+		s.Shift(received.At(i))
+	}
+}
+
+// Contents returns the register's polynomial where index i is the coefficient of x^i.
+func (s *ShiftRegister) Contents() gf2m.Polynomial {
+	return append(gf2m.Polynomial{}, s.stages...)
+}
+
+// Weight returns the number of stages set.
+func (s *ShiftRegister) Weight() int {
+	weight := 0
+	for _, b := range s.stages {
+		weight += b
+	}
+	return weight
+}
+
+// IsZero returns true when every stage is cleared.
+func (s *ShiftRegister) IsZero() bool {
+	return s.Weight() == 0
+}
+
+// Syndrome returns s(x) = r(x) mod g(x) computed by the division circuit, it's zero only for codewords.
+func (c *Cyclic) Syndrome(received mat.SparseVector) gf2m.Polynomial {
+	if received.Len() != c.n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", c.n, received.Len()))
+	}
+	register := c.NewShiftRegister()
+	register.Load(received)
+	return register.Contents()
+}
@@ -0,0 +1,197 @@
+package linearblock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// Received is the channel output handed to a Decoder. The field that is set depends on the channel,
+// Bits for hard decisions (BSC), Erasures for the BEC and LLR for soft decisions (BPSK/AWGN).
+type Received struct {
+	Bits     mat.SparseVector
+	Erasures []bec.ErasureBit
+	LLR      mat2.Vector // LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n))
+}
+
+// HardDecision returns the received hard decisions. They're the Bits when set, otherwise
+// the sign of the LLRs (a negative LLR is a 1) and lastly the Erasures with erased bits as 0.
+func (r Received) HardDecision() mat.SparseVector {
+	switch {
+	case r.Bits != nil:
+		return r.Bits
+	case r.LLR != nil:
+		result := mat.CSRVec(r.LLR.Len())
+		for i := 0; i < r.LLR.Len(); i++ {
+			if r.LLR.AtVec(i) < 0 {
+				result.Set(i, 1)
+			}
+		}
+		return result
+	case r.Erasures != nil:
+		result := mat.CSRVec(len(r.Erasures))
+		for i, e := range r.Erasures {
+			if e == bec.One {
+				result.Set(i, 1)
+			}
+		}
+		return result
+	}
+	panic("received must have Bits, Erasures or LLR set")
+}
+
+// SoftDecision returns the received LLRs. Without them the hard decisions
+// are used with every bit given the same reliability and erasures given none.
+func (r Received) SoftDecision() mat2.Vector {
+	if r.LLR != nil {
+		return r.LLR
+	}
+	if r.Bits == nil && r.Erasures != nil {
+		result := mat2.NewVecDense(len(r.Erasures), nil)
+		for i, e := range r.Erasures {
+			switch e {
+			case bec.Zero:
+				result.SetVec(i, 1)
+			case bec.One:
+				result.SetVec(i, -1)
+			}
+		}
+		return result
+	}
+
+	bits := r.HardDecision()
+	result := mat2.NewVecDense(bits.Len(), nil)
+	for i := 0; i < bits.Len(); i++ {
+		result.SetVec(i, float64(1-2*bits.At(i)))
+	}
+	return result
+}
+
+// DecodeResult is the outcome of decoding a received word.
+type DecodeResult struct {
+	Codeword       mat.SparseVector          // the estimated codeword, unresolved erasures are 0
+	Erasures       []bec.ErasureBit          // the estimated codeword with any unresolved erasures, only set by erasure decoders
+	Message        mat.SparseVector          // the message decoded from Codeword
+	Converged      bool                      // true when Codeword satisfies every parity check and no erasures remain
+	Iterations     int                       // the number of iterations the decoder used
+	SyndromeWeight int                       // the number of unsatisfied parity checks of Codeword
+	Stop           messagepassing.StopReason // why the decoder stopped
+}
+
+// Decoder is the common interface of the decoders. Any Decoder can be simulated over
+// any channel it understands, see Received. Decoders stop promptly when the ctx is
+// canceled, reporting a messagepassing.Canceled Stop.
+type Decoder interface {
+	Decode(ctx context.Context, received Received) DecodeResult
+}
+
+// DecoderFunc is a function that is a Decoder.
+type DecoderFunc func(ctx context.Context, received Received) DecodeResult
+
+func (d DecoderFunc) Decode(ctx context.Context, received Received) DecodeResult {
+	return d(ctx, received)
+}
+
+// NewDecodeResult creates the DecodeResult for the estimated codeword, filling in the message, syndrome
+// weight and whether it converged. Unless it was Canceled a codeword that satisfies every parity check
+// is reported as Converged and one that doesn't but claims to have converged is reported as Stalled.
+func (l *LinearBlock) NewDecodeResult(codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) DecodeResult {
+	weight := len(l.Syndrome(codeword).NonzeroArray())
+	switch {
+	case stop == messagepassing.Canceled:
+	case weight == 0:
+		stop = messagepassing.Converged
+	case stop == messagepassing.Converged:
+		stop = messagepassing.Stalled
+	}
+	return DecodeResult{
+		Codeword:       codeword,
+		Message:        l.Decode(codeword),
+		Converged:      weight == 0,
+		Iterations:     iterations,
+		SyndromeWeight: weight,
+		Stop:           stop,
+	}
+}
+
+// CorrectionDecoder adapts a decoder that corrects a received word in one shot, like the algebraic decoders,
+// to a Decoder. It corrects the hard decisions with Correct, or the LLRs with CorrectLLR when that is set
+// instead. It isn't iterative so Iterations is 0, the ok is false when the word couldn't be corrected and
+// it's returned as received so it's Stalled.
+type CorrectionDecoder struct {
+	Block      *LinearBlock
+	Correct    func(codeword mat.SparseVector) (corrected mat.SparseVector, ok bool)
+	CorrectLLR func(channelLLR mat2.Vector) (corrected mat.SparseVector, ok bool)
+}
+
+func (c *CorrectionDecoder) Decode(ctx context.Context, received Received) DecodeResult {
+	if ctx.Err() != nil {
+		return c.Block.NewDecodeResult(received.HardDecision(), 0, messagepassing.Canceled)
+	}
+
+	var corrected mat.SparseVector
+	var ok bool
+	if c.CorrectLLR != nil {
+		corrected, ok = c.CorrectLLR(received.SoftDecision())
+	} else {
+		corrected, ok = c.Correct(received.HardDecision())
+	}
+
+	stop := messagepassing.Stalled
+	if ok {
+		stop = messagepassing.Converged
+	}
+	return c.Block.NewDecodeResult(corrected, 0, stop)
+}
+
+// ErasureDecoder adapts a BECFlippingAlg to a Decoder.
+type ErasureDecoder struct {
+	Block   *LinearBlock
+	Alg     bec.BECFlippingAlg
+	MaxIter int // the most calls to Flip, <=0 will use the codeword length + 1
+}
+
+// Decode runs the Alg on the received Erasures until it is done, see bec.FlippingContext.
+// Hard decisions without erasures are decoded as is. The iterations are the number of calls to Flip.
+func (e *ErasureDecoder) Decode(ctx context.Context, received Received) DecodeResult {
+	codeword := received.Erasures
+	if codeword == nil {
+		bits := received.HardDecision()
+		codeword = make([]bec.ErasureBit, bits.Len())
+		for i := range codeword {
+			codeword[i] = bec.ErasureBit(bits.At(i))
+		}
+	}
+	if len(codeword) != e.Block.CodewordLength() {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", e.Block.CodewordLength(), len(codeword)))
+	}
+
+	maxIter := e.MaxIter
+	if maxIter <= 0 {
+		maxIter = len(codeword) + 1
+	}
+	codeword, iterations, stop := bec.FlippingContext(ctx, e.Alg, codeword, maxIter)
+
+	erased := false
+	bits := mat.CSRVec(len(codeword))
+	for i, b := range codeword {
+		switch b {
+		case bec.One:
+			bits.Set(i, 1)
+		case bec.Erased:
+			erased = true
+		}
+	}
+
+	result := e.Block.NewDecodeResult(bits, iterations, stop)
+	result.Erasures = codeword
+	if erased {
+		result.Converged = false
+		result.Stop = stop
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package gf2m
+
+import (
+	"fmt"
+
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MaxM is the largest m supported, the log and exp tables have 2^m entries.
+const MaxM = 16
+
+// primitivePolynomials are the default primitive polynomials where bit i is the coefficient of x^i
+var primitivePolynomials = [MaxM + 1]int{
+	1:  0b11,
+	2:  0b111,
+	3:  0b1011,
+	4:  0b10011,
+	5:  0b100101,
+	6:  0b1000011,
+	7:  0b10001001,
+	8:  0b100011101,
+	9:  0b1000010001,
+	10: 0b10000001001,
+	11: 0b100000000101,
+	12: 0b1000001010011,
+	13: 0b10000000011011,
+	14: 0b100010001000011,
+	15: 0b1000000000000011,
+	16: 0b10001000000001011,
+}
+
+// PrimitivePolynomial returns the default primitive polynomial of degree m, where bit i is the coefficient of x^i.
+func PrimitivePolynomial(m int) (int, error) {
+	if m < 1 || MaxM < m {
+		return 0, fmt.Errorf("GF(2^m) requires 1<=m<=%v but found %v", MaxM, m)
+	}
+	return primitivePolynomials[m], nil
+}
+
+// IsPrimitive returns true when the polynomial of degree m, where bit i is the coefficient of x^i,
+// is primitive. Meaning x has order 2^m-1 modulo the polynomial so it generates GF(2^m).
+func IsPrimitive(m, polynomial int) bool {
+	if m < 1 || MaxM < m || polynomial>>m != 1 {
+		return false
+	}
+	order := 1<<m - 1
+	x := 1
+	for i := 1; i <= order; i++ {
+		x <<= 1
+		if x&(1<<m) > 0 {
+			x ^= polynomial
+		}
+		if x == 1 {
+			return i == order
+		}
+	}
+	return false
+}
+
+// Field is GF(2^m) using exp/log tables. The elements are the ints [0,2^m) in the polynomial basis,
+// bit i is the coefficient of α^i where α is a root of the primitive polynomial. Addition is XOR.
+// A Field is read only after it's created so it can be shared between threads.
+type Field struct {
+	m          int
+	order      int   // 2^m-1, the multiplicative order of α
+	polynomial int   // the primitive polynomial
+	exp        []int // exp[i] = α^i for i in [0,2*order) so products don't need a modulo
+	log        []int // log[α^i] = i, log[0] is unused
+}
+
+// New creates GF(2^m) with the default primitive polynomial, see PrimitivePolynomial.
+func New(m int) (*Field, error) {
+	polynomial, err := PrimitivePolynomial(m)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithPolynomial(m, polynomial)
+}
+
+// NewWithPolynomial creates GF(2^m) using the primitive polynomial of degree m, where bit i is the coefficient of x^i.
+func NewWithPolynomial(m, polynomial int) (*Field, error) {
+	if m < 1 || MaxM < m {
+		return nil, fmt.Errorf("GF(2^m) requires 1<=m<=%v but found %v", MaxM, m)
+	}
+	if !IsPrimitive(m, polynomial) {
+		return nil, fmt.Errorf("%b is not a primitive polynomial of degree %v", polynomial, m)
+	}
+
+	f := &Field{
+		m:          m,
+		order:      1<<m - 1,
+		polynomial: polynomial,
+		exp:        make([]int, 2*(1<<m-1)),
+		log:        make([]int, 1<<m),
+	}
+	x := 1
+	for i := 0; i < f.order; i++ {
+		f.exp[i] = x
+		f.exp[i+f.order] = x
+		f.log[x] = i
+		x <<= 1
+		if x&(1<<m) > 0 {
+			x ^= polynomial
+		}
+	}
+	return f, nil
+}
+
+func (f *Field) String() string {
+	return fmt.Sprintf("GF(2^%v)", f.m)
+}
+
+// M is the number of bits in an element.
+func (f *Field) M() int {
+	return f.m
+}
+
+// Size is the number of elements 2^m.
+func (f *Field) Size() int {
+	return f.order + 1
+}
+
+// Order is the multiplicative order of α, 2^m-1.
+func (f *Field) Order() int {
+	return f.order
+}
+
+// Polynomial is the primitive polynomial of the field, where bit i is the coefficient of x^i.
+func (f *Field) Polynomial() int {
+	return f.polynomial
+}
+
+// Add returns a+b which is also a-b.
+func (f *Field) Add(a, b int) int {
+	return a ^ b
+}
+
+// Mul returns a*b.
+func (f *Field) Mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.log[b]]
+}
+
+// Div returns a/b, it panics when b is 0.
+func (f *Field) Div(a, b int) int {
+	if b == 0 {
+		panic("division by zero in GF(2^m)")
+	}
+	if a == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.order-f.log[b]]
+}
+
+// Inv returns 1/a, it panics when a is 0.
+func (f *Field) Inv(a int) int {
+	return f.Div(1, a)
+}
+
+// Pow returns a^e for any integer e, 0^e is 0 except 0^0 which is 1.
+func (f *Field) Pow(a, e int) int {
+	if a == 0 {
+		if e == 0 {
+			return 1
+		}
+		return 0
+	}
+	return f.Exp(f.log[a] * (e % f.order))
+}
+
+// Exp returns α^i for any integer i.
+func (f *Field) Exp(i int) int {
+	i %= f.order
+	if i < 0 {
+		i += f.order
+	}
+	return f.exp[i]
+}
+
+// Log returns i in [0,2^m-1) where α^i = a, it panics when a is 0.
+func (f *Field) Log(a int) int {
+	if a == 0 {
+		panic("log of zero in GF(2^m)")
+	}
+	return f.log[a]
+}
+
+// Vector returns the binary representation of a as an m length vector, index i is the coefficient of α^i.
+func (f *Field) Vector(a int) mat.SparseVector {
+	result := mat.CSRVec(f.m)
+	for i := 0; i < f.m; i++ {
+		if a&(1<<i) > 0 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
+
+// Element returns the element with the binary representation v, see Vector.
+func (f *Field) Element(v mat.SparseVector) int {
+	if v.Len() != f.m {
+		panic(fmt.Sprintf("vector length == %v required but found %v", f.m, v.Len()))
+	}
+	a := 0
+	for _, i := range v.NonzeroArray() {
+		a |= 1 << i
+	}
+	return a
+}
+
+// Matrix returns the m×m binary matrix of multiplication by a, so Matrix(a)·Vector(b) = Vector(a*b).
+// Column j is Vector(a*α^j).
+func (f *Field) Matrix(a int) mat.SparseMat {
+	result := mat.CSRMat(f.m, f.m)
+	for j := 0; j < f.m; j++ {
+		result.SetColumn(j, f.Vector(f.Mul(a, f.Exp(j))))
+	}
+	return result
+}
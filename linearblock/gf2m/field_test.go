@@ -0,0 +1,213 @@
+package gf2m
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestPrimitivePolynomial(t *testing.T) {
+	for m := 1; m <= MaxM; m++ {
+		t.Run(strconv.Itoa(m), func(t *testing.T) {
+			polynomial, err := PrimitivePolynomial(m)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !IsPrimitive(m, polynomial) {
+				t.Fatalf("expected %b to be primitive", polynomial)
+			}
+		})
+	}
+
+	if _, err := PrimitivePolynomial(MaxM + 1); err == nil {
+		t.Fatalf("expected an error for m=%v", MaxM+1)
+	}
+}
+
+func TestIsPrimitive(t *testing.T) {
+	tests := []struct {
+		m, polynomial int
+		expected      bool
+	}{
+		{4, 0b10011, true},
+		{4, 0b11001, true},
+		{4, 0b11111, false}, // irreducible but x has order 5
+		{4, 0b10101, false}, // reducible (x^2+x+1)^2
+		{4, 0b1011, false},  // degree 3
+		{8, 0b100011101, true},
+		{8, 0b100011011, false}, // the AES polynomial is irreducible but not primitive
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if actual := IsPrimitive(test.m, test.polynomial); actual != test.expected {
+				t.Fatalf("expected %v but found %v", test.expected, actual)
+			}
+		})
+	}
+
+	if _, err := NewWithPolynomial(4, 0b11111); err == nil {
+		t.Fatalf("expected an error for a polynomial that isn't primitive")
+	}
+}
+
+func TestField(t *testing.T) {
+	for _, m := range []int{1, 2, 4, 8} {
+		t.Run(strconv.Itoa(m), func(t *testing.T) {
+			f, err := New(m)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if f.Size() != 1<<m || f.Order() != 1<<m-1 {
+				t.Fatalf("expected size %v but found %v", 1<<m, f.Size())
+			}
+
+			for a := 1; a < f.Size(); a++ {
+				if f.Mul(a, f.Inv(a)) != 1 {
+					t.Fatalf("expected %v*%v == 1", a, f.Inv(a))
+				}
+				if f.Exp(f.Log(a)) != a {
+					t.Fatalf("expected α^log(%v) == %v", a, a)
+				}
+				if f.Pow(a, f.Order()) != 1 || f.Pow(a, -1) != f.Inv(a) {
+					t.Fatalf("expected %v^(2^m-1) == 1 and %v^-1 == 1/%v", a, a, a)
+				}
+				for b := 0; b < f.Size(); b++ {
+					if f.Div(f.Mul(a, b), a) != b {
+						t.Fatalf("expected %v*%v/%v == %v", a, b, a, b)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestField_Matrix(t *testing.T) {
+	f, err := New(5)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	for a := 0; a < f.Size(); a++ {
+		if f.Element(f.Vector(a)) != a {
+			t.Fatalf("expected %v but found %v", a, f.Element(f.Vector(a)))
+		}
+		for b := 0; b < f.Size(); b++ {
+			product := mat.CSRVec(f.M())
+			product.MatMul(f.Matrix(a), f.Vector(b))
+			if f.Element(product) != f.Mul(a, b) {
+				t.Fatalf("expected Matrix(%v)·Vector(%v) == %v but found %v", a, b, f.Mul(a, b), f.Element(product))
+			}
+		}
+	}
+}
+
+func TestField_PolyDivMod(t *testing.T) {
+	f, err := New(8)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	randomPolynomial := func(length int) Polynomial {
+		p := make(Polynomial, length)
+		for i := range p {
+			p[i] = random.Intn(f.Size())
+		}
+		p[length-1] = 1 + random.Intn(f.Order())
+		return p
+	}
+
+	for trial := 0; trial < 100; trial++ {
+		a := randomPolynomial(1 + random.Intn(20))
+		b := randomPolynomial(1 + random.Intn(10))
+
+		quotient, remainder := f.PolyDivMod(a, b)
+		if remainder.Degree() >= b.Degree() {
+			t.Fatalf("expected deg(remainder) < %v but found %v", b.Degree(), remainder.Degree())
+		}
+		if actual := f.PolyAdd(f.PolyMul(quotient, b), remainder); !actual.Equals(a) {
+			t.Fatalf("expected %v but found %v", a, actual)
+		}
+
+		// the gcd of a*c and b*c is a multiple of the monic c
+		c := f.PolyScale(randomPolynomial(3), 1)
+		c = f.PolyScale(c, f.Inv(c[2]))
+		gcd := f.PolyGCD(f.PolyMul(a, c), f.PolyMul(b, c))
+		if len(f.PolyMod(gcd, c)) != 0 || gcd[len(gcd)-1] != 1 {
+			t.Fatalf("expected the monic gcd %v to be a multiple of %v", gcd, c)
+		}
+
+		x := random.Intn(f.Size())
+		if f.PolyEval(f.PolyMul(a, b), x) != f.Mul(f.PolyEval(a, x), f.PolyEval(b, x)) {
+			t.Fatalf("expected (a*b)(%v) == a(%v)*b(%v)", x, x, x)
+		}
+	}
+}
+
+func TestCyclotomicCosets(t *testing.T) {
+	expected := [][]int{{0}, {1, 2, 4, 8}, {3, 6, 12, 9}, {5, 10}, {7, 14, 13, 11}}
+	actual := CyclotomicCosets(15)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but found %v", expected, actual)
+	}
+	for i := range expected {
+		if !Polynomial(actual[i]).Equals(expected[i]) {
+			t.Fatalf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func TestField_MinimalPolynomial(t *testing.T) {
+	f, err := New(4)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	tests := []struct {
+		a        int
+		expected Polynomial
+	}{
+		{0, Polynomial{0, 1}},
+		{1, Polynomial{1, 1}},
+		{f.Exp(1), Polynomial{1, 1, 0, 0, 1}},
+		{f.Exp(3), Polynomial{1, 1, 1, 1, 1}},
+		{f.Exp(5), Polynomial{1, 1, 1}},
+		{f.Exp(7), Polynomial{1, 0, 0, 1, 1}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual := f.MinimalPolynomial(test.a)
+			if !actual.Equals(test.expected) {
+				t.Fatalf("expected %v but found %v", test.expected, actual)
+			}
+			if f.PolyEval(actual, test.a) != 0 {
+				t.Fatalf("expected %v to be a root of %v", test.a, actual)
+			}
+		})
+	}
+}
+
+func TestField_BerlekampMassey(t *testing.T) {
+	f, err := New(8)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	// s_r = c_1 s_(r-1) + c_2 s_(r-2) + c_3 s_(r-3)
+	expected := Polynomial{1, 7, 200, 31}
+	sequence := []int{5, 99, 180}
+	for r := 3; r < 12; r++ {
+		s := 0
+		for i := 1; i < len(expected); i++ {
+			s ^= f.Mul(expected[i], sequence[r-i])
+		}
+		sequence = append(sequence, s)
+	}
+
+	actual, length := f.BerlekampMassey(sequence)
+	if length != 3 || !actual.Equals(expected) {
+		t.Fatalf("expected %v with length 3 but found %v with length %v", expected, actual, length)
+	}
+}
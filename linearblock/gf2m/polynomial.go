@@ -0,0 +1,242 @@
+package gf2m
+
+import (
+	"fmt"
+)
+
+// Polynomial is a polynomial over GF(2^m) where p[i] is the coefficient of x^i. A polynomial
+// with only 0 and 1 coefficients is a binary polynomial, they're closed under the Field's operations.
+type Polynomial []int
+
+// Degree returns the degree of p ignoring any leading zeros, the zero polynomial has degree -1.
+func (p Polynomial) Degree() int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i] != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Trim returns p without its leading zeros.
+func (p Polynomial) Trim() Polynomial {
+	return p[:p.Degree()+1]
+}
+
+// Equals returns true when p and q have the same coefficients, ignoring leading zeros.
+func (p Polynomial) Equals(q Polynomial) bool {
+	p, q = p.Trim(), q.Trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PolyAdd returns a(x)+b(x).
+func (f *Field) PolyAdd(a, b Polynomial) Polynomial {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	result := append(Polynomial{}, a...)
+	for i, c := range b {
+		result[i] ^= c
+	}
+	return result.Trim()
+}
+
+// PolyScale returns c*p(x).
+func (f *Field) PolyScale(p Polynomial, c int) Polynomial {
+	result := make(Polynomial, len(p))
+	for i, pi := range p {
+		result[i] = f.Mul(pi, c)
+	}
+	return result.Trim()
+}
+
+// PolyMul returns a(x)*b(x).
+func (f *Field) PolyMul(a, b Polynomial) Polynomial {
+	a, b = a.Trim(), b.Trim()
+	if len(a) == 0 || len(b) == 0 {
+		return Polynomial{}
+	}
+	result := make(Polynomial, len(a)+len(b)-1)
+	for i, ai := range a {
+		if ai == 0 {
+			continue
+		}
+		la := f.log[ai]
+		for j, bj := range b {
+			if bj != 0 {
+				result[i+j] ^= f.exp[la+f.log[bj]]
+			}
+		}
+	}
+	return result
+}
+
+// PolyDivMod returns the quotient and remainder of a(x)/b(x), it panics when b is the zero polynomial.
+func (f *Field) PolyDivMod(a, b Polynomial) (quotient, remainder Polynomial) {
+	b = b.Trim()
+	if len(b) == 0 {
+		panic("division by the zero polynomial")
+	}
+	remainder = append(Polynomial{}, a.Trim()...)
+	if len(remainder) < len(b) {
+		return Polynomial{}, remainder
+	}
+
+	lead := f.Inv(b[len(b)-1])
+	quotient = make(Polynomial, len(remainder)-len(b)+1)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		c := f.Mul(remainder[i+len(b)-1], lead)
+		if c == 0 {
+			continue
+		}
+		quotient[i] = c
+		for j, bj := range b {
+			remainder[i+j] ^= f.Mul(bj, c)
+		}
+	}
+	return quotient.Trim(), remainder.Trim()
+}
+
+// PolyMod returns the remainder of a(x)/b(x).
+func (f *Field) PolyMod(a, b Polynomial) Polynomial {
+	_, remainder := f.PolyDivMod(a, b)
+	return remainder
+}
+
+// PolyGCD returns the monic greatest common divisor of a(x) and b(x), the gcd of two zero polynomials is zero.
+func (f *Field) PolyGCD(a, b Polynomial) Polynomial {
+	a, b = a.Trim(), b.Trim()
+	for len(b) > 0 {
+		a, b = b, f.PolyMod(a, b)
+	}
+	if len(a) == 0 {
+		return a
+	}
+	return f.PolyScale(a, f.Inv(a[len(a)-1]))
+}
+
+// PolyEval returns p(x) using Horner's method.
+func (f *Field) PolyEval(p Polynomial, x int) int {
+	result := 0
+	if x == 0 {
+		if len(p) > 0 {
+			result = p[0]
+		}
+		return result
+	}
+	lx := f.log[x]
+	for i := len(p) - 1; i >= 0; i-- {
+		if result != 0 {
+			result = f.exp[f.log[result]+lx]
+		}
+		result ^= p[i]
+	}
+	return result
+}
+
+// PolyDerivative returns the formal derivative p'(x), in characteristic 2 only the odd powers remain.
+func (f *Field) PolyDerivative(p Polynomial) Polynomial {
+	if len(p) < 2 {
+		return Polynomial{}
+	}
+	result := make(Polynomial, len(p)-1)
+	for i := 1; i < len(p); i += 2 {
+		result[i-1] = p[i]
+	}
+	return result.Trim()
+}
+
+// CyclotomicCoset returns the 2-cyclotomic coset of s modulo n, {s, 2s, 4s, ...} mod n, starting with s mod n.
+func CyclotomicCoset(s, n int) []int {
+	if n < 1 {
+		panic(fmt.Sprintf("n>=1 is required but found %v", n))
+	}
+	s %= n
+	if s < 0 {
+		s += n
+	}
+	coset := []int{s}
+	for j := 2 * s % n; j != s; j = 2 * j % n {
+		coset = append(coset, j)
+	}
+	return coset
+}
+
+// CyclotomicCosets returns the 2-cyclotomic cosets modulo n which partition [0,n), ordered by their smallest element.
+func CyclotomicCosets(n int) [][]int {
+	used := make([]bool, n)
+	cosets := make([][]int, 0)
+	for s := 0; s < n; s++ {
+		if used[s] {
+			continue
+		}
+		coset := CyclotomicCoset(s, n)
+		for _, j := range coset {
+			used[j] = true
+		}
+		cosets = append(cosets, coset)
+	}
+	return cosets
+}
+
+// MinimalPolynomial returns the minimal polynomial of a, the lowest degree monic binary polynomial
+// with a as a root. For a = α^i its roots are α^j for every j in the cyclotomic coset of i modulo 2^m-1.
+func (f *Field) MinimalPolynomial(a int) Polynomial {
+	if a == 0 {
+		return Polynomial{0, 1}
+	}
+	result := Polynomial{1}
+	for _, j := range CyclotomicCoset(f.log[a], f.order) {
+		result = f.PolyMul(result, Polynomial{f.exp[j], 1})
+	}
+	return result
+}
+
+// BerlekampMassey returns the shortest linear feedback shift register, the connection polynomial Λ(x) with Λ_0=1,
+// that generates the sequence along with its length L. So s_r = Σ_{i=1..L} Λ_i s_(r-i) for r>=L. When used as an
+// error locator a Λ(x) with a degree smaller than L is uncorrectable.
+func (f *Field) BerlekampMassey(sequence []int) (connection Polynomial, length int) {
+	connection = Polynomial{1} // Λ(x)
+	previous := Polynomial{1}  // B(x), Λ(x) before the last length change
+	shift := 1
+	b := 1 // the discrepancy at the last length change
+
+	for r := range sequence {
+		discrepancy := sequence[r]
+		for i := 1; i <= length && i < len(connection); i++ {
+			discrepancy ^= f.Mul(connection[i], sequence[r-i])
+		}
+		if discrepancy == 0 {
+			shift++
+			continue
+		}
+
+		// Λ(x) - (d/b) x^shift B(x)
+		scale := f.Div(discrepancy, b)
+		next := make(Polynomial, max(len(connection), len(previous)+shift))
+		copy(next, connection)
+		for i, c := range previous {
+			next[i+shift] ^= f.Mul(scale, c)
+		}
+
+		if 2*length <= r {
+			previous = connection
+			length = r + 1 - length
+			b = discrepancy
+			shift = 1
+		} else {
+			shift++
+		}
+		connection = next
+	}
+
+	return connection.Trim(), length
+}
@@ -0,0 +1,58 @@
+package golay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/syndrome"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Decoder is the complete decoder of the Golay codes made by New and NewExtended. Every syndrome is
+// mapped to a coset leader (see syndrome.Table) so every received word is decoded to a closest
+// codeword. All patterns of 3 or fewer errors are corrected. The extended code's syndromes of
+// weight 4 patterns have six leaders each, one of them is used.
+type Decoder struct {
+	Block *linearblock.LinearBlock
+
+	once  sync.Once
+	table *syndrome.Table
+}
+
+func (d *Decoder) init() {
+	d.once.Do(func() {
+		rows, cols := d.Block.H.Dims()
+		if !(cols == 23 && rows == 11) && !(cols == 24 && rows == 12) {
+			panic(fmt.Sprintf("a (23,12) or (24,12) Golay code is required but found H with dims (%v,%v)", rows, cols))
+		}
+
+		var err error
+		d.table, err = syndrome.NewTable(d.Block)
+		if err != nil {
+			panic(err)
+		}
+	})
+}
+
+// Correct returns the closest codeword along with the number of errors corrected. The ok is false
+// when it isn't the only closest codeword, which only happens for 4 errors in the extended code.
+func (d *Decoder) Correct(codeword mat.SparseVector) (corrected mat.SparseVector, errors int, ok bool) {
+	d.init()
+	if codeword.Len() != d.Block.CodewordLength() {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", d.Block.CodewordLength(), codeword.Len()))
+	}
+	return d.table.DecodeComplete(codeword)
+}
+
+// Decode corrects the hard decisions of the received word, see linearblock.CorrectionDecoder.
+// It's complete so the word is always corrected to a codeword, even when it isn't the only closest one.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: d.Block, Correct: d.correct}).Decode(ctx, received)
+}
+
+func (d *Decoder) correct(codeword mat.SparseVector) (mat.SparseVector, bool) {
+	corrected, _, _ := d.Correct(codeword)
+	return corrected, true
+}
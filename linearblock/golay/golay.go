@@ -0,0 +1,62 @@
+package golay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/cyclic"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Generator is the generator polynomial g(x) = 1+x^2+x^4+x^5+x^6+x^10+x^11 of the
+// cyclic (23,12) Golay code, where index i is the coefficient of x^i.
+var Generator = gf2m.Polynomial{1, 0, 1, 0, 1, 1, 1, 0, 0, 0, 1, 1}
+
+// New creates the systematic binary (23,12) Golay code. It's a perfect code with minimum distance 7,
+// so every word is within distance 3 of exactly one codeword. The codeword bits are the coefficients
+// c_0,...,c_22 of the code polynomial c(x), see cyclic.Cyclic.
+func New(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	code, err := cyclic.New(23, Generator)
+	if err != nil {
+		return nil, err
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, code.LinearBlock().H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+// NewExtended creates the systematic extended binary (24,12) Golay code, the (23,12) code plus an overall
+// parity bit as its last bit. It has minimum distance 8 so it corrects 3 errors and detects 4.
+func NewExtended(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	code, err := cyclic.New(23, Generator)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cyclic parity checks don't include the overall parity bit,
+	// the added check row is over every bit
+	cyclicH := code.LinearBlock().H
+	rows, cols := cyclicH.Dims()
+	H := mat.CSRMat(rows+1, cols+1)
+	for r := 0; r < rows; r++ {
+		for _, c := range cyclicH.Row(r).NonzeroArray() {
+			H.Set(r, c, 1)
+		}
+	}
+	for c := 0; c <= cols; c++ {
+		H.Set(rows, c, 1)
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
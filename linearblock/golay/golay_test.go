@@ -0,0 +1,189 @@
+package golay
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+// patterns calls f with every error pattern of the given weight
+func patterns(n, weight int, f func(positions []int)) {
+	positions := make([]int, 0, weight)
+	var next func(start int)
+	next = func(start int) {
+		if len(positions) == weight {
+			f(positions)
+			return
+		}
+		for p := start; p < n; p++ {
+			positions = append(positions, p)
+			next(p + 1)
+			positions = positions[:len(positions)-1]
+		}
+	}
+	next(0)
+}
+
+func TestNew_WeightDistribution(t *testing.T) {
+	tests := []struct {
+		extended     bool
+		n            int
+		distribution map[int]int
+	}{
+		{false, 23, map[int]int{0: 1, 7: 253, 8: 506, 11: 1288, 12: 1288, 15: 506, 16: 253, 23: 1}},
+		{true, 24, map[int]int{0: 1, 8: 759, 12: 2576, 16: 759, 24: 1}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var block *linearblock.LinearBlock
+			var err error
+			if test.extended {
+				block, err = NewExtended(context.Background(), 0)
+			} else {
+				block, err = New(context.Background(), 0)
+			}
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !block.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+			if block.CodewordLength() != test.n || block.MessageLength() != 12 {
+				t.Fatalf("expected (%v,12) but found (%v,%v)", test.n, block.CodewordLength(), block.MessageLength())
+			}
+
+			actual := make(map[int]int)
+			for value := 0; value < 1<<12; value++ {
+				message := mat.CSRVec(12)
+				for i := 0; i < 12; i++ {
+					message.Set(i, value>>i&1)
+				}
+				actual[len(block.Encode(message).NonzeroArray())]++
+			}
+			if !reflect.DeepEqual(actual, test.distribution) {
+				t.Fatalf("expected weight distribution %v but found %v", test.distribution, actual)
+			}
+		})
+	}
+}
+
+func TestDecoder_Correct(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i, create := range []func(context.Context, int) (*linearblock.LinearBlock, error){New, NewExtended} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := create(context.Background(), 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			decoder := &Decoder{Block: block}
+			codeword := block.Encode(randomMessage(random, block.MessageLength()))
+
+			for weight := 0; weight <= 3; weight++ {
+				patterns(block.CodewordLength(), weight, func(positions []int) {
+					received := mat.CSRVecCopy(codeword)
+					for _, p := range positions {
+						received.Set(p, received.At(p)+1)
+					}
+
+					actual, errors, ok := decoder.Correct(received)
+					if !actual.Equals(codeword) || errors != weight || !ok {
+						t.Fatalf("errors at %v: expected %v but found %v %v %v", positions, codeword, actual, errors, ok)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestDecoder_ExtendedFourErrors(t *testing.T) {
+	block, err := NewExtended(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Decoder{Block: block}
+
+	random := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 100; trial++ {
+		codeword := block.Encode(randomMessage(random, block.MessageLength()))
+		received := mat.CSRVecCopy(codeword)
+		for _, p := range random.Perm(block.CodewordLength())[:4] {
+			received.Set(p, received.At(p)+1)
+		}
+
+		// the decoding is complete so it's still a codeword, one of the six at distance 4
+		actual, errors, ok := decoder.Correct(received)
+		if ok || errors != 4 || !block.Syndrome(actual).IsZero() || actual.HammingDistance(received) != 4 {
+			t.Fatalf("expected an ambiguous codeword at distance 4 but found %v %v %v", actual, errors, ok)
+		}
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	block, err := New(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Decoder{Block: block}
+
+	message := randomMessage(rand.New(rand.NewSource(3)), block.MessageLength())
+	received := block.Encode(message)
+	received.Set(0, received.At(0)+1)
+	received.Set(9, received.At(9)+1)
+	received.Set(22, received.At(22)+1)
+
+	actual := decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if !actual.Converged || actual.Stop != messagepassing.Converged || !actual.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v %v", message, actual.Message, actual.Stop)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual = decoder.Decode(ctx, linearblock.Received{Bits: received})
+	if actual.Stop != messagepassing.Canceled {
+		t.Fatalf("expected %v but found %v", messagepassing.Canceled, actual.Stop)
+	}
+}
+
+func TestDecoder_Benchmark(t *testing.T) {
+	// the Golay codes correct every 3 flips so the benchmark must have zero residual errors
+	for i, create := range []func(context.Context, int) (*linearblock.LinearBlock, error){New, NewExtended} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := create(context.Background(), 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			decoder := &Decoder{Block: block}
+
+			createMessage := func(trial int) mat.SparseVector {
+				return benchmarking.RandomMessage(block.MessageLength())
+			}
+			channel := func(codeword mat.SparseVector) linearblock.Received {
+				return linearblock.Received{Bits: benchmarking.RandomFlipBitCount(codeword, 3)}
+			}
+			newDecoder := func() linearblock.Decoder {
+				return decoder
+			}
+
+			stats := benchmarking.BenchmarkDecoder(context.Background(), block, 1000, runtime.NumCPU(), createMessage, channel, newDecoder, nil, false)
+			if stats.ChannelCodewordError.Count != 1000 || stats.ChannelCodewordError.Mean != 0 || stats.ChannelMessageError.Mean != 0 {
+				t.Fatalf("expected zero residual errors but found %v", stats)
+			}
+		})
+	}
+}
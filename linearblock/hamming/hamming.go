@@ -16,20 +16,72 @@ func New(ctx context.Context, paritySymbols int, threads int) (*linearblock.Line
 		panic("hamming codes require >=3 parity symbols")
 	}
 	n := 1<<paritySymbols - 1
-	//k := n - paritySymbols
-	H := mat.CSRMat(paritySymbols, n)
 
 	//To make Hamming codes we make the columns the bit versions
 	// of every number from 1 to and including n -> [1,n] (note they're nonzero)
+	columns := make([]int, n)
 	for i := 1; i <= n; i++ {
-		vec := mat.CSRVec(paritySymbols)
-		for j := 0; j < paritySymbols; j++ {
-			if i&(1<<j) > 0 {
-				vec.Set(j, 1)
-			}
+		columns[i-1] = i
+	}
+	H := parityMatrix(paritySymbols, columns, false)
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+// NewExtended creates the systematic extended hamming code with paritySymbols number of parity symbols
+// plus an overall parity bit, giving a (2^paritySymbols, 2^paritySymbols-paritySymbols-1) code. Extended
+// hamming codes are single error correcting and double error detecting (SECDED).
+func NewExtended(ctx context.Context, paritySymbols int, threads int) (*linearblock.LinearBlock, error) {
+	if paritySymbols < 3 {
+		panic("hamming codes require >=3 parity symbols")
+	}
+	n := 1<<paritySymbols - 1
+
+	columns := make([]int, n)
+	for i := 1; i <= n; i++ {
+		columns[i-1] = i
+	}
+	H := parityMatrix(paritySymbols, columns, true)
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+// NewSECDED creates a shortened extended hamming code for messageLength bits, using the fewest hamming
+// parity symbols r where 2^r-r-1 >= messageLength plus the overall parity bit. So a 64 bit message gives
+// the (72,64) code and a 32 bit message gives the (39,32) code used by memory controllers.
+func NewSECDED(ctx context.Context, messageLength int, threads int) (*linearblock.LinearBlock, error) {
+	if messageLength < 1 {
+		return nil, fmt.Errorf("message length must be >=1 but found %v", messageLength)
+	}
+
+	paritySymbols := 3
+	for 1<<paritySymbols-paritySymbols-1 < messageLength {
+		paritySymbols++
+	}
+
+	// we shorten the code by keeping the parity columns (powers of two) and
+	// only the first messageLength of the data columns
+	columns := make([]int, 0, messageLength+paritySymbols)
+	data := 0
+	for i := 1; i < 1<<paritySymbols; i++ {
+		if i&(i-1) == 0 {
+			columns = append(columns, i)
+		} else if data < messageLength {
+			columns = append(columns, i)
+			data++
 		}
-		H.SetColumn(i-1, vec)
 	}
+	H := parityMatrix(paritySymbols, columns, true)
 
 	result := linearblock.SystematicLinearBlock(ctx, H, threads)
 	if result == nil {
@@ -38,3 +90,31 @@ func New(ctx context.Context, paritySymbols int, threads int) (*linearblock.Line
 
 	return result, nil
 }
+
+// parityMatrix makes the H matrix where each column is the bit version of the number in columns.
+// When extended an overall parity row and column are added.
+func parityMatrix(paritySymbols int, columns []int, extended bool) mat.SparseMat {
+	rows, cols := paritySymbols, len(columns)
+	if extended {
+		rows++
+		cols++
+	}
+
+	H := mat.CSRMat(rows, cols)
+	for c, value := range columns {
+		vec := mat.CSRVec(rows)
+		for j := 0; j < paritySymbols; j++ {
+			if value&(1<<j) > 0 {
+				vec.Set(j, 1)
+			}
+		}
+		if extended {
+			vec.Set(paritySymbols, 1)
+		}
+		H.SetColumn(c, vec)
+	}
+	if extended {
+		H.Set(paritySymbols, cols-1, 1)
+	}
+	return H
+}
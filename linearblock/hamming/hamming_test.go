@@ -25,3 +25,53 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestNewExtended(t *testing.T) {
+	tests := []struct {
+		paritySymbols int
+		n, k          int
+	}{
+		{3, 8, 4},
+		{4, 16, 11},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := NewExtended(context.Background(), test.paritySymbols, 0)
+			if err != nil {
+				t.Fatalf("expected no error found :%v", err)
+			}
+			if !actual.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.k {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.n, test.k, actual.CodewordLength(), actual.MessageLength())
+			}
+		})
+	}
+}
+
+func TestNewSECDED(t *testing.T) {
+	tests := []struct {
+		messageLength int
+		n             int
+	}{
+		{4, 8},
+		{8, 13},
+		{32, 39},
+		{64, 72},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := NewSECDED(context.Background(), test.messageLength, 0)
+			if err != nil {
+				t.Fatalf("expected no error found :%v", err)
+			}
+			if !actual.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.messageLength {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.n, test.messageLength, actual.CodewordLength(), actual.MessageLength())
+			}
+		})
+	}
+}
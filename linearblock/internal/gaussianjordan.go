@@ -64,6 +64,52 @@ func GaussianJordanEliminationGF2(ctx context.Context, H mat.SparseMat, threads
 	return result, columnSwapHistory
 }
 
+// GaussianJordanEliminationOrderedGF2 is Gaussian-Jordan elimination over GF(2) where the pivot columns are
+// picked greedily following order, so the pivots are the first linearly independent columns of that order.
+// No columns are swapped, it returns the reduced rows of H (one per pivot) and the pivot column of each row.
+func GaussianJordanEliminationOrderedGF2(ctx context.Context, H mat.SparseMat, order []int, threads int) (mat.SparseMat, []int) {
+	rows, cols := H.Dims()
+	result := mat.CSRMatCopy(H)
+	pivots := make([]int, 0, rows)
+
+orderLoop:
+	for _, c := range order {
+		if len(pivots) == rows {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break orderLoop
+		default:
+		}
+
+		r := len(pivots)
+		pivot := -1
+		for _, i := range result.Column(c).NonzeroArray() {
+			if i >= r {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			//column c depends on the previous pivot columns
+			continue
+		}
+		if pivot != r {
+			result.SwapRows(r, pivot)
+		}
+
+		eliminateColumnParallel(ctx, r, c, result, threads)
+		pivots = append(pivots, c)
+	}
+
+	//a matrix can't have zero rows so without any pivots the rows are left as is
+	if 0 < len(pivots) && len(pivots) != rows {
+		result = result.Slice(0, 0, len(pivots), cols)
+	}
+	return result, pivots
+}
+
 func upperTriangular(ctx context.Context, rows int, H mat.SparseMat, threads int, showProgressBar bool) bool {
 	bar := pb.Full.New(rows)
 	logrus.Debugf("Reduced row echelon")
@@ -130,6 +176,25 @@ func eliminateOtherRowsParallel(ctx context.Context, rowIndex int, result mat.Sp
 	pool.Wait()
 }
 
+func eliminateColumnParallel(ctx context.Context, rowIndex, column int, result mat.SparseMat, threads int) {
+	pivots := result.Column(column).NonzeroArray()
+	pool := threadpool.New(ctx, threads)
+
+	//for all pivots != rowIndex subtract it (in GF2 subtract is add)
+	// we use AddRows without a mutex, this will work only for CSRMat
+	for _, index := range pivots {
+		pIndex := index
+		if index != rowIndex {
+			pool.Add(func() {
+				func(p int) {
+					result.AddRows(rowIndex, p, p)
+				}(pIndex)
+			})
+		}
+	}
+	pool.Wait()
+}
+
 func eliminateLowerRowsParallel(ctx context.Context, rowIndex int, result mat.SparseMat, threads int) {
 	pivots := result.Column(rowIndex).NonzeroArray()
 	pool := threadpool.New(ctx, threads)
@@ -167,6 +232,33 @@ func CalculateRank(ctx context.Context, H mat.SparseMat, threads int, showProgre
 	return lowerTriangular(ctx, min, tmp, columnSwapHistory, threads, showProgressBar)
 }
 
+// PivotColumnsGF2 returns the indices of a maximal set of linearly independent columns of H.
+// These are the columns that would become pivots during Gaussian-Jordan elimination.
+func PivotColumnsGF2(ctx context.Context, H mat.SparseMat, threads int) []int {
+	tmp := mat.CSRMatCopy(H)
+
+	rows, cols := H.Dims()
+
+	min := rows
+	if cols < rows {
+		min = cols
+	}
+	columnSwapHistory := make([]int, cols)
+	for c := 0; c < cols; c++ {
+		columnSwapHistory[c] = c
+	}
+
+	lowerTriangular(ctx, min, tmp, columnSwapHistory, threads, false)
+
+	pivots := make([]int, 0, min)
+	for r := 0; r < min; r++ {
+		if tmp.At(r, r) > 0 {
+			pivots = append(pivots, columnSwapHistory[r])
+		}
+	}
+	return pivots
+}
+
 func lowerTriangular(ctx context.Context, rows int, H mat.SparseMat, columnSwapHistory []int, threads int, showProgressBar bool) int {
 	bar := pb.Full.New(rows)
 	logrus.Debugf("Row echelon")
@@ -0,0 +1,140 @@
+package finitegeometry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// EuclideanGeometry creates the type-I cyclic EG-LDPC code of the Euclidean geometry EG(m,2^s), from the book
+// "Error Control Coding" by Shu Lin and Daniel J. Costello. The columns are the 2^(ms)-1 points other than the
+// origin and the rows are the lines not through the origin, each line has q=2^s points and each point is on
+// (q^m-1)/(q-1)-1 of them. Two lines meet at most once so the girth is at least 6 and the checks are orthogonal
+// on every bit (see hardmessage.MajorityLogic). The rows are grouped into circulants, a single one when m=2.
+func EuclideanGeometry(ctx context.Context, m, s int, threads int) (*linearblock.LinearBlock, error) {
+	if m < 2 || s < 1 || gf2m.MaxM < m*s {
+		return nil, fmt.Errorf("EG(m,2^s) requires m>=2, s>=1 and m*s<=%v but found m=%v s=%v", gf2m.MaxM, m, s)
+	}
+	f, err := gf2m.New(m * s)
+	if err != nil {
+		return nil, err
+	}
+
+	// the point α^i is column i, GF(q) is {0} ∪ {α^(k(2^(ms)-1)/(q-1))}
+	n := f.Order()
+	q := 1 << s
+	subfield := subfield(f, q)
+
+	// the lines through α^0 not through the origin are {1+βb: β in GF(q)}
+	// where b isn't in GF(q), otherwise the line would have 0 at β=1/b
+	through := make([][]int, 0)
+	for j := 0; j < n; j++ {
+		if j%(n/(q-1)) == 0 {
+			continue
+		}
+		b := f.Exp(j)
+		line := make([]int, 0, q)
+		for _, β := range subfield {
+			line = append(line, f.Log(f.Add(1, f.Mul(β, b))))
+		}
+		through = append(through, line)
+	}
+
+	return incidence(ctx, n, orbits(n, through), threads)
+}
+
+// ProjectiveGeometry creates the type-I cyclic PG-LDPC code of the projective geometry PG(m,2^s), from the book
+// "Error Control Coding" by Shu Lin and Daniel J. Costello. The columns are the (2^((m+1)s)-1)/(q-1) points, with
+// q=2^s, and the rows are the lines, each line has q+1 points and each point is on (q^m-1)/(q-1) of them. Two
+// lines meet at most once so the girth is at least 6 and the checks are orthogonal on every bit (see
+// hardmessage.MajorityLogic). The rows are grouped into cyclic classes, a single circulant when m=2.
+func ProjectiveGeometry(ctx context.Context, m, s int, threads int) (*linearblock.LinearBlock, error) {
+	if m < 2 || s < 1 || gf2m.MaxM < (m+1)*s {
+		return nil, fmt.Errorf("PG(m,2^s) requires m>=2, s>=1 and (m+1)*s<=%v but found m=%v s=%v", gf2m.MaxM, m, s)
+	}
+	f, err := gf2m.New((m + 1) * s)
+	if err != nil {
+		return nil, err
+	}
+
+	// the points are the elements of GF(2^((m+1)s)) up to a nonzero GF(q) multiple,
+	// GF(q)* is generated by α^n so the point of α^i is column i mod n
+	q := 1 << s
+	n := f.Order() / (q - 1)
+	subfield := subfield(f, q)
+
+	// the lines through the point 1 are {1+ηb: η in GF(q)} and b
+	through := make([][]int, 0)
+	for j := 1; j < n; j++ {
+		b := f.Exp(j)
+		line := []int{j}
+		for _, η := range subfield {
+			line = append(line, f.Log(f.Add(1, f.Mul(η, b)))%n)
+		}
+		through = append(through, line)
+	}
+
+	return incidence(ctx, n, orbits(n, through), threads)
+}
+
+// subfield returns the elements of GF(q) in the field, the zero followed by the powers of α^((2^m-1)/(q-1))
+func subfield(f *gf2m.Field, q int) []int {
+	result := []int{0}
+	step := f.Order() / (q - 1)
+	for k := 0; k < q-1; k++ {
+		result = append(result, f.Exp(k*step))
+	}
+	return result
+}
+
+// orbits returns the distinct lines made by cyclically shifting the lines, multiplying by α^k adds k to each point.
+// Each line's shifts are added in order so every cyclic class of n lines is a circulant.
+func orbits(n int, lines [][]int) [][]int {
+	seen := make(map[string]bool)
+	result := make([][]int, 0)
+	for _, line := range lines {
+		// a line already found is in a cyclic class that's already complete
+		sort.Ints(line)
+		if seen[fmt.Sprint(line)] {
+			continue
+		}
+
+		for k := 0; k < n; k++ {
+			shifted := make([]int, len(line))
+			for i, p := range line {
+				shifted[i] = (p + k) % n
+			}
+			sort.Ints(shifted)
+
+			key := fmt.Sprint(shifted)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, shifted)
+		}
+	}
+	return result
+}
+
+// incidence makes the linear block whose H is the line to point incidence matrix,
+// it has more rows than its rank so every line is kept as a parity check
+func incidence(ctx context.Context, points int, lines [][]int, threads int) (*linearblock.LinearBlock, error) {
+	H := mat.CSRMat(len(lines), points)
+	for r, line := range lines {
+		for _, p := range line {
+			H.Set(r, p, 1)
+		}
+	}
+
+	result := linearblock.RedundantLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package finitegeometry
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/hardmessage"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestFiniteGeometry(t *testing.T) {
+	tests := []struct {
+		projective   bool
+		m, s         int
+		n, k         int // k < 0 when not checked
+		rows         int
+		rowWeight    int
+		columnWeight int
+	}{
+		{false, 2, 2, 15, 7, 15, 4, 4},
+		{false, 2, 3, 63, 37, 63, 8, 8},
+		{false, 2, 4, 255, 175, 255, 16, 16},
+		{false, 3, 2, 63, -1, 315, 4, 20},
+		{true, 2, 1, 7, 3, 7, 3, 3},
+		{true, 2, 2, 21, 11, 21, 5, 5},
+		{true, 2, 3, 73, 45, 73, 9, 9},
+		{true, 3, 2, 85, -1, 357, 5, 21},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var block *linearblock.LinearBlock
+			var err error
+			if test.projective {
+				block, err = ProjectiveGeometry(context.Background(), test.m, test.s, 0)
+			} else {
+				block, err = EuclideanGeometry(context.Background(), test.m, test.s, 0)
+			}
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !block.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+
+			rows, cols := block.H.Dims()
+			if cols != test.n || rows != test.rows || (test.k >= 0 && block.MessageLength() != test.k) {
+				t.Fatalf("expected (%v,%v) with %v rows but found (%v,%v) with %v rows", test.n, test.k, test.rows, cols, block.MessageLength(), rows)
+			}
+			for r := 0; r < rows; r++ {
+				if w := len(block.H.Row(r).NonzeroArray()); w != test.rowWeight {
+					t.Fatalf("expected row weight %v but found %v", test.rowWeight, w)
+				}
+			}
+			for c := 0; c < cols; c++ {
+				if w := len(block.H.Column(c).NonzeroArray()); w != test.columnWeight {
+					t.Fatalf("expected column weight %v but found %v", test.columnWeight, w)
+				}
+			}
+			if !hardmessage.Orthogonal(block.H) {
+				t.Fatalf("expected the checks to be orthogonal (girth >= 6)")
+			}
+		})
+	}
+}
+
+func TestFiniteGeometry_Cyclic(t *testing.T) {
+	block, err := EuclideanGeometry(context.Background(), 2, 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	// with m=2 the code is cyclic so the shift of a codeword is a codeword
+	codeword := block.Encode(randomMessage(rand.New(rand.NewSource(1)), block.MessageLength()))
+	n := codeword.Len()
+	shifted := mat.CSRVec(n)
+	for _, i := range codeword.NonzeroArray() {
+		shifted.Set((i+1)%n, 1)
+	}
+	if !block.Syndrome(shifted).IsZero() {
+		t.Fatalf("expected the cyclic shift of a codeword to be a codeword")
+	}
+}
+
+func TestFiniteGeometry_Invalid(t *testing.T) {
+	if _, err := EuclideanGeometry(context.Background(), 1, 2, 0); err == nil {
+		t.Fatalf("expected an error for m=1")
+	}
+	if _, err := EuclideanGeometry(context.Background(), 2, 0, 0); err == nil {
+		t.Fatalf("expected an error for s=0")
+	}
+	if _, err := ProjectiveGeometry(context.Background(), 3, 5, 0); err == nil {
+		t.Fatalf("expected an error for a field that is too large")
+	}
+}
+
+func TestFiniteGeometry_MajorityLogic(t *testing.T) {
+	tests := []struct {
+		projective bool
+		m, s       int
+	}{
+		{false, 2, 3},
+		{false, 3, 2},
+		{true, 2, 3},
+	}
+	random := rand.New(rand.NewSource(2))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var block *linearblock.LinearBlock
+			var err error
+			if test.projective {
+				block, err = ProjectiveGeometry(context.Background(), test.m, test.s, 0)
+			} else {
+				block, err = EuclideanGeometry(context.Background(), test.m, test.s, 0)
+			}
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			decoder := &hardmessage.MajorityLogic{H: block.H}
+
+			// one-step majority-logic corrects ⌊J/2⌋ errors where J is the column weight
+			correctable := len(block.H.Column(0).NonzeroArray()) / 2
+			for trial := 0; trial < 20; trial++ {
+				codeword := block.Encode(randomMessage(random, block.MessageLength()))
+				received := mat.CSRVecCopy(codeword)
+				for _, p := range random.Perm(block.CodewordLength())[:correctable] {
+					received.Set(p, received.At(p)+1)
+				}
+
+				actual, converged := decoder.Decode(received)
+				if !converged || !actual.Equals(codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual)
+				}
+			}
+		})
+	}
+}
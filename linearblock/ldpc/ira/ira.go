@@ -0,0 +1,227 @@
+package ira
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/ldpc/peg"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+var random = rand.New(rand.NewSource(time.Now().Unix()))
+
+// IRA is a systematic (irregular) repeat-accumulate code. Its parity check matrix is H=[H1|H2], the first
+// MessageLength columns are the message bits and H1 says which of them each check sums. H2 is the dual-diagonal
+// accumulator, parity bit i is in checks i and i+1, so
+//
+//	p_0 = (H1 u)_0 and p_i = p_(i-1) + (H1 u)_i
+//
+// which is what Encode computes, in time linear in the number of ones of H and without a generator matrix. The
+// decoders only need H so any of them can decode it.
+type IRA struct {
+	H mat.SparseMat // H=[H1|H2] with H2 the dual-diagonal accumulator
+}
+
+// New creates the IRA code of H, its last rows(H) columns must be the dual-diagonal accumulator.
+func New(H mat.SparseMat) (*IRA, error) {
+	rows, cols := H.Dims()
+	if rows == 0 || rows >= cols {
+		return nil, fmt.Errorf("H must have 0 < rows < cols but found %vx%v", rows, cols)
+	}
+
+	k := cols - rows
+	for i := 0; i < rows; i++ {
+		column := H.Column(k + i).NonzeroArray()
+		expected := []int{i, i + 1}
+		if i == rows-1 {
+			expected = expected[:1]
+		}
+		if len(column) != len(expected) || column[0] != expected[0] || column[len(column)-1] != expected[len(expected)-1] {
+			return nil, fmt.Errorf("column %v of H must have its ones in rows %v of the accumulator but found %v", k+i, expected, column)
+		}
+	}
+
+	CSR := mat.CSRMat(rows, cols)
+	CSR.SetMatrix(H, 0, 0)
+	return &IRA{H: CSR}, nil
+}
+
+// NewEIRA creates the extended IRA (eIRA) code of any H1, with rows(H1) parity bits, by appending the accumulator.
+// H1 can be designed freely, for example with PEG, as long as its checks aren't empty.
+func NewEIRA(H1 mat.SparseMat) (*IRA, error) {
+	rows, k := H1.Dims()
+	if rows == 0 || k == 0 {
+		return nil, fmt.Errorf("H1 must not be empty but found %vx%v", rows, k)
+	}
+
+	H := mat.CSRMat(rows, k+rows)
+	H.SetMatrix(H1, 0, 0)
+	for i := 0; i < rows; i++ {
+		if len(H1.Row(i).NonzeroArray()) == 0 {
+			return nil, fmt.Errorf("check %v of H1 has no message bits", i)
+		}
+		H.Set(i, k+i, 1)
+		if i > 0 {
+			H.Set(i, k+i-1, 1)
+		}
+	}
+	return &IRA{H: H}, nil
+}
+
+// NewRA creates a repeat-accumulate code, every message bit is repeated repetition times, the repetitions are
+// interleaved and every combining of them are summed into a check before the accumulator. It has
+// ⌈messageLength*repetition/combining⌉ parity bits so the rate is about combining/(combining+repetition).
+func NewRA(messageLength, repetition, combining int) (*IRA, error) {
+	return NewIRA(messageLength, peg.DegreeDistribution{repetition: 1}, combining)
+}
+
+// NewIRA creates an irregular repeat-accumulate code, the message bits are repeated according to the node
+// perspective degree distribution and then interleaved, combined and accumulated like NewRA.
+func NewIRA(messageLength int, repetitions peg.DegreeDistribution, combining int) (*IRA, error) {
+	if messageLength < 1 {
+		return nil, fmt.Errorf("message length must be >=1 but found %v", messageLength)
+	}
+	if combining < 1 {
+		return nil, fmt.Errorf("combining must be >=1 but found %v", combining)
+	}
+	degrees, err := repetitions.Degrees(messageLength)
+	if err != nil {
+		return nil, err
+	}
+
+	// every repetition of a message bit is a socket, the interleaver is a random order of the sockets
+	sockets := make([]int, 0)
+	for bit, degree := range degrees {
+		for r := 0; r < degree; r++ {
+			sockets = append(sockets, bit)
+		}
+	}
+	checks := (len(sockets) + combining - 1) / combining
+	if degrees[len(degrees)-1] > checks {
+		return nil, fmt.Errorf("a message bit repeated %v times can't be in %v different checks", degrees[len(degrees)-1], checks)
+	}
+
+	interleaved, err := interleave(sockets, combining)
+	if err != nil {
+		return nil, err
+	}
+
+	H1 := mat.CSRMat(checks, messageLength)
+	for s, bit := range interleaved {
+		H1.Set(s/combining, bit, 1)
+	}
+	return NewEIRA(H1)
+}
+
+// interleave returns the sockets in a random order where no check, the consecutive groups of combining sockets,
+// has the same message bit twice since they'd cancel. Repeats are swapped with a random socket until there are
+// none left.
+func interleave(sockets []int, combining int) ([]int, error) {
+	result := make([]int, len(sockets))
+	for i, j := range random.Perm(len(sockets)) {
+		result[i] = sockets[j]
+	}
+
+	repeats := func(s int) bool {
+		start := s / combining * combining
+		for t := start; t < start+combining && t < len(result); t++ {
+			if t != s && result[t] == result[s] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for attempt := 0; attempt < 100*len(result); attempt++ {
+		s := -1
+		for i := range result {
+			if repeats(i) {
+				s = i
+				break
+			}
+		}
+		if s == -1 {
+			return result, nil
+		}
+
+		t := random.Intn(len(result))
+		result[s], result[t] = result[t], result[s]
+		if repeats(s) || repeats(t) {
+			result[s], result[t] = result[t], result[s]
+		}
+	}
+	return nil, fmt.Errorf("unable to interleave without repeating a message bit in a check")
+}
+
+func (c *IRA) MessageLength() int {
+	rows, cols := c.H.Dims()
+	return cols - rows
+}
+
+func (c *IRA) CodewordLength() int {
+	_, cols := c.H.Dims()
+	return cols
+}
+
+// Encode returns the codeword [u|p] of the message u by running the accumulator over the checks of H1.
+func (c *IRA) Encode(message mat.SparseVector) (codeword mat.SparseVector) {
+	rows, cols := c.H.Dims()
+	k := cols - rows
+	if message.Len() != k {
+		panic(fmt.Sprintf("message length == %v is required but found %v", k, message.Len()))
+	}
+
+	codeword = mat.CSRVec(cols)
+	for _, j := range message.NonzeroArray() {
+		codeword.Set(j, 1)
+	}
+
+	parity := 0
+	for i := 0; i < rows; i++ {
+		for _, j := range c.H.Row(i).NonzeroArray() {
+			if j < k {
+				parity ^= message.At(j)
+			}
+		}
+		codeword.Set(k+i, parity)
+	}
+	return codeword
+}
+
+// LinearBlock returns the linear block code for the tools that need one. It encodes with Encode
+// instead of a generator matrix, which would be dense.
+func (c *IRA) LinearBlock() *linearblock.LinearBlock {
+	_, cols := c.H.Dims()
+	order := make([]int, cols)
+	for i := range order {
+		order[i] = i
+	}
+	return &linearblock.LinearBlock{
+		H: c.H,
+		Processing: &linearblock.Systematic{
+			HColumnOrder: order,
+			Encoder:      c,
+		},
+	}
+}
+
+// UnmarshalJSON is needed because IRA has a mat.SparseMat and requires special handling
+func (c *IRA) UnmarshalJSON(bytes []byte) error {
+	var code struct {
+		H mat.CSRMatrix
+	}
+	err := json.Unmarshal(bytes, &code)
+	if err != nil {
+		return err
+	}
+
+	result, err := New(&code.H)
+	if err != nil {
+		return err
+	}
+	*c = *result
+	return nil
+}
@@ -0,0 +1,202 @@
+package ira
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/ldpc/peg"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/softdecision"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestNewIRA(t *testing.T) {
+	tests := []struct {
+		messageLength int
+		repetitions   peg.DegreeDistribution
+		combining     int
+		checks        int
+	}{
+		{100, peg.DegreeDistribution{3: 1}, 3, 100},
+		{96, peg.DegreeDistribution{4: 1}, 6, 64},
+		{200, peg.DegreeDistribution{2: 0.5, 4: 0.3, 8: 0.2}, 4, 190},
+		{10, peg.DegreeDistribution{3: 1}, 4, 8},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			random = rand.New(rand.NewSource(int64(i)))
+			code, err := NewIRA(test.messageLength, test.repetitions, test.combining)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if code.MessageLength() != test.messageLength || code.CodewordLength() != test.messageLength+test.checks {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.messageLength+test.checks, test.messageLength, code.CodewordLength(), code.MessageLength())
+			}
+
+			degrees, _ := test.repetitions.Degrees(test.messageLength)
+			for j, degree := range degrees {
+				if actual := len(code.H.Column(j).NonzeroArray()); actual != degree {
+					t.Fatalf("expected message bit %v to be repeated %v times but found %v", j, degree, actual)
+				}
+			}
+			for r := 0; r < test.checks; r++ {
+				combined := 0
+				for _, j := range code.H.Row(r).NonzeroArray() {
+					if j < test.messageLength {
+						combined++
+					}
+				}
+				if combined > test.combining {
+					t.Fatalf("expected check %v to combine at most %v bits but found %v", r, test.combining, combined)
+				}
+			}
+		})
+	}
+}
+
+func TestIRA_Encode(t *testing.T) {
+	random = rand.New(rand.NewSource(4))
+	code, err := NewIRA(120, peg.DegreeDistribution{3: 0.7, 6: 0.3}, 5)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	block := code.LinearBlock()
+	if block.Processing.G != nil {
+		t.Fatalf("expected the linearblock code to encode without G")
+	}
+	if !block.Validate() {
+		t.Fatalf("expected valid linearblock code")
+	}
+
+	messages := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 20; trial++ {
+		message := randomMessage(messages, code.MessageLength())
+		codeword := code.Encode(message)
+		if !block.Syndrome(codeword).IsZero() {
+			t.Fatalf("expected a zero syndrome")
+		}
+		if expected := block.Encode(message); !codeword.Equals(expected) {
+			t.Fatalf("expected %v but found %v", expected, codeword)
+		}
+		if !block.Decode(codeword).Equals(message) {
+			t.Fatalf("expected the codeword to start with the message")
+		}
+	}
+}
+
+func TestIRA_JSON(t *testing.T) {
+	random = rand.New(rand.NewSource(10))
+	expected, _ := NewRA(30, 3, 3)
+
+	bs, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	var actual IRA
+	if err := json.Unmarshal(bs, &actual); err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	message := randomMessage(rand.New(rand.NewSource(11)), expected.MessageLength())
+	if !actual.Encode(message).Equals(expected.Encode(message)) {
+		t.Fatalf("expected the same encoding")
+	}
+
+	// the accumulator is checked like New
+	bs, _ = json.Marshal(&IRA{H: mat.CSRMat(2, 2)})
+	if err := json.Unmarshal(bs, &actual); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestIRA_SumProduct(t *testing.T) {
+	random = rand.New(rand.NewSource(6))
+	code, err := NewRA(200, 3, 3)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	alg := &softdecision.SumProduct{H: code.H}
+
+	noise := rand.New(rand.NewSource(7))
+	for trial := 0; trial < 10; trial++ {
+		expected := code.Encode(randomMessage(noise, code.MessageLength()))
+		llr := mat2.NewVecDense(expected.Len(), nil)
+		for i := 0; i < expected.Len(); i++ {
+			llr.SetVec(i, float64(1-2*expected.At(i))*2)
+		}
+		// unreliable errors, they have the wrong sign
+		for _, i := range noise.Perm(expected.Len())[:8] {
+			llr.SetVec(i, -0.5*llr.AtVec(i))
+		}
+
+		actual, iterations, converged := alg.Decode(llr, 50)
+		if !converged || !actual.Equals(expected) {
+			t.Fatalf("expected to decode but found converged=%v after %v iterations", converged, iterations)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	random = rand.New(rand.NewSource(8))
+	code, _ := NewRA(30, 3, 3)
+
+	H := mat.DOKMat(code.H.Dims())
+	H.SetMatrix(code.H, 0, 0)
+	actual, err := New(H)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	message := randomMessage(rand.New(rand.NewSource(9)), code.MessageLength())
+	if !actual.Encode(message).Equals(code.Encode(message)) {
+		t.Fatalf("expected the same encoding")
+	}
+
+	// the accumulator must be the last columns
+	swapped := mat.CSRMat(30, 60)
+	swapped.SetMatrix(code.H.Slice(0, 30, 30, 30), 0, 0)
+	swapped.SetMatrix(code.H.Slice(0, 0, 30, 30), 0, 30)
+
+	tests := []mat.SparseMat{
+		mat.CSRMat(0, 3),
+		mat.CSRMat(2, 2),
+		swapped,
+		mat.DOKMat(2, 4, 1, 1, 1, 1, 0, 1, 1, 1),
+		mat.DOKMat(2, 4, 1, 1, 1, 0, 1, 1, 0, 1),
+	}
+	for i, H := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(H); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestNewIRA_Invalid(t *testing.T) {
+	tests := []struct {
+		messageLength int
+		repetitions   peg.DegreeDistribution
+		combining     int
+	}{
+		{0, peg.DegreeDistribution{3: 1}, 3},
+		{10, peg.DegreeDistribution{3: 1}, 0},
+		{10, peg.DegreeDistribution{3: 0.5}, 3},
+		{2, peg.DegreeDistribution{3: 1}, 3},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := NewIRA(test.messageLength, test.repetitions, test.combining); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package peg
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+	"github.com/sirupsen/logrus"
+)
+
+var random = rand.New(rand.NewSource(time.Now().Unix()))
+
+// DegreeDistribution is a node perspective degree distribution, it maps a degree to the fraction of the nodes
+// with that degree. For example {2: 0.5, 3: 0.3, 8: 0.2} has half the nodes with degree 2.
+type DegreeDistribution map[int]float64
+
+// Degrees returns the degree of each of the count nodes, in increasing order. The number of nodes
+// with each degree is rounded so they sum to count, the largest remainders are rounded up.
+func (d DegreeDistribution) Degrees(count int) ([]int, error) {
+	if len(d) == 0 {
+		return nil, fmt.Errorf("degree distribution must not be empty")
+	}
+
+	degrees := make([]int, 0, len(d))
+	total := 0.0
+	for degree, fraction := range d {
+		if degree < 1 {
+			return nil, fmt.Errorf("degrees must be >=1 but found %v", degree)
+		}
+		if fraction < 0 {
+			return nil, fmt.Errorf("fractions must be >=0 but found %v for degree %v", fraction, degree)
+		}
+		degrees = append(degrees, degree)
+		total += fraction
+	}
+	if math.Abs(total-1) > 1e-6 {
+		return nil, fmt.Errorf("fractions must sum to 1 but found %v", total)
+	}
+	sort.Ints(degrees)
+
+	counts := make(map[int]int)
+	assigned := 0
+	for _, degree := range degrees {
+		counts[degree] = int(math.Floor(d[degree] * float64(count)))
+		assigned += counts[degree]
+	}
+	byRemainder := append([]int{}, degrees...)
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		ri := d[byRemainder[i]]*float64(count) - float64(counts[byRemainder[i]])
+		rj := d[byRemainder[j]]*float64(count) - float64(counts[byRemainder[j]])
+		return ri > rj
+	})
+	for i := 0; assigned < count; i++ {
+		counts[byRemainder[i%len(byRemainder)]]++
+		assigned++
+	}
+
+	result := make([]int, 0, count)
+	for _, degree := range degrees {
+		for i := 0; i < counts[degree]; i++ {
+			result = append(result, degree)
+		}
+	}
+	return result, nil
+}
+
+// New creates an LDPC code with Progressive Edge Growth from the paper "Regular and Irregular Progressive
+// Edge-Growth Tanner Graphs" by Xiao-Yu Hu, Evangelos Eleftheriou and Dieter M. Arnold. The variable nodes are
+// placed in order of increasing degree and each edge goes to a check node that's as far as possible from the
+// variable node in the graph so far, maximizing its local girth. Ties go to the check node with the most edges
+// left (see checkDegrees) and then at random.
+//
+// The checkDegrees may be nil, then like the original PEG ties go to the check node with the lowest degree so far,
+// keeping the check degrees close to equal without limiting them. Otherwise they're rounded to match the number
+// of edges of the variable nodes and no check node goes past its degree, at the cost of some local girth.
+//
+// When aceDistance > 0 the ACE criterion from "Improved Progressive-Edge-Growth (PEG) Construction of Irregular
+// LDPC Codes" by Hua Xiao and Amir H. Banihashemi breaks the ties before the random choice. For an edge closing
+// a cycle of length <= 2·aceDistance the check node whose cycle has the largest approximate cycle extrinsic
+// message degree, ACE = Σ(d_v-2) over its variable nodes, is used. This keeps the low degree variable nodes
+// out of short cycles that aren't well connected to the rest of the graph.
+//
+// The H matrix is kept as built even when its rows are linearly dependent, see linearblock.RedundantLinearBlock.
+func New(ctx context.Context, checkNodes, variableNodes int, variableDegrees, checkDegrees DegreeDistribution, aceDistance int, threads int) (*linearblock.LinearBlock, error) {
+	H, err := Graph(ctx, checkNodes, variableNodes, variableDegrees, checkDegrees, aceDistance)
+	if err != nil {
+		return nil, err
+	}
+
+	result := linearblock.RedundantLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+// Graph returns the H matrix of the Tanner graph built by New.
+func Graph(ctx context.Context, checkNodes, variableNodes int, variableDegrees, checkDegrees DegreeDistribution, aceDistance int) (mat.SparseMat, error) {
+	if checkNodes < 1 || variableNodes <= checkNodes {
+		return nil, fmt.Errorf("0<checkNodes<variableNodes required but found %v and %v", checkNodes, variableNodes)
+	}
+	if aceDistance < 0 {
+		return nil, fmt.Errorf("aceDistance must be >=0 but found %v", aceDistance)
+	}
+
+	varDegrees, err := variableDegrees.Degrees(variableNodes)
+	if err != nil {
+		return nil, fmt.Errorf("variable node %v", err)
+	}
+	edges := 0
+	for _, d := range varDegrees {
+		if d > checkNodes {
+			return nil, fmt.Errorf("variable node degrees must be <=%v but found %v", checkNodes, d)
+		}
+		edges += d
+	}
+
+	targets, err := checkTargets(checkNodes, edges, checkDegrees)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &graph{
+		varToChecks: make([][]int, variableNodes),
+		checkToVars: make([][]int, checkNodes),
+		varDegrees:  varDegrees,
+		targets:     targets,
+		strict:      checkDegrees != nil,
+		aceDistance: aceDistance,
+	}
+	for v, degree := range varDegrees {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for k := 0; k < degree; k++ {
+			c := g.choose(v)
+			if c < 0 {
+				return nil, fmt.Errorf("unable to place edge %v of variable node %v, the check degrees are exhausted", k, v)
+			}
+			g.varToChecks[v] = append(g.varToChecks[v], c)
+			g.checkToVars[c] = append(g.checkToVars[c], v)
+		}
+		if v%1000 == 0 {
+			logrus.Debugf("PEG placed %v of %v variable nodes", v, variableNodes)
+		}
+	}
+
+	H := mat.CSRMat(checkNodes, variableNodes)
+	for v, checks := range g.varToChecks {
+		for _, c := range checks {
+			H.Set(c, v, 1)
+		}
+	}
+	return H, nil
+}
+
+// checkTargets returns the degree each check node is built up to. Without a distribution they differ by at
+// most one, they only break ties in that case, otherwise the distribution's degrees are adjusted one at a time to add up to the number of edges.
+func checkTargets(checkNodes, edges int, checkDegrees DegreeDistribution) ([]int, error) {
+	targets := make([]int, checkNodes)
+	if checkDegrees == nil {
+		for c := range targets {
+			targets[c] = edges / checkNodes
+			if c < edges%checkNodes {
+				targets[c]++
+			}
+		}
+	} else {
+		degrees, err := checkDegrees.Degrees(checkNodes)
+		if err != nil {
+			return nil, fmt.Errorf("check node %v", err)
+		}
+		copy(targets, degrees)
+
+		total := 0
+		for _, d := range targets {
+			total += d
+		}
+		for c := 0; total != edges; c = (c + 1) % checkNodes {
+			switch {
+			case total < edges:
+				targets[c]++
+				total++
+			case targets[c] > 1:
+				targets[c]--
+				total--
+			}
+		}
+	}
+
+	// the targets are shuffled so the check nodes with the same degree aren't all together
+	random.Shuffle(len(targets), func(i, j int) {
+		targets[i], targets[j] = targets[j], targets[i]
+	})
+	return targets, nil
+}
+
+type graph struct {
+	varToChecks [][]int
+	checkToVars [][]int
+	varDegrees  []int // the final degree of each variable node, used for the ACE
+	targets     []int // the final degree of each check node
+	strict      bool  // when the check nodes can't go past their targets
+	aceDistance int
+}
+
+// choose returns the check node for the next edge of v or -1 when none have edges left
+func (g *graph) choose(v int) int {
+	distance, ace := g.expand(v)
+
+	best := make([]int, 0)
+	bestDistance, bestRemaining, bestACE := -1, 0, math.MinInt
+	for c, d := range distance {
+		remaining := g.targets[c] - len(g.checkToVars[c])
+		if d == 0 || (g.strict && remaining <= 0) {
+			//either it's already connected to v or it's full
+			continue
+		}
+
+		a := math.MaxInt
+		if d < g.aceDistance {
+			a = ace[c]
+		}
+
+		switch {
+		case d > bestDistance,
+			d == bestDistance && remaining > bestRemaining,
+			d == bestDistance && remaining == bestRemaining && a > bestACE:
+			best = append(best[:0], c)
+			bestDistance, bestRemaining, bestACE = d, remaining, a
+		case d == bestDistance && remaining == bestRemaining && a == bestACE:
+			best = append(best, c)
+		}
+	}
+
+	if len(best) == 0 {
+		return -1
+	}
+	return best[random.Intn(len(best))]
+}
+
+// expand grows the tree of the graph from v. The distance of a check node is its depth in the tree, 0 for the
+// ones connected to v and math.MaxInt for the unreached ones, so an edge to a check node at distance d closes a
+// cycle of length 2(d+1). The ace of a check node is the smallest Σ(d_u-2) over the variable nodes on the way
+// to it, including v, which becomes the ACE of that cycle.
+func (g *graph) expand(v int) (distance []int, ace []int) {
+	distance = make([]int, len(g.checkToVars))
+	ace = make([]int, len(g.checkToVars))
+	for c := range distance {
+		distance[c] = math.MaxInt
+		ace[c] = math.MaxInt
+	}
+
+	// the depth of the variable nodes in the tree, -1 when unreached
+	depth := make([]int, len(g.varToChecks))
+	varACE := make([]int, len(g.varToChecks))
+	for u := range depth {
+		depth[u] = -1
+	}
+	depth[v] = 0
+	varACE[v] = g.varDegrees[v] - 2
+
+	frontier := []int{v}
+	for d := 0; len(frontier) > 0; d++ {
+		reached := make([]int, 0)
+		for _, u := range frontier {
+			for _, c := range g.varToChecks[u] {
+				if distance[c] == math.MaxInt {
+					distance[c] = d
+					reached = append(reached, c)
+				}
+				if distance[c] == d && varACE[u] < ace[c] {
+					ace[c] = varACE[u]
+				}
+			}
+		}
+
+		next := make([]int, 0)
+		for _, c := range reached {
+			for _, u := range g.checkToVars[c] {
+				switch depth[u] {
+				case -1:
+					depth[u] = d + 1
+					varACE[u] = math.MaxInt
+					next = append(next, u)
+				case d + 1:
+				default:
+					//reached on an earlier level
+					continue
+				}
+				if through := ace[c] + g.varDegrees[u] - 2; through < varACE[u] {
+					varACE[u] = through
+				}
+			}
+		}
+		frontier = next
+	}
+	return distance, ace
+}
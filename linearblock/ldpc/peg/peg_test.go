@@ -0,0 +1,219 @@
+package peg
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestDegreeDistribution_Degrees(t *testing.T) {
+	tests := []struct {
+		distribution DegreeDistribution
+		count        int
+		expected     []int
+	}{
+		{DegreeDistribution{3: 1}, 4, []int{3, 3, 3, 3}},
+		{DegreeDistribution{2: 0.5, 3: 0.3, 8: 0.2}, 10, []int{2, 2, 2, 2, 2, 3, 3, 3, 8, 8}},
+		{DegreeDistribution{2: 0.5, 3: 0.3, 8: 0.2}, 7, []int{2, 2, 2, 2, 3, 3, 8}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := test.distribution.Degrees(test.count)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("expected %v but found %v", test.expected, actual)
+			}
+		})
+	}
+
+	for i, invalid := range []DegreeDistribution{nil, {0: 1}, {2: 0.5}, {2: 1.5, 3: -0.5}} {
+		if _, err := invalid.Degrees(10); err == nil {
+			t.Fatalf("%v: expected an error for %v", i, invalid)
+		}
+	}
+}
+
+func degrees(H mat.SparseMat) (rowDegrees, columnDegrees []int) {
+	rows, cols := H.Dims()
+	rowDegrees = make([]int, rows)
+	columnDegrees = make([]int, cols)
+	for r := 0; r < rows; r++ {
+		rowDegrees[r] = len(H.Row(r).NonzeroArray())
+	}
+	for c := 0; c < cols; c++ {
+		columnDegrees[c] = len(H.Column(c).NonzeroArray())
+	}
+	return
+}
+
+func TestNew_Regular(t *testing.T) {
+	random = rand.New(rand.NewSource(1))
+	block, err := New(context.Background(), 252, 504, DegreeDistribution{3: 1}, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if !block.Validate() {
+		t.Fatalf("expected valid linearblock code")
+	}
+
+	rowDegrees, columnDegrees := degrees(block.H)
+	// the check degrees aren't limited, only kept close to 6
+	for _, d := range rowDegrees {
+		if d < 5 || 7 < d {
+			t.Fatalf("expected row degree 6±1 but found %v", d)
+		}
+	}
+	for _, d := range columnDegrees {
+		if d != 3 {
+			t.Fatalf("expected column degree 3 but found %v", d)
+		}
+	}
+
+	// PEG reaches girth 8 for the (504,252) regular code
+	if girth := linearblock.CalculateGirth(context.Background(), block.H, 0); girth < 8 {
+		t.Fatalf("expected girth >=8 but found %v", girth)
+	}
+}
+
+func TestNew_Irregular(t *testing.T) {
+	random = rand.New(rand.NewSource(2))
+	variableDegrees := DegreeDistribution{2: 0.5, 3: 0.3, 8: 0.2}
+	tests := []struct {
+		checkDegrees DegreeDistribution
+	}{
+		{nil},
+		{DegreeDistribution{6: 0.5, 8: 0.5}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			H, err := Graph(context.Background(), 200, 400, variableDegrees, test.checkDegrees, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+
+			expected, _ := variableDegrees.Degrees(400)
+			rowDegrees, columnDegrees := degrees(H)
+			if !reflect.DeepEqual(columnDegrees, expected) {
+				t.Fatalf("expected column degrees %v but found %v", expected, columnDegrees)
+			}
+
+			// 1400 edges over 200 check nodes
+			total := 0
+			for _, d := range rowDegrees {
+				total += d
+			}
+			if total != 1400 {
+				t.Fatalf("expected 1400 edges but found %v", total)
+			}
+			if test.checkDegrees != nil {
+				counts := make(map[int]int)
+				for _, d := range rowDegrees {
+					counts[d]++
+				}
+				if counts[6]+counts[7]+counts[8] != 200 {
+					t.Fatalf("expected the check degrees to follow the distribution but found %v", counts)
+				}
+			}
+
+			// holding the check nodes to their degrees can force a 4-cycle near the end
+			if girth := linearblock.CalculateGirth(context.Background(), H, 0); test.checkDegrees == nil && girth < 6 {
+				t.Fatalf("expected girth >=6 but found %v", girth)
+			}
+		})
+	}
+}
+
+// lowACECycles counts the cycles with length <= maxLength whose ACE, Σ(d_v-2), is <= maxACE.
+// Each cycle is counted once per direction it can be walked.
+func lowACECycles(H mat.SparseMat, maxLength, maxACE int) int {
+	_, columnDegrees := degrees(H)
+	_, cols := H.Dims()
+
+	result := 0
+	onPath := make(map[int]bool)
+	var walk func(start, v, checks, ace int, from int)
+	walk = func(start, v, checks, ace int, from int) {
+		for _, c := range H.Column(v).NonzeroArray() {
+			if c == from {
+				continue
+			}
+			for _, u := range H.Row(c).NonzeroArray() {
+				switch {
+				case u == start && checks >= 1:
+					if ace <= maxACE {
+						result++
+					}
+				case u <= start || onPath[u] || 2*(checks+2) > maxLength:
+				default:
+					onPath[u] = true
+					walk(start, u, checks+1, ace+columnDegrees[u]-2, c)
+					delete(onPath, u)
+				}
+			}
+		}
+	}
+	for v := 0; v < cols; v++ {
+		onPath[v] = true
+		walk(v, v, 0, columnDegrees[v]-2, -1)
+		delete(onPath, v)
+	}
+	return result
+}
+
+func TestNew_ACE(t *testing.T) {
+	// lots of degree 2 variable nodes make short cycles with a small ACE likely
+	variableDegrees := DegreeDistribution{2: 0.6, 3: 0.2, 6: 0.2}
+
+	random = rand.New(rand.NewSource(3))
+	plain, err := Graph(context.Background(), 100, 200, variableDegrees, nil, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	random = rand.New(rand.NewSource(3))
+	ace, err := Graph(context.Background(), 100, 200, variableDegrees, nil, 4)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	plainCycles, aceCycles := lowACECycles(plain, 8, 4), lowACECycles(ace, 8, 4)
+	if aceCycles >= plainCycles {
+		t.Fatalf("expected the ACE criterion to remove cycles with ACE <= 4 but found %v vs %v", aceCycles, plainCycles)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		checkNodes, variableNodes int
+		variableDegrees           DegreeDistribution
+		checkDegrees              DegreeDistribution
+		aceDistance               int
+	}{
+		{0, 10, DegreeDistribution{3: 1}, nil, 0},
+		{10, 10, DegreeDistribution{3: 1}, nil, 0},
+		{5, 10, DegreeDistribution{6: 1}, nil, 0},
+		{5, 10, DegreeDistribution{3: 0.5}, nil, 0},
+		{5, 10, DegreeDistribution{3: 1}, DegreeDistribution{0: 1}, 0},
+		{5, 10, DegreeDistribution{3: 1}, nil, -1},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			_, err := New(context.Background(), test.checkNodes, test.variableNodes, test.variableDegrees, test.checkDegrees, test.aceDistance, 0)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Graph(ctx, 50, 100, DegreeDistribution{3: 1}, nil, 0); err == nil {
+		t.Fatalf("expected an error when canceled")
+	}
+}
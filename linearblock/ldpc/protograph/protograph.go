@@ -0,0 +1,221 @@
+package protograph
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/ldpc/qc"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+var random = rand.New(rand.NewSource(time.Now().Unix()))
+
+// Protograph is a small Tanner graph that's made into an LDPC code by copy-and-permute lifting, it's copied Z
+// times and the Z copies of every edge are permuted among the copies. Parallel edges are allowed, the lifting
+// spreads them over different copies. The punctured variable nodes are part of the code but aren't meant to be
+// transmitted, they only help the decoding.
+type Protograph struct {
+	Base      [][]int // Base[i][j] is the number of edges between check node i and variable node j
+	Punctured []int   // the variable nodes that aren't transmitted
+}
+
+// New creates the protograph with the base matrix and punctured variable nodes.
+func New(base [][]int, punctured []int) (*Protograph, error) {
+	p := &Protograph{
+		Base:      base,
+		Punctured: punctured,
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Protograph) validate() error {
+	if len(p.Base) == 0 || len(p.Base[0]) == 0 {
+		return fmt.Errorf("the base matrix must not be empty")
+	}
+	degrees := make([]int, len(p.Base[0]))
+	for i, row := range p.Base {
+		if len(row) != len(p.Base[0]) {
+			return fmt.Errorf("every row of the base matrix must have %v entries but row %v has %v", len(p.Base[0]), i, len(row))
+		}
+		for j, b := range row {
+			if b < 0 {
+				return fmt.Errorf("the number of edges must be >=0 but found %v at (%v,%v)", b, i, j)
+			}
+			degrees[j] += b
+		}
+	}
+	if len(p.Base) >= len(p.Base[0]) {
+		return fmt.Errorf("the base matrix must have fewer rows than columns but found %vx%v", len(p.Base), len(p.Base[0]))
+	}
+	for j, d := range degrees {
+		if d == 0 {
+			return fmt.Errorf("variable node %v has no edges", j)
+		}
+	}
+
+	punctured := make(map[int]bool)
+	for _, j := range p.Punctured {
+		if j < 0 || len(p.Base[0]) <= j {
+			return fmt.Errorf("punctured variable nodes must be in [0,%v) but found %v", len(p.Base[0]), j)
+		}
+		if punctured[j] {
+			return fmt.Errorf("variable node %v is punctured more than once", j)
+		}
+		punctured[j] = true
+	}
+	if len(p.Punctured) >= len(p.Base[0])-len(p.Base) {
+		return fmt.Errorf("at most %v variable nodes can be punctured but found %v", len(p.Base[0])-len(p.Base)-1, len(p.Punctured))
+	}
+	return nil
+}
+
+func (p *Protograph) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("Protograph(Punctured=%v)\n", p.Punctured))
+	for _, row := range p.Base {
+		for j, b := range row {
+			if j > 0 {
+				buf.WriteString(" ")
+			}
+			buf.WriteString(fmt.Sprintf("%2v", b))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// Rate is the design rate of the transmitted bits, (variables-checks)/(variables-punctured).
+func (p *Protograph) Rate() float64 {
+	checks, variables := len(p.Base), len(p.Base[0])
+	return float64(variables-checks) / float64(variables-len(p.Punctured))
+}
+
+// PuncturedColumns returns the columns of H that belong to the punctured variable nodes after lifting by z,
+// copy r of variable node j is column j*z+r (see Lift and QC).
+func (p *Protograph) PuncturedColumns(z int) []int {
+	result := make([]int, 0, len(p.Punctured)*z)
+	for _, j := range p.Punctured {
+		for r := 0; r < z; r++ {
+			result = append(result, j*z+r)
+		}
+	}
+	return result
+}
+
+// Lift copies the protograph z times and connects the z copies of every edge with a random permutation, copy r
+// of check node i becomes check node i*z+r and copy r of variable node j becomes variable node j*z+r. The b
+// parallel edges between a check and a variable node are given the permutations r -> σ((τ(r)+k) mod z) for
+// k<b, which never connect the same copies, so the result has no parallel edges. It can be lifted again by QC
+// or used as is by LinearBlock.
+func (p *Protograph) Lift(z int) (*Protograph, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if z < 1 {
+		return nil, fmt.Errorf("the lifting size must be >=1 but found %v", z)
+	}
+
+	checks, variables := len(p.Base), len(p.Base[0])
+	base := make([][]int, checks*z)
+	for i := range base {
+		base[i] = make([]int, variables*z)
+	}
+	for i, row := range p.Base {
+		for j, b := range row {
+			if b > z {
+				return nil, fmt.Errorf("lifting by %v can't separate the %v parallel edges between check %v and variable %v", z, b, i, j)
+			}
+			if b == 0 {
+				continue
+			}
+
+			sigma := random.Perm(z)
+			tau := random.Perm(z)
+			for r := 0; r < z; r++ {
+				for k := 0; k < b; k++ {
+					base[i*z+r][j*z+sigma[(tau[r]+k)%z]] = 1
+				}
+			}
+		}
+	}
+
+	return New(base, p.PuncturedColumns(z))
+}
+
+// parallelEdges returns an error when the protograph has parallel edges
+func (p *Protograph) parallelEdges() error {
+	for i, row := range p.Base {
+		for j, b := range row {
+			if b > 1 {
+				return fmt.Errorf("the protograph has %v parallel edges between check %v and variable %v, it must be lifted first", b, i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// QC lifts the protograph by z using circulants, with the shifts searched to reach the girth (see qc.Search).
+// Circulants can't separate parallel edges so protographs with them must be lifted (see Lift) first.
+func (p *Protograph) QC(ctx context.Context, z, girth, attempts int) (*qc.QC, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if err := p.parallelEdges(); err != nil {
+		return nil, err
+	}
+	return qc.Search(ctx, p.Base, z, girth, attempts)
+}
+
+// LinearBlock returns the linear block code with the protograph's base matrix as H, it must not have parallel
+// edges (see Lift). The punctured variable nodes are part of the codeword.
+func (p *Protograph) LinearBlock(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if err := p.parallelEdges(); err != nil {
+		return nil, err
+	}
+
+	H := mat.CSRMat(len(p.Base), len(p.Base[0]))
+	for i, row := range p.Base {
+		for j, b := range row {
+			H.Set(i, j, b)
+		}
+	}
+
+	result := linearblock.RedundantLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+	return result, nil
+}
+
+// AR4JA returns the accumulate-repeat-4-jagged-accumulate protograph (Divsalar, Dolinar, Jones and Andrews) with
+// the given number of extensions, its rate is (2+2e)/(4+2e) so 0, 1, 2 and 3 extensions give the rates 1/2, 2/3,
+// 3/4 and 4/5. The rate 1/2, 2/3 and 4/5 members are the ones used by the CCSDS deep space codes. The variable
+// node of degree 6 is punctured.
+func AR4JA(extensions int) (*Protograph, error) {
+	if extensions < 0 {
+		return nil, fmt.Errorf("the number of extensions must be >=0 but found %v", extensions)
+	}
+
+	base := [][]int{
+		{1, 2, 0, 0, 0},
+		{0, 3, 1, 1, 1},
+		{0, 1, 2, 1, 2},
+	}
+	// every extension adds two variable nodes of degree 4, each raising the rate by one information bit
+	for e := 0; e < extensions; e++ {
+		base[0] = append(base[0], 0, 0)
+		base[1] = append(base[1], 3, 1)
+		base[2] = append(base[2], 1, 3)
+	}
+	return New(base, []int{1})
+}
@@ -0,0 +1,167 @@
+package protograph
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+)
+
+func TestAR4JA(t *testing.T) {
+	tests := []struct {
+		extensions int
+		rate       float64
+	}{
+		{0, 1. / 2},
+		{1, 2. / 3},
+		{2, 3. / 4},
+		{3, 4. / 5},
+	}
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.extensions), func(t *testing.T) {
+			p, err := AR4JA(test.extensions)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if math.Abs(p.Rate()-test.rate) > 1e-9 {
+				t.Fatalf("expected rate %v but found %v", test.rate, p.Rate())
+			}
+
+			degree := 0
+			for _, row := range p.Base {
+				degree += row[p.Punctured[0]]
+			}
+			if degree != 6 {
+				t.Fatalf("expected the punctured variable node to have degree 6 but found %v", degree)
+			}
+		})
+	}
+}
+
+func TestProtograph_Lift(t *testing.T) {
+	random = rand.New(rand.NewSource(1))
+	p, _ := AR4JA(1)
+	z := 8
+
+	lifted, err := p.Lift(z)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if len(lifted.Base) != len(p.Base)*z || len(lifted.Base[0]) != len(p.Base[0])*z {
+		t.Fatalf("expected %vx%v but found %vx%v", len(p.Base)*z, len(p.Base[0])*z, len(lifted.Base), len(lifted.Base[0]))
+	}
+	if !reflect.DeepEqual(lifted.Punctured, p.PuncturedColumns(z)) || lifted.Rate() != p.Rate() {
+		t.Fatalf("expected punctured %v but found %v", p.PuncturedColumns(z), lifted.Punctured)
+	}
+
+	// every copy of a node keeps the node's edges to the copies of each of its neighbors
+	for i, row := range p.Base {
+		for j, b := range row {
+			for r := 0; r < z; r++ {
+				toVariable, toCheck := 0, 0
+				for c := 0; c < z; c++ {
+					if lifted.Base[i*z+r][j*z+c] > 1 || lifted.Base[i*z+c][j*z+r] > 1 {
+						t.Fatalf("expected no parallel edges")
+					}
+					toVariable += lifted.Base[i*z+r][j*z+c]
+					toCheck += lifted.Base[i*z+c][j*z+r]
+				}
+				if toVariable != b || toCheck != b {
+					t.Fatalf("expected copy %v of (%v,%v) to have %v edges but found %v and %v", r, i, j, b, toVariable, toCheck)
+				}
+			}
+		}
+	}
+
+	if _, err := p.Lift(2); err == nil {
+		t.Fatalf("expected an error lifting 3 parallel edges by 2")
+	}
+}
+
+func TestProtograph_QC(t *testing.T) {
+	random = rand.New(rand.NewSource(2))
+	p, _ := AR4JA(0)
+	if _, err := p.QC(context.Background(), 16, 6, 10); err == nil {
+		t.Fatalf("expected an error for parallel edges")
+	}
+
+	lifted, err := p.Lift(4)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	q, err := lifted.QC(context.Background(), 16, 8, 1000)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if girth := linearblock.CalculateGirth(context.Background(), q.Expand(), 0); girth < 8 {
+		t.Fatalf("expected girth >=8 but found %v", girth)
+	}
+
+	block, err := q.LinearBlock(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if !block.Validate() {
+		t.Fatalf("expected valid linearblock code")
+	}
+	n := 5 * 4 * 16
+	if block.CodewordLength() != n || block.MessageLength() < n-3*4*16 {
+		t.Fatalf("expected (%v,>=%v) but found (%v,%v)", n, n-3*4*16, block.CodewordLength(), block.MessageLength())
+	}
+}
+
+func TestProtograph_LinearBlock(t *testing.T) {
+	random = rand.New(rand.NewSource(3))
+	p, _ := New([][]int{{1, 1, 1, 1, 0, 0}, {0, 1, 1, 0, 1, 1}, {1, 0, 1, 1, 0, 1}}, nil)
+	lifted, err := p.Lift(20)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	block, err := lifted.LinearBlock(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if !block.Validate() || block.CodewordLength() != 120 {
+		t.Fatalf("expected a valid code with 120 bits but found %v", block.CodewordLength())
+	}
+	for r, row := range lifted.Base {
+		for c, v := range row {
+			if block.H.At(r, c) != v {
+				t.Fatalf("expected H to be the lifted base matrix")
+			}
+		}
+	}
+
+	parallel, _ := New([][]int{{2, 1, 1}}, nil)
+	if _, err := parallel.LinearBlock(context.Background(), 0); err == nil {
+		t.Fatalf("expected an error for parallel edges")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		base      [][]int
+		punctured []int
+	}{
+		{[][]int{}, nil},
+		{[][]int{{1, 1}, {1}}, nil},
+		{[][]int{{1, -1}}, nil},
+		{[][]int{{1, 1}, {1, 1}}, nil},
+		{[][]int{{1, 0, 1}}, nil},
+		{[][]int{{1, 1, 1}}, []int{3}},
+		{[][]int{{1, 1, 1, 1}}, []int{0, 0}},
+		{[][]int{{1, 1, 1}}, []int{0, 1}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(test.base, test.punctured); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
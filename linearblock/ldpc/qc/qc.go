@@ -0,0 +1,298 @@
+package qc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+	"github.com/sirupsen/logrus"
+)
+
+var random = rand.New(rand.NewSource(time.Now().Unix()))
+
+// QC is a quasi-cyclic LDPC code given by its exponent (base) matrix and lifting size Z. Every entry of the
+// exponent matrix becomes a ZxZ block of H, -1 is the all zero block and s>=0 is the identity cyclically shifted
+// right by s, so row r of the block has its one in column (r+s) mod Z.
+type QC struct {
+	Exponents [][]int // Exponents[i][j] is the shift of block row i and block column j, -1 for the zero block
+	Z         int     // the lifting size, the size of every circulant
+}
+
+// New creates the QC-LDPC code of the exponent matrix lifted by z.
+func New(exponents [][]int, z int) (*QC, error) {
+	q := &QC{
+		Exponents: exponents,
+		Z:         z,
+	}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *QC) validate() error {
+	if q.Z < 1 {
+		return fmt.Errorf("the lifting size must be >=1 but found %v", q.Z)
+	}
+	if len(q.Exponents) == 0 || len(q.Exponents[0]) == 0 {
+		return fmt.Errorf("the exponent matrix must not be empty")
+	}
+	for i, row := range q.Exponents {
+		if len(row) != len(q.Exponents[0]) {
+			return fmt.Errorf("every row of the exponent matrix must have %v entries but row %v has %v", len(q.Exponents[0]), i, len(row))
+		}
+		for j, s := range row {
+			if s < -1 || q.Z <= s {
+				return fmt.Errorf("exponents must be -1 or in [0,%v) but found %v at (%v,%v)", q.Z, s, i, j)
+			}
+		}
+	}
+	if len(q.Exponents) >= len(q.Exponents[0]) {
+		return fmt.Errorf("the exponent matrix must have fewer rows than columns but found %vx%v", len(q.Exponents), len(q.Exponents[0]))
+	}
+	return nil
+}
+
+func (q *QC) String() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("QC(Z=%v)\n", q.Z))
+	for _, row := range q.Exponents {
+		for j, s := range row {
+			if j > 0 {
+				buf.WriteString(" ")
+			}
+			buf.WriteString(fmt.Sprintf("%3v", s))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// Expand returns the parity check matrix H made by replacing every exponent with its ZxZ block.
+func (q *QC) Expand() mat.SparseMat {
+	rows, cols := len(q.Exponents), len(q.Exponents[0])
+	H := mat.CSRMat(rows*q.Z, cols*q.Z)
+	for i, row := range q.Exponents {
+		for j, s := range row {
+			if s < 0 {
+				continue
+			}
+			for r := 0; r < q.Z; r++ {
+				H.Set(i*q.Z+r, j*q.Z+(r+s)%q.Z, 1)
+			}
+		}
+	}
+	return H
+}
+
+// LinearBlock returns the linear block code of the expanded H. The circulants often make some rows of H
+// linearly dependent so the generator is made from a basis of the rows (see linearblock.RedundantLinearBlock).
+func (q *QC) LinearBlock(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	result := linearblock.RedundantLinearBlock(ctx, q.Expand(), threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+	return result, nil
+}
+
+// edge is a nonzero block of the exponent matrix
+type edge struct {
+	row, col int
+}
+
+// Girth returns the girth of the expanded H, calculated from the exponent matrix alone. A cycle of length 2l in
+// the Tanner graph of H is a closed walk of length 2l through the blocks, never going straight back, whose
+// alternating sum of shifts is 0 mod Z. For l=2 that's the 4-cycle condition
+//
+//	E[i0][j0]-E[i0][j1]+E[i1][j1]-E[i1][j0] = 0 mod Z
+//
+// and for l=3 the 6-cycle condition
+//
+//	E[i0][j0]-E[i0][j1]+E[i1][j1]-E[i1][j2]+E[i2][j2]-E[i2][j0] = 0 mod Z.
+//
+// It searches for cycles with a length <= maxGirth, if none are found it returns -1.
+func (q *QC) Girth(maxGirth int) int {
+	edges, byRow, byCol := q.edges()
+	for length := 4; length <= maxGirth; length += 2 {
+		for e := range edges {
+			// every closed walk can be rotated (and reversed) to start on its smallest edge going from its row to its
+			// column, so only walks using edges >=e are searched
+			shift := q.Exponents[edges[e].row][edges[e].col]
+			if q.closes(edges, byRow, byCol, e, length, func(coefficient, sum int) bool {
+				return mod(coefficient*shift+sum, q.Z) == 0
+			}) {
+				return length
+			}
+		}
+	}
+	return -1
+}
+
+// edges returns the nonzero blocks and their indices grouped by block row and block column
+func (q *QC) edges() (edges []edge, byRow, byCol [][]int) {
+	byRow = make([][]int, len(q.Exponents))
+	byCol = make([][]int, len(q.Exponents[0]))
+	for i, row := range q.Exponents {
+		for j, s := range row {
+			if s < 0 {
+				continue
+			}
+			byRow[i] = append(byRow[i], len(edges))
+			byCol[j] = append(byCol[j], len(edges))
+			edges = append(edges, edge{i, j})
+		}
+	}
+	return
+}
+
+// closes walks, without going straight back, from the row of edges[start] through edges[start] and then only on
+// edges with an index >= start. Every walk of the length that returns to the row of edges[start] on an edge other
+// than edges[start] is a closed walk, its alternating shift sum is split into the net number of times
+// edges[start] was taken (going row to column adds and column to row subtracts) and the sum of the other shifts.
+// It returns true as soon as found returns true for a closed walk.
+func (q *QC) closes(edges []edge, byRow, byCol [][]int, start, length int, found func(coefficient, sum int) bool) bool {
+	shift := func(e int) (int, int) {
+		if e == start {
+			return 1, 0
+		}
+		return 0, q.Exponents[edges[e].row][edges[e].col]
+	}
+
+	var walk func(e, steps, coefficient, sum int) bool
+	walk = func(e, steps, coefficient, sum int) bool {
+		// we are at the column of e having come from its row, now go to another row
+		for _, f := range byCol[edges[e].col] {
+			if f == e || f < start {
+				continue
+			}
+			c, s := shift(f)
+			c, s = coefficient-c, sum-s
+			if steps+2 == length {
+				if edges[f].row == edges[start].row && f != start && found(c, s) {
+					return true
+				}
+				continue
+			}
+			// and then to another column
+			for _, g := range byRow[edges[f].row] {
+				if g == f || g < start {
+					continue
+				}
+				gc, gs := shift(g)
+				if walk(g, steps+2, c+gc, s+gs) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(start, 0, 1, 0)
+}
+
+// Search looks for the shifts of the base matrix, where base[i][j]!=0 marks a circulant and 0 the zero block, that
+// give a girth >= girth when lifted by z. Shifts are picked at random one block at a time among the values that
+// close no cycle shorter than girth with the blocks already picked, starting over when a block has none left.
+// It gives up after attempts tries.
+func Search(ctx context.Context, base [][]int, z, girth, attempts int) (*QC, error) {
+	exponents := make([][]int, len(base))
+	for i, row := range base {
+		exponents[i] = make([]int, len(row))
+		for j, b := range row {
+			exponents[i][j] = -1
+			if b != 0 {
+				exponents[i][j] = 0
+			}
+		}
+	}
+	q, err := New(exponents, z)
+	if err != nil {
+		return nil, err
+	}
+	if girth < 4 {
+		return nil, fmt.Errorf("girth must be >=4 but found %v", girth)
+	}
+	if attempts < 1 {
+		return nil, fmt.Errorf("attempts must be >=1 but found %v", attempts)
+	}
+
+	edges, _, _ := q.edges()
+	for attempt := 0; attempt < attempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		if q.place(edges, girth) {
+			logrus.Debugf("QC search found girth >=%v after %v attempts", girth, attempt+1)
+			return q, nil
+		}
+	}
+	return nil, fmt.Errorf("no shifts with girth >=%v found in %v attempts", girth, attempts)
+}
+
+// place picks the shift of every edge, in a random order, so no cycle shorter than girth is closed. It returns
+// false when an edge has no shift left.
+func (q *QC) place(edges []edge, girth int) bool {
+	// the placed edges are given the smallest indices so closes only walks over them
+	order := random.Perm(len(edges))
+	placed := make([]edge, 0, len(edges))
+	for _, e := range order {
+		placed = append(placed, edge{})
+		copy(placed[1:], placed)
+		placed[0] = edges[e]
+
+		byRow := make([][]int, len(q.Exponents))
+		byCol := make([][]int, len(q.Exponents[0]))
+		for i, p := range placed {
+			byRow[p.row] = append(byRow[p.row], i)
+			byCol[p.col] = append(byCol[p.col], i)
+		}
+
+		// a closed walk taking the new edge a net c times with the others summing to s forbids every shift x
+		// with c*x+s = 0 mod Z
+		forbidden := make([]bool, q.Z)
+		impossible := false
+		for length := 4; length < girth && !impossible; length += 2 {
+			q.closes(placed, byRow, byCol, 0, length, func(coefficient, sum int) bool {
+				if mod(coefficient, q.Z) == 0 {
+					impossible = mod(sum, q.Z) == 0
+					return impossible
+				}
+				for x := 0; x < q.Z; x++ {
+					if mod(coefficient*x+sum, q.Z) == 0 {
+						forbidden[x] = true
+					}
+				}
+				return false
+			})
+		}
+		if impossible {
+			return false
+		}
+
+		allowed := make([]int, 0, q.Z)
+		for x, f := range forbidden {
+			if !f {
+				allowed = append(allowed, x)
+			}
+		}
+		if len(allowed) == 0 {
+			return false
+		}
+		q.Exponents[placed[0].row][placed[0].col] = allowed[random.Intn(len(allowed))]
+	}
+	return true
+}
+
+func mod(a, z int) int {
+	return ((a % z) + z) % z
+}
@@ -0,0 +1,205 @@
+package qc
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+)
+
+func randomExponents(random *rand.Rand, rows, cols, z int, zeros float64) [][]int {
+	exponents := make([][]int, rows)
+	for i := range exponents {
+		exponents[i] = make([]int, cols)
+		for j := range exponents[i] {
+			exponents[i][j] = random.Intn(z)
+			if random.Float64() < zeros {
+				exponents[i][j] = -1
+			}
+		}
+	}
+	return exponents
+}
+
+func TestQC_Expand(t *testing.T) {
+	q, err := New([][]int{{0, 1, -1}, {2, -1, 0}}, 3)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	expected := [][]int{
+		{1, 0, 0, 0, 1, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 1, 0, 0, 0},
+		{0, 0, 1, 1, 0, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0, 0, 1, 0, 0},
+		{1, 0, 0, 0, 0, 0, 0, 1, 0},
+		{0, 1, 0, 0, 0, 0, 0, 0, 1},
+	}
+	H := q.Expand()
+	for r, row := range expected {
+		for c, v := range row {
+			if H.At(r, c) != v {
+				t.Fatalf("expected H[%v][%v]=%v but found \n%v", r, c, v, H)
+			}
+		}
+	}
+}
+
+func TestQC_Girth(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	tests := []struct {
+		rows, cols, z int
+		zeros         float64
+	}{
+		{2, 4, 3, 0},
+		{2, 4, 7, 0},
+		{3, 6, 5, 0.2},
+		{3, 6, 13, 0},
+		{4, 8, 11, 0.4},
+		{3, 5, 31, 0.1},
+		{3, 4, 61, 0},
+		{2, 3, 101, 0},
+		{3, 5, 47, 0.3},
+	}
+	for i, test := range tests {
+		for trial := 0; trial < 10; trial++ {
+			t.Run(strconv.Itoa(i)+"/"+strconv.Itoa(trial), func(t *testing.T) {
+				q, err := New(randomExponents(random, test.rows, test.cols, test.z, test.zeros), test.z)
+				if err != nil {
+					t.Fatalf("expected no error but found: %v", err)
+				}
+
+				expected := linearblock.CalculateGirthLowerBound(context.Background(), q.Expand(), 12, 0)
+				if actual := q.Girth(12); actual != expected {
+					t.Fatalf("expected girth %v but found %v for\n%v", expected, actual, q)
+				}
+			})
+		}
+	}
+}
+
+func TestQC_Girth4And6(t *testing.T) {
+	// 0-1+3-2 = 0 mod 5 is a 4-cycle
+	q, _ := New([][]int{{0, 1, 0}, {2, 3, 0}}, 5)
+	if girth := q.Girth(12); girth != 4 {
+		t.Fatalf("expected girth 4 but found %v", girth)
+	}
+
+	// no 4-cycles but 0-0+1-0+0-1 = 0 mod 5 is a 6-cycle
+	q, _ = New([][]int{{0, 0, -1, 0}, {-1, 1, 0, 2}, {1, -1, 0, 4}}, 5)
+	if girth := q.Girth(12); girth != 6 {
+		t.Fatalf("expected girth 6 but found %v", girth)
+	}
+
+	// a single circulant in every column has no cycles at all
+	q, _ = New([][]int{{0, 1, -1}, {-1, -1, 2}}, 5)
+	if girth := q.Girth(12); girth != -1 {
+		t.Fatalf("expected no cycles but found girth %v", girth)
+	}
+}
+
+func TestQC_LinearBlock(t *testing.T) {
+	// every column of the base matrix has weight 2 so the rows of H sum to 0 and H is rank deficient
+	q, _ := New([][]int{{0, 1, 2, 3, 4, 5}, {0, 2, 4, 6, 8, 10}}, 11)
+	block, err := q.LinearBlock(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if !block.Validate() {
+		t.Fatalf("expected valid linearblock code")
+	}
+	if block.CodewordLength() != 66 || block.MessageLength() != 45 {
+		t.Fatalf("expected (66,45) but found (%v,%v)", block.CodewordLength(), block.MessageLength())
+	}
+	if !block.H.Equals(q.Expand()) {
+		t.Fatalf("expected H to be the expanded exponent matrix")
+	}
+}
+
+func TestQC_JSON(t *testing.T) {
+	q, _ := New([][]int{{0, 1, -1, 3}, {2, -1, 0, 1}}, 5)
+	bs, err := json.Marshal(q)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	var actual QC
+	if err := json.Unmarshal(bs, &actual); err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if !reflect.DeepEqual(*q, actual) {
+		t.Fatalf("expected %v but found %v", q, actual)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	random = rand.New(rand.NewSource(1))
+	tests := []struct {
+		rows, cols, z, girth int
+	}{
+		{3, 6, 13, 6},
+		{3, 6, 31, 8},
+		{3, 4, 37, 10},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			base := make([][]int, test.rows)
+			for r := range base {
+				base[r] = make([]int, test.cols)
+				for c := range base[r] {
+					base[r][c] = 1
+				}
+			}
+
+			q, err := Search(context.Background(), base, test.z, test.girth, 1000)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if girth := q.Girth(12); girth < test.girth {
+				t.Fatalf("expected girth >=%v but found %v", test.girth, girth)
+			}
+			if girth := linearblock.CalculateGirth(context.Background(), q.Expand(), 0); girth < test.girth {
+				t.Fatalf("expected expanded girth >=%v but found %v", test.girth, girth)
+			}
+		})
+	}
+}
+
+func TestSearch_Impossible(t *testing.T) {
+	// a 2x3 base matrix of circulants always has a 12-cycle
+	base := [][]int{{1, 1, 1}, {1, 1, 1}}
+	if _, err := Search(context.Background(), base, 101, 14, 10); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Search(ctx, base, 101, 6, 10); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		exponents [][]int
+		z         int
+	}{
+		{[][]int{{0, 1}}, 0},
+		{[][]int{}, 3},
+		{[][]int{{0, 1}, {0}}, 3},
+		{[][]int{{0, 3}}, 3},
+		{[][]int{{0, -2}}, 3},
+		{[][]int{{0, 1}, {1, 0}}, 3},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(test.exponents, test.z); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
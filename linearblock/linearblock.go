@@ -15,11 +15,19 @@ import (
 type Systematic struct {
 	HColumnOrder []int
 	G            mat.SparseMat
+	Encoder      Encoder `json:"-"` // used instead of G when G is nil, for codes with an encoder that's cheaper than a (dense) G
+}
+
+// Encoder encodes without a generator matrix, Encode must return the systematic codeword message*G.
+type Encoder interface {
+	MessageLength() int
+	CodewordLength() int
+	Encode(message mat.SparseVector) (codeword mat.SparseVector)
 }
 
 // LinearBlock contains matrices for the original H matrix and the systematic G generator.
 type LinearBlock struct {
-	H          mat.SparseMat //the original H(parity) matrix
+	H          mat.SparseMat //the original H(parity) matrix, its rows may be linearly dependent (see RedundantLinearBlock)
 	Processing *Systematic   // contains systematic generator matrix
 }
 
@@ -57,6 +65,12 @@ func (l *LinearBlock) UnmarshalJSON(bytes []byte) error {
 // Encode take in a message and encodes it using the linear block, returning a codeword
 func (l *LinearBlock) Encode(message mat.SparseVector) (codeword mat.SparseVector) {
 	G := l.Processing.G
+	if G == nil {
+		if message.Len() != l.MessageLength() {
+			panic(fmt.Sprintf("message length == %v is required but found %v", l.MessageLength(), message.Len()))
+		}
+		return ToNonSystematic(l.Processing.Encoder.Encode(message), l.Processing.HColumnOrder)
+	}
 	rows, cols := G.Dims()
 	if message.Len() != rows {
 		panic(fmt.Sprintf("message length == %v is required but found %v", rows, message.Len()))
@@ -103,20 +117,26 @@ func (l *LinearBlock) DecodeBE(codeword []bec.ErasureBit) (message []bec.Erasure
 }
 
 func (l *LinearBlock) Syndrome(codeword mat.SparseVector) (syndrome mat.SparseVector) {
-	syndrome = mat.CSRVec(l.ParitySymbols())
+	rows, _ := l.H.Dims()
+	syndrome = mat.CSRVec(rows)
 	syndrome.MatMul(l.H, codeword)
 	return
 }
 
 func (l *LinearBlock) MessageLength() int {
+	if l.Processing.G == nil {
+		return l.Processing.Encoder.MessageLength()
+	}
 	k, _ := l.Processing.G.Dims()
 	return k
 }
 func (l *LinearBlock) ParitySymbols() int {
-	k, n := l.Processing.G.Dims()
-	return n - k
+	return l.CodewordLength() - l.MessageLength()
 }
 func (l *LinearBlock) CodewordLength() int {
+	if l.Processing.G == nil {
+		return l.Processing.Encoder.CodewordLength()
+	}
 	_, n := l.Processing.G.Dims()
 	return n
 }
@@ -124,8 +144,21 @@ func (l *LinearBlock) CodeRate() float64 {
 	return float64(l.MessageLength()) / float64(l.CodewordLength())
 }
 
-// Validate will test if this linearblock satisfies G*H.T=0, where G is the generator matrix and H.T is the transpose of H
+// Validate will test if this linearblock satisfies G*H.T=0, where G is the generator matrix and H.T is the transpose of H.
+// Without G the rows of G are the encodings of the unit messages.
 func (l *LinearBlock) Validate() bool {
+	if l.Processing.G == nil {
+		k := l.MessageLength()
+		for i := 0; i < k; i++ {
+			message := mat.CSRVec(k)
+			message.Set(i, 1)
+			if !l.Syndrome(l.Encode(message)).IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+
 	//now we validate it
 	return internal.ValidateHGMatrices(l.Processing.G, internal.ColumnSwapped(l.H, l.Processing.HColumnOrder))
 }
@@ -135,8 +168,12 @@ func (l *LinearBlock) String() string {
 	buf.WriteString("{\nH:\n")
 	buf.WriteString(l.H.String())
 	buf.WriteString(fmt.Sprintf("Order: %v", l.Processing.HColumnOrder))
-	buf.WriteString("\nG:\n")
-	buf.WriteString(l.Processing.G.String())
+	if l.Processing.G == nil {
+		buf.WriteString(fmt.Sprintf("\nEncoder: %T", l.Processing.Encoder))
+	} else {
+		buf.WriteString("\nG:\n")
+		buf.WriteString(l.Processing.G.String())
+	}
 	buf.WriteString("\n}\n")
 	return buf.String()
 }
@@ -324,3 +361,22 @@ func SystematicLinearBlock(ctx context.Context, H mat.SparseMat, threads int) *L
 		},
 	}
 }
+
+// RedundantLinearBlock is SystematicLinearBlock for an H whose rows may be linearly dependent, like the
+// incidence matrices of finite geometries. The generator is made from a basis of the rows of H but all of H
+// is kept as the parity checks since decoders like one-step majority-logic rely on the redundant ones.
+func RedundantLinearBlock(ctx context.Context, H mat.SparseMat, threads int) *LinearBlock {
+	_, cols := H.Dims()
+	independent := internal.PivotColumnsGF2(ctx, H.T(), threads)
+	basis := mat.CSRMat(len(independent), cols)
+	for i, r := range independent {
+		basis.SetRow(i, H.Row(r))
+	}
+
+	result := SystematicLinearBlock(ctx, basis, threads)
+	if result == nil {
+		return nil
+	}
+	result.H = mat.CSRMatCopy(H)
+	return result
+}
@@ -1,5 +1,11 @@
 package bec
 
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+)
+
 type ErasureBit int
 
 const (
@@ -12,12 +18,52 @@ type BECFlippingAlg interface {
 	Flip(currentCodeword []ErasureBit) (nextCodeword []ErasureBit, done bool)
 }
 
+// ContextFlippingAlg is a BECFlippingAlg where a single flip can take long enough
+// that it should stop as soon as the context is canceled, see ml.ML.
+type ContextFlippingAlg interface {
+	BECFlippingAlg
+	FlipContext(ctx context.Context, currentCodeword []ErasureBit) (nextCodeword []ErasureBit, done bool)
+}
+
+// Flipping runs the alg until it is done. Since every flip that isn't done should resolve
+// at least one erasure it stops after len(codeword)+1 flips, see FlippingContext.
 func Flipping(alg BECFlippingAlg, codeword []ErasureBit) (result []ErasureBit) {
-	done := false
+	result, _, _ = FlippingContext(context.Background(), alg, codeword, len(codeword)+1)
+	return result
+}
+
+// FlippingContext runs the alg for at most maxIter flips or until it is done. It returns the result along
+// with the number of flips used and why it stopped, Converged when no erasures remain, Stalled when the
+// alg is done but erasures remain, MaxIterations or Canceled when the ctx is canceled.
+func FlippingContext(ctx context.Context, alg BECFlippingAlg, codeword []ErasureBit, maxIter int) (result []ErasureBit, iterations int, stop messagepassing.StopReason) {
 	result = codeword
+	contextAlg, hasContext := alg.(ContextFlippingAlg)
 
+	done := false
 	for !done {
-		result, done = alg.Flip(result)
+		if ctx.Err() != nil {
+			return result, iterations, messagepassing.Canceled
+		}
+		if iterations >= maxIter {
+			return result, iterations, messagepassing.MaxIterations
+		}
+
+		if hasContext {
+			result, done = contextAlg.FlipContext(ctx, result)
+		} else {
+			result, done = alg.Flip(result)
+		}
+		iterations++
 	}
-	return result
+
+	//the last flip may have been cut short
+	if ctx.Err() != nil {
+		return result, iterations, messagepassing.Canceled
+	}
+	for _, b := range result {
+		if b == Erased {
+			return result, iterations, messagepassing.Stalled
+		}
+	}
+	return result, iterations, messagepassing.Converged
 }
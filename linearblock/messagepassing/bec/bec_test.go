@@ -0,0 +1,65 @@
+package bec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+)
+
+// never is a misbehaving alg that is never done
+type never struct {
+	flips int
+}
+
+func (n *never) Flip(currentCodeword []ErasureBit) (nextCodeword []ErasureBit, done bool) {
+	n.flips++
+	return currentCodeword, false
+}
+
+// stuck is done without resolving anything
+type stuck struct{}
+
+func (stuck) Flip(currentCodeword []ErasureBit) (nextCodeword []ErasureBit, done bool) {
+	return currentCodeword, true
+}
+
+func TestFlipping_Bounded(t *testing.T) {
+	codeword := []ErasureBit{Zero, Erased, One}
+
+	alg := &never{}
+	Flipping(alg, codeword)
+	if alg.flips != len(codeword)+1 {
+		t.Fatalf("expected %v flips but found %v", len(codeword)+1, alg.flips)
+	}
+
+	_, iterations, stop := FlippingContext(context.Background(), &never{}, codeword, 10)
+	if stop != messagepassing.MaxIterations || iterations != 10 {
+		t.Fatalf("expected %v after 10 iterations but found %v after %v", messagepassing.MaxIterations, stop, iterations)
+	}
+}
+
+func TestFlippingContext_StopReasons(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		alg      BECFlippingAlg
+		codeword []ErasureBit
+		expected messagepassing.StopReason
+	}{
+		{"Converged", context.Background(), stuck{}, []ErasureBit{Zero, One}, messagepassing.Converged},
+		{"Stalled", context.Background(), stuck{}, []ErasureBit{Zero, Erased}, messagepassing.Stalled},
+		{"Canceled", ctx, &never{}, []ErasureBit{Zero, Erased}, messagepassing.Canceled},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, stop := FlippingContext(test.ctx, test.alg, test.codeword, 10)
+			if stop != test.expected {
+				t.Fatalf("expected %v but found %v", test.expected, stop)
+			}
+		})
+	}
+}
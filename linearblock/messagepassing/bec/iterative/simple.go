@@ -1,21 +1,28 @@
 package iterative
 
 import (
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
 	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
 	mat "github.com/nathanhack/sparsemat"
 )
 
 type Simple struct {
-	H           mat.SparseMat
-	checkToVars [][]int
-	varToChecks [][]int
+	H      mat.SparseMat
+	tanner *messagepassing.TannerGraph
+}
+
+// NewSimple creates a Simple with its Tanner graph already built so it can be shared between threads.
+func NewSimple(H mat.SparseMat) *Simple {
+	s := &Simple{H: H}
+	s.init()
+	return s
 }
 
 func (s *Simple) Flip(currentCodeword []bec.ErasureBit) (nextCodeword []bec.ErasureBit, done bool) {
 	if s.H == nil {
 		panic("Simple BEC flipping algorithm must have the H parity matrix set before using")
 	}
-	if s.checkToVars == nil {
+	if s.tanner == nil {
 		s.init()
 	}
 
@@ -30,12 +37,12 @@ func (s *Simple) Flip(currentCodeword []bec.ErasureBit) (nextCodeword []bec.Eras
 
 		checksCompleted := make(map[int]bool)
 		for _, erasedBit := range erasedBits {
-			for _, row := range s.varToChecks[erasedBit] {
+			for _, row := range s.tanner.VarToChecks[erasedBit] {
 				if _, has := checksCompleted[row]; has {
 					continue
 				}
 
-				if progressM(nextCodeword, s.checkToVars[row]) {
+				if progressM(nextCodeword, s.tanner.CheckToVars[row]) {
 					progress = true
 				}
 			}
@@ -52,23 +59,10 @@ func (s *Simple) Flip(currentCodeword []bec.ErasureBit) (nextCodeword []bec.Eras
 }
 
 func (s *Simple) init() {
-	if s.checkToVars != nil {
+	if s.tanner != nil {
 		return
 	}
-	rows, cols := s.H.Dims()
-
-	s.varToChecks = make([][]int, cols)
-	for v := range s.varToChecks {
-		s.varToChecks[v] = make([]int, 0)
-	}
-
-	s.checkToVars = make([][]int, rows)
-	for c := range s.checkToVars {
-		s.checkToVars[c] = s.H.Row(c).NonzeroArray()
-		for _, v := range s.checkToVars[c] {
-			s.varToChecks[v] = append(s.varToChecks[v], c)
-		}
-	}
+	s.tanner = messagepassing.NewTannerGraph(s.H)
 }
 
 func getErasedIndices(m []bec.ErasureBit) []int {
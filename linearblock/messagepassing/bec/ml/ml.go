@@ -0,0 +1,164 @@
+package ml
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nathanhack/ecc/linearblock/internal"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec/iterative"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// ML is the maximum-likelihood erasure decoder. The erased bits x_E must satisfy
+// H_E*x_E = H_K*x_K where E are the erased columns of H and K the known columns.
+// It solves this system with Gaussian-Jordan elimination over GF(2), so every erased bit that
+// has a unique value is recovered, including those in stopping sets that peeling can't fix.
+// Bits with more than one possible value are left erased.
+type ML struct {
+	H       mat.SparseMat
+	Threads int // threads used during elimination, <=0 will use runtime.NumCPU()
+}
+
+func (m *ML) Flip(currentCodeword []bec.ErasureBit) (nextCodeword []bec.ErasureBit, done bool) {
+	return m.FlipContext(context.Background(), currentCodeword)
+}
+
+// FlipContext is Flip where the elimination stops as soon as the ctx is canceled,
+// in which case the codeword is returned unchanged.
+func (m *ML) FlipContext(ctx context.Context, currentCodeword []bec.ErasureBit) (nextCodeword []bec.ErasureBit, done bool) {
+	if m.H == nil {
+		panic("ML BEC algorithm must have the H parity matrix set before using")
+	}
+
+	nextCodeword = make([]bec.ErasureBit, len(currentCodeword))
+	copy(nextCodeword, currentCodeword)
+
+	erased := make([]int, 0)
+	for i, b := range nextCodeword {
+		if b == bec.Erased {
+			erased = append(erased, i)
+		}
+	}
+	if len(erased) == 0 {
+		return nextCodeword, true
+	}
+
+	rows, _ := m.H.Dims()
+	e := len(erased)
+
+	// the syndrome of the known bits is the right hand side
+	syndrome := make([]int, rows)
+	for r := 0; r < rows; r++ {
+		for _, v := range m.H.Row(r).NonzeroArray() {
+			if nextCodeword[v] != bec.Erased {
+				syndrome[r] += int(nextCodeword[v])
+			}
+		}
+		syndrome[r] %= 2
+	}
+
+	// first we find the linearly independent erased columns, these are the pivots
+	subH := mat.CSRMat(rows, e)
+	for j, v := range erased {
+		subH.SetColumn(j, m.H.Column(v))
+	}
+	pivotColumns := internal.PivotColumnsGF2(ctx, subH, m.Threads)
+
+	// then we eliminate [H_P, H_F, I] where P are the pivot columns and F the free columns.
+	// With the pivots first they are guaranteed to stay pivots. The identity part keeps
+	// track of the row operations (T) so we can apply them to the syndrome afterwards.
+	pivots := len(pivotColumns)
+	columns := make([]int, 0, e)
+	isPivot := make([]bool, e)
+	for _, j := range pivotColumns {
+		columns = append(columns, erased[j])
+		isPivot[j] = true
+	}
+	for j, v := range erased {
+		if !isPivot[j] {
+			columns = append(columns, v)
+		}
+	}
+	erased = columns
+
+	system := mat.CSRMat(rows, e+rows)
+	for j, v := range erased {
+		system.SetColumn(j, m.H.Column(v))
+	}
+	for r := 0; r < rows; r++ {
+		system.Set(r, e+r, 1)
+	}
+
+	reduced, ordering := internal.GaussianJordanEliminationGF2(ctx, system, m.Threads)
+	if ctx.Err() != nil {
+		//the elimination was cut short
+		copy(nextCodeword, currentCodeword)
+		return nextCodeword, true
+	}
+	rank, _ := reduced.Dims()
+
+	isPivot = make([]bool, e)
+	for j := 0; j < pivots; j++ {
+		isPivot[j] = true
+	}
+
+	for r := 0; r < rank; r++ {
+		if ordering[r] >= e {
+			continue
+		}
+
+		// this pivot has a unique value only when it doesn't
+		// depend on any of the free (non-pivot) erased bits
+		// and its value is the transformed syndrome T*s
+		row := reduced.Row(r)
+		unique := true
+		value := 0
+		for _, p := range row.NonzeroArray() {
+			c := ordering[p]
+			switch {
+			case c < e && !isPivot[c]:
+				unique = false
+			case c >= e:
+				value += syndrome[c-e]
+			}
+		}
+		if unique {
+			nextCodeword[erased[ordering[r]]] = bec.ErasureBit(value % 2)
+		}
+	}
+
+	return nextCodeword, true
+}
+
+// Hybrid first peels (see iterative.Simple) and only uses ML elimination on the erasures that remain,
+// which keeps the elimination small when peeling does most of the work.
+type Hybrid struct {
+	H       mat.SparseMat
+	Threads int // threads used during elimination, <=0 will use runtime.NumCPU()
+	once    sync.Once
+	peeling *iterative.Simple
+	ml      *ML
+}
+
+func (h *Hybrid) Flip(currentCodeword []bec.ErasureBit) (nextCodeword []bec.ErasureBit, done bool) {
+	return h.FlipContext(context.Background(), currentCodeword)
+}
+
+// FlipContext is Flip where the ML elimination stops as soon as the ctx is canceled, see ML.FlipContext.
+func (h *Hybrid) FlipContext(ctx context.Context, currentCodeword []bec.ErasureBit) (nextCodeword []bec.ErasureBit, done bool) {
+	if h.H == nil {
+		panic("Hybrid BEC algorithm must have the H parity matrix set before using")
+	}
+	h.once.Do(func() {
+		h.peeling = iterative.NewSimple(h.H)
+		h.ml = &ML{H: h.H, Threads: h.Threads}
+	})
+
+	nextCodeword, _, stop := bec.FlippingContext(ctx, h.peeling, currentCodeword, len(currentCodeword)+1)
+	if stop == messagepassing.Stalled {
+		return h.ml.FlipContext(ctx, nextCodeword)
+	}
+	return nextCodeword, true
+}
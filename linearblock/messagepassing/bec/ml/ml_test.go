@@ -0,0 +1,145 @@
+package ml
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec"
+	"github.com/nathanhack/ecc/linearblock/messagepassing/bec/iterative"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestML_StoppingSet(t *testing.T) {
+	// erasing 0,1,2 is a stopping set: every check has at least two
+	// erased bits but the erased columns are linearly independent
+	H := mat.CSRMat(4, 7,
+		1, 1, 0, 1, 0, 0, 0,
+		0, 1, 1, 0, 1, 0, 0,
+		1, 0, 1, 0, 0, 1, 0,
+		1, 1, 1, 0, 0, 0, 1)
+	codeword := []bec.ErasureBit{bec.Erased, bec.Erased, bec.Erased, 1, 1, 0, 0}
+	expected := []bec.ErasureBit{1, 0, 1, 1, 1, 0, 0}
+
+	peeled := bec.Flipping(&iterative.Simple{H: H}, codeword)
+	if !reflect.DeepEqual(peeled, codeword) {
+		t.Fatalf("expected peeling to be stuck but found %v", peeled)
+	}
+
+	for name, alg := range map[string]bec.BECFlippingAlg{"ML": &ML{H: H, Threads: 1}, "Hybrid": &Hybrid{H: H, Threads: 1}} {
+		actual := bec.Flipping(alg, codeword)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%v expected %v but found %v", name, expected, actual)
+		}
+	}
+}
+
+func TestML_HammingAllErasurePatterns(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	n := block.CodewordLength()
+	k := block.MessageLength()
+
+	codewords := make([][]bec.ErasureBit, 0, 1<<k)
+	for m := 0; m < 1<<k; m++ {
+		message := mat.CSRVec(k)
+		for i := 0; i < k; i++ {
+			message.Set(i, (m>>i)&1)
+		}
+		codewords = append(codewords, block.EncodeBE(message))
+	}
+
+	algs := map[string]bec.BECFlippingAlg{
+		"ML":     &ML{H: block.H, Threads: 1},
+		"Hybrid": &Hybrid{H: block.H, Threads: 1},
+	}
+
+	for c, codeword := range codewords {
+		for pattern := 0; pattern < 1<<n; pattern++ {
+			received := make([]bec.ErasureBit, n)
+			copy(received, codeword)
+			for i := 0; i < n; i++ {
+				if pattern&(1<<i) > 0 {
+					received[i] = bec.Erased
+				}
+			}
+			expected := bruteForce(codewords, received)
+
+			for name, alg := range algs {
+				t.Run(name+strconv.Itoa(c)+"_"+strconv.Itoa(pattern), func(t *testing.T) {
+					actual := bec.Flipping(alg, received)
+					if !reflect.DeepEqual(actual, expected) {
+						t.Fatalf("expected %v but found %v", expected, actual)
+					}
+				})
+			}
+		}
+	}
+}
+
+// bruteForce finds every codeword matching the received bits and
+// returns the bits that all of them agree on, the rest are erased
+func bruteForce(codewords [][]bec.ErasureBit, received []bec.ErasureBit) []bec.ErasureBit {
+	result := make([]bec.ErasureBit, len(received))
+	first := true
+	for _, codeword := range codewords {
+		matches := true
+		for i, b := range received {
+			if b != bec.Erased && b != codeword[i] {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if first {
+			copy(result, codeword)
+			first = false
+			continue
+		}
+		for i := range result {
+			if result[i] != codeword[i] {
+				result[i] = bec.Erased
+			}
+		}
+	}
+	return result
+}
+
+func TestErasureDecoder(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	message := mat.DOKVec(4, 1, 1, 0, 1)
+	codeword := block.EncodeBE(message)
+
+	decoder := &linearblock.ErasureDecoder{Block: block, Alg: &ML{H: block.H, Threads: 1}}
+
+	received := make([]bec.ErasureBit, len(codeword))
+	copy(received, codeword)
+	received[0], received[4], received[6] = bec.Erased, bec.Erased, bec.Erased
+	actual := decoder.Decode(context.Background(), linearblock.Received{Erasures: received})
+	if !actual.Converged || !reflect.DeepEqual(actual.Erasures, codeword) {
+		t.Fatalf("expected to converge to %v but found %v", codeword, actual.Erasures)
+	}
+	if !actual.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v", message, actual.Message)
+	}
+
+	// more erasures than parity symbols can't all be resolved
+	for i := range received {
+		received[i] = bec.Erased
+	}
+	received[1] = codeword[1]
+	actual = decoder.Decode(context.Background(), linearblock.Received{Erasures: received})
+	if actual.Converged {
+		t.Fatalf("expected unresolved erasures but found %v", actual.Erasures)
+	}
+}
@@ -1,6 +1,10 @@
 package harddecision
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
 	mat "github.com/nathanhack/sparsemat"
 )
 
@@ -9,14 +13,110 @@ type BitFlippingAlg interface {
 	Reset() //resets internal state for next codeword
 }
 
+// ReliabilityBitFlippingAlg is a BitFlippingAlg that weighs its flips with the channel reliabilities.
+// SetChannelLLR is called before the codeword is corrected, a nil channelLLR means only hard decisions
+// were received and every bit is equally reliable.
+type ReliabilityBitFlippingAlg interface {
+	BitFlippingAlg
+	SetChannelLLR(channelLLR []float64)
+}
+
+// LayeredBitFlippingAlg is a BitFlippingAlg that can restrict its flips to the bits of a layer, which the
+// Layered schedule requires. FlipLayer still sees the full syndrome, layer[n] is true when bit n may be flipped
+// and a nil layer allows every bit (same as Flip).
+type LayeredBitFlippingAlg interface {
+	BitFlippingAlg
+	FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool)
+}
+
 func BitFlipping(bitFlippingAlg BitFlippingAlg, H mat.SparseMat, codeword mat.SparseVector, maxIter int) (result mat.SparseVector) {
-	done := false
+	result, _ = BitFlippingSchedule(bitFlippingAlg, H, codeword, maxIter, messagepassing.Flooding, 0)
+	return result
+}
+
+// BitFlippingSchedule runs the bitFlippingAlg for at most maxIter iterations using the given schedule
+// and returns the result along with the number of iterations used, see BitFlippingContext.
+func BitFlippingSchedule(bitFlippingAlg BitFlippingAlg, H mat.SparseMat, codeword mat.SparseVector, maxIter int, schedule messagepassing.Schedule, layerSize int) (result mat.SparseVector, iterations int) {
+	result, iterations, _ = BitFlippingContext(context.Background(), bitFlippingAlg, H, codeword, maxIter, schedule, layerSize)
+	return result, iterations
+}
+
+// BitFlippingContext runs the bitFlippingAlg for at most maxIter iterations using the given schedule
+// and returns the result along with the number of iterations used and why it stopped. With Flooding every
+// iteration hands the full syndrome to the bitFlippingAlg. With Layered an iteration is a pass over the layers
+// of layerSize check nodes, every layer with an unsatisfied check gets to flip the bits of its check nodes
+// (the bitFlippingAlg must be a LayeredBitFlippingAlg) and the syndrome is recomputed after each layer.
+// The ctx is checked before every flip.
+func BitFlippingContext(ctx context.Context, bitFlippingAlg BitFlippingAlg, H mat.SparseMat, codeword mat.SparseVector, maxIter int, schedule messagepassing.Schedule, layerSize int) (result mat.SparseVector, iterations int, stop messagepassing.StopReason) {
 	rows, _ := H.Dims()
 	result = mat.CSRVecCopy(codeword)
 	syndrome := mat.CSRVec(rows)
-	for i := 0; i < maxIter && !done; i++ {
-		syndrome.MatMul(H, result)
-		result, done = bitFlippingAlg.Flip(syndrome, result)
+
+	switch schedule {
+	case messagepassing.Flooding:
+		done := false
+		for ; iterations < maxIter; iterations++ {
+			if ctx.Err() != nil {
+				return result, iterations, messagepassing.Canceled
+			}
+			syndrome.MatMul(H, result)
+			result, done = bitFlippingAlg.Flip(syndrome, result)
+			if done {
+				break
+			}
+		}
+	case messagepassing.Layered:
+		layeredAlg, ok := bitFlippingAlg.(LayeredBitFlippingAlg)
+		if !ok {
+			panic(fmt.Sprintf("%T does not support the Layered schedule", bitFlippingAlg))
+		}
+
+		// the bits each layer is allowed to flip are the bits of its check nodes
+		_, cols := H.Dims()
+		layers := messagepassing.Layers(rows, layerSize)
+		layerBits := make([][]bool, len(layers))
+		for i, layer := range layers {
+			layerBits[i] = make([]bool, cols)
+			for _, c := range layer {
+				for _, n := range H.Row(c).NonzeroArray() {
+					layerBits[i][n] = true
+				}
+			}
+		}
+
+		for ; iterations < maxIter; iterations++ {
+			syndrome.MatMul(H, result)
+			if syndrome.IsZero() {
+				break
+			}
+
+			for i, layer := range layers {
+				if ctx.Err() != nil {
+					return result, iterations, messagepassing.Canceled
+				}
+				//a layer with every check satisfied has nothing to vote on
+				unsatisfied := false
+				for _, c := range layer {
+					unsatisfied = unsatisfied || syndrome.At(c) == 1
+				}
+				if unsatisfied {
+					result, _ = layeredAlg.FlipLayer(syndrome, result, layerBits[i])
+					syndrome.MatMul(H, result)
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unknown schedule %v", schedule))
 	}
-	return result
+
+	syndrome.MatMul(H, result)
+	switch {
+	case syndrome.IsZero():
+		stop = messagepassing.Converged
+	case iterations >= maxIter:
+		stop = messagepassing.MaxIterations
+	default:
+		stop = messagepassing.Stalled
+	}
+	return result, iterations, stop
 }
@@ -0,0 +1,120 @@
+package harddecision
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestBitFlippingSchedule_LayeredSingleErrors(t *testing.T) {
+	// every layer size must correct every single error like Flooding does
+	for _, m := range []int{3, 4} {
+		block, err := hamming.New(context.Background(), m, 0)
+		if err != nil {
+			t.Fatalf("expected no error but found: %v", err)
+		}
+		rows, cols := block.H.Dims()
+		message := mat.CSRVec(block.MessageLength())
+		for i := 0; i < message.Len(); i += 2 {
+			message.Set(i, 1)
+		}
+		expected := block.Encode(message)
+
+		for layerSize := 1; layerSize <= rows; layerSize++ {
+			for index := 0; index < cols; index++ {
+				t.Run(fmt.Sprintf("%v/%v/%v", m, layerSize, index), func(t *testing.T) {
+					codeword := mat.CSRVecCopy(expected)
+					codeword.Set(index, codeword.At(index)+1)
+
+					actual, _ := BitFlippingSchedule(&Gallager{H: block.H}, block.H, codeword, 20, messagepassing.Layered, layerSize)
+					if !actual.Equals(expected) {
+						t.Fatalf("expected %v but found %v", expected, actual)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestBitFlippingSchedule_Layered(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	tests := []struct {
+		message          mat.SparseVector
+		flipCodewordBits []int
+		layerSize        int
+	}{
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{}, 1},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, 3},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{2}, 3},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{4}, 3},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{5}, 3},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, 1},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{2}, 1},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{4}, 1},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{5}, 2},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{6}, 2},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			alg := &Gallager{
+				H: block.H,
+			}
+
+			codeword := block.Encode(test.message)
+			expected := mat.CSRVecCopy(codeword)
+			for _, index := range test.flipCodewordBits {
+				codeword.Set(index, codeword.At(index)+1)
+			}
+
+			actual, iterations := BitFlippingSchedule(alg, block.H, codeword, 20, messagepassing.Layered, test.layerSize)
+			if !actual.Equals(expected) {
+				t.Fatalf("expected %v but found %v", expected, actual)
+			}
+			if len(test.flipCodewordBits) == 0 && iterations != 0 {
+				t.Fatalf("expected 0 iterations but found %v", iterations)
+			}
+		})
+	}
+}
+
+func TestBitFlippingContext_StopReasons(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	codeword := block.Encode(mat.DOKVec(4, 1, 0, 1, 1))
+	codeword.Set(0, codeword.At(0)+1)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		maxIter    int
+		schedule   messagepassing.Schedule
+		expected   messagepassing.StopReason
+		iterations int
+	}{
+		{"Converged", context.Background(), 20, messagepassing.Flooding, messagepassing.Converged, 1},
+		{"MaxIterations", context.Background(), 0, messagepassing.Flooding, messagepassing.MaxIterations, 0},
+		{"Canceled", canceled, 20, messagepassing.Flooding, messagepassing.Canceled, 0},
+		{"CanceledLayered", canceled, 20, messagepassing.Layered, messagepassing.Canceled, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, iterations, stop := BitFlippingContext(test.ctx, &Gallager{H: block.H}, block.H, codeword, test.maxIter, test.schedule, 1)
+			if stop != test.expected || iterations != test.iterations {
+				t.Fatalf("expected %v after %v iterations but found %v after %v", test.expected, test.iterations, stop, iterations)
+			}
+		})
+	}
+}
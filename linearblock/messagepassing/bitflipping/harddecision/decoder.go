@@ -0,0 +1,36 @@
+package harddecision
+
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+)
+
+// Decoder adapts a BitFlippingAlg to a linearblock.Decoder. Since the BitFlippingAlg keeps
+// state between flips a Decoder must not be shared between threads.
+type Decoder struct {
+	Block     *linearblock.LinearBlock
+	Alg       BitFlippingAlg
+	MaxIter   int
+	Schedule  messagepassing.Schedule // Flooding (default) or Layered
+	LayerSize int                     // rows per layer when Layered, <=1 is row-serial
+}
+
+// Decode resets the Alg and runs BitFlippingContext on the hard decisions of the received word.
+// A ReliabilityBitFlippingAlg is also given the received LLRs, when there are any.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	d.Alg.Reset()
+	if alg, ok := d.Alg.(ReliabilityBitFlippingAlg); ok {
+		var channelLLR []float64
+		if received.LLR != nil {
+			channelLLR = make([]float64, received.LLR.Len())
+			for i := range channelLLR {
+				channelLLR[i] = received.LLR.AtVec(i)
+			}
+		}
+		alg.SetChannelLLR(channelLLR)
+	}
+	codeword, iterations, stop := BitFlippingContext(ctx, d.Alg, d.Block.H, received.HardDecision(), d.MaxIter, d.Schedule, d.LayerSize)
+	return d.Block.NewDecodeResult(codeword, iterations, stop)
+}
@@ -0,0 +1,91 @@
+package harddecision
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/ldpc/finitegeometry"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+func TestDecoder_Hamming(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	message := mat.DOKVec(4, 1, 0, 1, 1)
+	codeword := block.Encode(message)
+
+	decoder := &Decoder{Block: block, Alg: &Gallager{H: block.H}, MaxIter: 20}
+	for i := 0; i < block.CodewordLength(); i++ {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			bits := mat.CSRVecCopy(codeword)
+			bits.Set(i, bits.At(i)+1)
+
+			// the soft decisions only carry the signs to a hard decision decoder
+			llr := mat2.NewVecDense(bits.Len(), nil)
+			for j := 0; j < bits.Len(); j++ {
+				llr.SetVec(j, float64(1-2*bits.At(j))*0.3)
+			}
+
+			for _, received := range []linearblock.Received{{Bits: bits}, {LLR: llr}} {
+				actual := decoder.Decode(context.Background(), received)
+				if !actual.Converged || actual.SyndromeWeight != 0 {
+					t.Fatalf("expected to converge but found syndrome weight %v", actual.SyndromeWeight)
+				}
+				if !actual.Codeword.Equals(codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual.Codeword)
+				}
+				if !actual.Message.Equals(message) {
+					t.Fatalf("expected message %v but found %v", message, actual.Message)
+				}
+				if actual.Iterations < 1 {
+					t.Fatalf("expected at least one iteration but found %v", actual.Iterations)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_ChannelLLR(t *testing.T) {
+	block, err := finitegeometry.EuclideanGeometry(context.Background(), 2, 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	codeword := mat.CSRVec(block.CodewordLength())
+
+	random := rand.New(rand.NewSource(11))
+	sigma := 0.6
+	for _, alg := range []ReliabilityBitFlippingAlg{&WBF{H: block.H}, &MWBF{H: block.H, AlphaFactor: .2}, &IMWBF{H: block.H, AlphaFactor: .2}} {
+		t.Run(fmt.Sprintf("%T", alg), func(t *testing.T) {
+			decoder := &Decoder{Block: block, Alg: alg, MaxIter: 20}
+			softErrors, hardErrors := 0, 0
+			for trial := 0; trial < 200; trial++ {
+				// the all zero codeword sent with BPSK over AWGN
+				llr := mat2.NewVecDense(codeword.Len(), nil)
+				for n := 0; n < codeword.Len(); n++ {
+					llr.SetVec(n, 2*(1+sigma*random.NormFloat64())/(sigma*sigma))
+				}
+
+				soft := decoder.Decode(context.Background(), linearblock.Received{LLR: llr})
+				if !soft.Codeword.Equals(codeword) {
+					softErrors++
+				}
+
+				hard := decoder.Decode(context.Background(), linearblock.Received{Bits: linearblock.Received{LLR: llr}.HardDecision()})
+				if !hard.Codeword.Equals(codeword) {
+					hardErrors++
+				}
+			}
+			if softErrors >= hardErrors {
+				t.Fatalf("expected the reliabilities to lower the %v word errors but found %v", hardErrors, softErrors)
+			}
+		})
+	}
+}
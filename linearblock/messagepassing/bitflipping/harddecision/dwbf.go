@@ -2,6 +2,8 @@ package harddecision
 
 import (
 	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
 	mat "github.com/nathanhack/sparsemat"
 	mat2 "gonum.org/v1/gonum/mat"
 )
@@ -16,16 +18,18 @@ type DWBF_F struct {
 	z            mat.SparseVector //original codeword
 	r            *mat2.Dense
 	e_n          []float64
-	columnCache  [][]int
-	rowCache     [][]int
+	tanner       *messagepassing.TannerGraph
 }
 
-func argMaxFloat(values []float64) int {
-	result := 0
-	max := values[0]
-	for i := 1; i < len(values); i++ {
-		v := values[i]
-		if max < v {
+// argMaxFloat returns the index of the largest value among the allowed indices, a nil allowed allows every index
+func argMaxFloat(values []float64, allowed []bool) int {
+	result := -1
+	max := 0.0
+	for i, v := range values {
+		if allowed != nil && !allowed[i] {
+			continue
+		}
+		if result == -1 || max < v {
 			result = i
 			max = v
 		}
@@ -49,18 +53,8 @@ func (D *DWBF_F) init(codeword mat.SparseVector) {
 	}
 	D.e_n = make([]float64, cols)
 
-	if D.rowCache == nil {
-		D.rowCache = make([][]int, cols)
-		for i := 0; i < rows; i++ {
-			D.rowCache[i] = D.H.Row(i).NonzeroArray()
-		}
-	}
-
-	if D.columnCache == nil {
-		D.columnCache = make([][]int, cols)
-		for j := 0; j < cols; j++ {
-			D.columnCache[j] = D.H.Column(j).NonzeroArray()
-		}
+	if D.tanner == nil {
+		D.tanner = messagepassing.NewTannerGraph(D.H)
 	}
 }
 func (D *DWBF_F) Reset() {
@@ -68,6 +62,10 @@ func (D *DWBF_F) Reset() {
 	D.r = nil
 }
 func (D *DWBF_F) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return D.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (D *DWBF_F) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
 	//first we check if the syndromes was zero
 	if currentSyndromes.IsZero() {
 		return currentCodeword, true
@@ -85,7 +83,7 @@ func (D *DWBF_F) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.Spa
 
 	// with the updated E_n we now determine the bit set
 	// B = {n|n arg max_i E_i}
-	n := argMaxFloat(D.e_n)
+	n := argMaxFloat(D.e_n, layer)
 
 	// then let E_n = -E_n
 	for i, e := range D.e_n {
@@ -108,7 +106,7 @@ func (D *DWBF_F) nextE_n(syndromes mat.SparseVector, codeword mat.SparseVector)
 	syndsLen := len(synds)
 	for n := 0; n < D.z.Len(); n++ {
 		sum := 0.0
-		cacheRow := D.columnCache[n]
+		cacheRow := D.tanner.VarToChecks[n]
 		cacheRowLen := len(cacheRow)
 		for i, j := 0, 0; i < cacheRowLen && j < syndsLen; {
 			if cacheRow[i] == synds[j] {
@@ -133,7 +131,7 @@ func (D *DWBF_F) nextR() {
 		for n := 0; n < cols; n++ {
 			min := 0.0
 			minIndex := -1
-			for _, n1 := range D.rowCache[m] {
+			for _, n1 := range D.tanner.CheckToVars[m] {
 				if n == n1 {
 					continue
 				}
@@ -1,15 +1,19 @@
 package harddecision
 
 import (
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
 	mat "github.com/nathanhack/sparsemat"
 )
 
-func argMaxInt(values []int) int {
-	result := 0
-	max := values[0]
-	for i := 1; i < len(values); i++ {
-		v := values[i]
-		if max < v {
+// argMaxInt returns the index of the largest value among the allowed indices, a nil allowed allows every index
+func argMaxInt(values []int, allowed []bool) int {
+	result := -1
+	max := 0
+	for i, v := range values {
+		if allowed != nil && !allowed[i] {
+			continue
+		}
+		if result == -1 || max < v {
 			result = i
 			max = v
 		}
@@ -18,9 +22,9 @@ func argMaxInt(values []int) int {
 }
 
 type Gallager struct {
-	H        mat.SparseMat
-	e_n      []int
-	rowCache [][]int
+	H      mat.SparseMat
+	e_n    []int
+	tanner *messagepassing.TannerGraph
 }
 
 func (g *Gallager) Reset() {
@@ -28,6 +32,10 @@ func (g *Gallager) Reset() {
 }
 
 func (g *Gallager) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return g.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (g *Gallager) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
 	if g.H == nil {
 		panic("Gallager H matrix must be set before calling Algorithm")
 	}
@@ -44,7 +52,7 @@ func (g *Gallager) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.S
 	// calculate the flipping function E_n vector
 	g.nextE_n(currentSyndromes)
 
-	n := argMaxInt(g.e_n)
+	n := argMaxInt(g.e_n, layer)
 
 	// and we flip that bit
 	nextCodeword = mat.CSRVecCopy(currentCodeword)
@@ -60,7 +68,7 @@ func (g *Gallager) nextE_n(syndromes mat.SparseVector) {
 	synIndicesLen := len(synIndices)
 	for n := 0; n < len(g.e_n); n++ {
 		sum := 0
-		indices := g.rowCache[n]
+		indices := g.tanner.VarToChecks[n]
 		indicesLen := len(indices)
 		for i, j := 0, 0; i < indicesLen && j < synIndicesLen; {
 			if indices[i] == synIndices[j] {
@@ -84,8 +92,5 @@ func (g *Gallager) init(codewordLen int) {
 	}
 
 	g.e_n = make([]int, codewordLen)
-	g.rowCache = make([][]int, codewordLen)
-	for n := 0; n < len(g.e_n); n++ {
-		g.rowCache[n] = g.H.Column(n).NonzeroArray()
-	}
+	g.tanner = messagepassing.NewTannerGraph(g.H)
 }
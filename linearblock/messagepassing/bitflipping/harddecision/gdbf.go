@@ -0,0 +1,236 @@
+package harddecision
+
+import (
+	"fmt"
+	"math/rand"
+
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// GDBF is the single bit gradient descent bit flipping hard decision alg based on the paper
+// "Gradient Descent Bit Flipping Algorithms for Decoding LDPC Codes"
+// by Tadashi Wadayama, Keisuke Nakamura, Masayuki Yagita, Yuuki Funahashi, Shogo Usami and Ichi Takumi
+type GDBF struct {
+	H          mat.SparseMat
+	ChannelLLR []float64 //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	reliabilities
+}
+
+func (g *GDBF) Reset() {
+	g.reset()
+}
+
+func (g *GDBF) SetChannelLLR(channelLLR []float64) {
+	g.ChannelLLR = channelLLR
+}
+
+func (g *GDBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return g.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (g *GDBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if g.H == nil {
+		panic("GDBF H matrix must be set before calling Algorithm")
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	if g.y == nil {
+		g.init(g.H, g.ChannelLLR, currentCodeword)
+	}
+
+	g.inversion(currentSyndromes, currentCodeword, 1)
+
+	// flip the bit with the smallest inversion function
+	n := argMinFloat(g.e_n, layer)
+
+	nextCodeword = mat.CSRVecCopy(currentCodeword)
+	nextCodeword.Set(n, nextCodeword.At(n)+1)
+	return nextCodeword, false
+}
+
+// MultiGDBF is the multi-bit gradient descent bit flipping hard decision alg from the same paper as GDBF.
+// Every bit with an inversion function below the threshold is flipped while the objective
+// function keeps increasing, after which it switches to flipping a single bit (GDBF).
+type MultiGDBF struct {
+	Threshold  float64 //θ: θ < 0, bits with Δ_n < θ are flipped
+	H          mat.SparseMat
+	ChannelLLR []float64 //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	reliabilities
+	objective  float64
+	singleMode bool
+}
+
+func (g *MultiGDBF) Reset() {
+	g.reset()
+	g.singleMode = false
+}
+
+func (g *MultiGDBF) SetChannelLLR(channelLLR []float64) {
+	g.ChannelLLR = channelLLR
+}
+
+func (g *MultiGDBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return g.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (g *MultiGDBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if g.H == nil {
+		panic("MultiGDBF H matrix must be set before calling Algorithm")
+	}
+	if g.Threshold >= 0 {
+		panic(fmt.Sprintf("θ<0 is required but found %v ", g.Threshold))
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	first := g.y == nil
+	if first {
+		g.init(g.H, g.ChannelLLR, currentCodeword)
+	}
+
+	// f(x) = sum(x_n*y_n, n) + sum(1-2*s_m, m)
+	objective := g.inversion(currentSyndromes, currentCodeword, 1)
+	if !first && objective <= g.objective {
+		g.singleMode = true
+	}
+	g.objective = objective
+
+	nextCodeword = mat.CSRVecCopy(currentCodeword)
+	if g.singleMode {
+		n := argMinFloat(g.e_n, layer)
+		nextCodeword.Set(n, nextCodeword.At(n)+1)
+		return nextCodeword, false
+	}
+
+	flipped := false
+	for n, e := range g.e_n {
+		if layer != nil && !layer[n] {
+			continue
+		}
+		if e < g.Threshold {
+			nextCodeword.Set(n, nextCodeword.At(n)+1)
+			flipped = true
+		}
+	}
+	if !flipped {
+		// nothing is below the threshold so we continue with single bit flips
+		g.singleMode = true
+		n := argMinFloat(g.e_n, layer)
+		nextCodeword.Set(n, nextCodeword.At(n)+1)
+	}
+	return nextCodeword, false
+}
+
+// NoisyGDBF is the noisy gradient descent bit flipping hard decision alg based on the paper
+// "Noisy Gradient Descent Bit-Flip Decoding for LDPC Codes"
+// by Gopalakrishnan Sundararajan, Chris Winstead and Emmanuel Boutillon
+type NoisyGDBF struct {
+	Threshold   float64 //θ: bits with Δ_n < θ are flipped, frequently slightly below 0
+	WeightW     float64 //w: w > 0, weight of the syndrome term
+	SigmaNoise  float64 //σ: σ >= 0, standard deviation of the perturbation noise added to each Δ_n
+	H           mat.SparseMat
+	ChannelLLR  []float64  //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	RandomNoise *rand.Rand //source of the perturbation noise, nil uses math/rand
+	reliabilities
+}
+
+func (g *NoisyGDBF) Reset() {
+	g.reset()
+}
+
+func (g *NoisyGDBF) SetChannelLLR(channelLLR []float64) {
+	g.ChannelLLR = channelLLR
+}
+
+func (g *NoisyGDBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return g.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (g *NoisyGDBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if g.H == nil {
+		panic("NoisyGDBF H matrix must be set before calling Algorithm")
+	}
+	if g.WeightW <= 0 {
+		panic(fmt.Sprintf("w>0 is required but found %v ", g.WeightW))
+	}
+	if g.SigmaNoise < 0 {
+		panic(fmt.Sprintf("σ>=0 is required but found %v ", g.SigmaNoise))
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	if g.y == nil {
+		g.init(g.H, g.ChannelLLR, currentCodeword)
+	}
+
+	norm := rand.NormFloat64
+	if g.RandomNoise != nil {
+		norm = g.RandomNoise.NormFloat64
+	}
+
+	g.inversion(currentSyndromes, currentCodeword, g.WeightW)
+
+	nextCodeword = mat.CSRVecCopy(currentCodeword)
+	for n, e := range g.e_n {
+		if layer != nil && !layer[n] {
+			continue
+		}
+		if e+g.SigmaNoise*norm() < g.Threshold {
+			nextCodeword.Set(n, nextCodeword.At(n)+1)
+		}
+	}
+	return nextCodeword, false
+}
+
+// inversion calculates the inversion function for every bit
+// Δ_n = x_n*y_n + w*sum(1-2*s_m, m ∈ M(n))
+// where x_n = 1-2*c_n is the bipolar form of the current codeword,
+// and returns the objective function f(x) = sum(x_n*y_n, n) + sum(1-2*s_m, m).
+func (r *reliabilities) inversion(syndromes mat.SparseVector, codeword mat.SparseVector, weight float64) (objective float64) {
+	unsatisfied := unsatisfiedChecks(syndromes, r.tanner.Checks())
+	for _, u := range unsatisfied {
+		if u {
+			objective--
+		} else {
+			objective++
+		}
+	}
+
+	for n, checks := range r.tanner.VarToChecks {
+		sum := 0.0
+		for _, m := range checks {
+			if unsatisfied[m] {
+				sum--
+			} else {
+				sum++
+			}
+		}
+		correlation := float64(1-2*codeword.At(n)) * r.y[n]
+		objective += correlation
+		r.e_n[n] = correlation + weight*sum
+	}
+	return objective
+}
+
+// argMinFloat returns the index of the smallest value among the allowed indices, a nil allowed allows every index
+func argMinFloat(values []float64, allowed []bool) int {
+	result := -1
+	min := 0.0
+	for i, v := range values {
+		if allowed != nil && !allowed[i] {
+			continue
+		}
+		if result == -1 || v < min {
+			result = i
+			min = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package harddecision
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestGDBF_BitFlippingHammingCodes(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	algs := map[string]func(llr []float64) BitFlippingAlg{
+		"GDBF":      func(llr []float64) BitFlippingAlg { return &GDBF{H: block.H, ChannelLLR: llr} },
+		"MultiGDBF": func(llr []float64) BitFlippingAlg { return &MultiGDBF{Threshold: -.5, H: block.H, ChannelLLR: llr} },
+		"NoisyGDBF": func(llr []float64) BitFlippingAlg {
+			return &NoisyGDBF{Threshold: -.5, WeightW: 1, SigmaNoise: .1, H: block.H, ChannelLLR: llr, RandomNoise: rand.New(rand.NewSource(0))}
+		},
+	}
+	tests := []struct {
+		message          mat.SparseVector
+		flipCodewordBits []int
+		soft             bool
+		maxIter          int
+	}{
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{1}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{2}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{3}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{4}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{5}, true, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{6}, true, 20},
+		{mat.DOKVec(4, 0, 1, 1, 0), []int{4}, false, 20},
+	}
+	for name, newAlg := range algs {
+		for i, test := range tests {
+			t.Run(name+"/"+strconv.Itoa(i), func(t *testing.T) {
+				//create codeword
+				codeword := block.Encode(test.message)
+				expected := mat.CSRVecCopy(codeword)
+
+				for _, index := range test.flipCodewordBits {
+					codeword.Set(index, codeword.At(index)+1)
+				}
+
+				var llr []float64
+				if test.soft {
+					llr = channelLLR(codeword, 2, .25, test.flipCodewordBits)
+				}
+				actual := BitFlipping(newAlg(llr), block.H, codeword, test.maxIter)
+
+				if !actual.Equals(expected) {
+					t.Fatalf("expected %v but found %v", expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGDBF_BitFlipping(b *testing.B) {
+	h := mat.CSRMat(4, 6, 1, 1, 0, 1, 0, 0, 0, 1, 1, 0, 1, 0, 1, 0, 0, 0, 1, 1, 0, 0, 1, 1, 0, 1)
+	input := mat.CSRVec(6, 1, 0, 1, 0, 1, 1)
+	g := &GDBF{
+		H: h,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Reset()
+		BitFlipping(g, h, input, 1)
+	}
+}
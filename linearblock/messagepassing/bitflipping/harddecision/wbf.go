@@ -0,0 +1,231 @@
+package harddecision
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// WBF is a single bit flipping hard decision alg based on the paper
+// "Low-Density Parity-Check Codes Based on Finite Geometries: A Rediscovery and New Results"
+// by Yu Kou, Shu Lin and Marc P.C. Fossorier
+type WBF struct {
+	H          mat.SparseMat
+	ChannelLLR []float64 //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	reliabilities
+}
+
+func (w *WBF) Reset() {
+	w.reset()
+}
+
+func (w *WBF) SetChannelLLR(channelLLR []float64) {
+	w.ChannelLLR = channelLLR
+}
+
+func (w *WBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return w.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (w *WBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if w.H == nil {
+		panic("WBF H matrix must be set before calling Algorithm")
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	if w.y == nil {
+		w.init(w.H, w.ChannelLLR, currentCodeword)
+	}
+
+	// E_n = sum((2*s_m-1)*w_m, m ∈ M(n))
+	// where w_m = min(|y_n'|, n' ∈ N(m))
+	return w.flipArgMax(currentSyndromes, currentCodeword, 0, false, layer), false
+}
+
+// MWBF is a single bit flipping hard decision alg based on the paper
+// "A Modified Weighted Bit-Flipping Decoding of Low-Density Parity-Check Codes"
+// by Juntan Zhang and Marc P.C. Fossorier
+type MWBF struct {
+	AlphaFactor float64 //α: α >= 0, weight of the bit's own reliability
+	H           mat.SparseMat
+	ChannelLLR  []float64 //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	reliabilities
+}
+
+func (w *MWBF) Reset() {
+	w.reset()
+}
+
+func (w *MWBF) SetChannelLLR(channelLLR []float64) {
+	w.ChannelLLR = channelLLR
+}
+
+func (w *MWBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return w.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (w *MWBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if w.H == nil {
+		panic("MWBF H matrix must be set before calling Algorithm")
+	}
+	if w.AlphaFactor < 0 {
+		panic(fmt.Sprintf("α>=0 is required but found %v ", w.AlphaFactor))
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	if w.y == nil {
+		w.init(w.H, w.ChannelLLR, currentCodeword)
+	}
+
+	// E_n = sum((2*s_m-1)*w_m, m ∈ M(n)) - α*|y_n|
+	// where w_m = min(|y_n'|, n' ∈ N(m))
+	return w.flipArgMax(currentSyndromes, currentCodeword, w.AlphaFactor, false, layer), false
+}
+
+// IMWBF is a single bit flipping hard decision alg based on the paper
+// "An Improvement on the Modified Weighted Bit Flipping Decoding Algorithm for LDPC Codes"
+// by Ming Jiang, Chunming Zhao, Zhihua Shi and Yu Chen
+type IMWBF struct {
+	AlphaFactor float64 //α: α >= 0, weight of the bit's own reliability
+	H           mat.SparseMat
+	ChannelLLR  []float64 //LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), when nil every bit is equally reliable
+	reliabilities
+}
+
+func (w *IMWBF) Reset() {
+	w.reset()
+}
+
+func (w *IMWBF) SetChannelLLR(channelLLR []float64) {
+	w.ChannelLLR = channelLLR
+}
+
+func (w *IMWBF) Flip(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector) (nextCodeword mat.SparseVector, done bool) {
+	return w.FlipLayer(currentSyndromes, currentCodeword, nil)
+}
+
+func (w *IMWBF) FlipLayer(currentSyndromes mat.SparseVector, currentCodeword mat.SparseVector, layer []bool) (nextCodeword mat.SparseVector, done bool) {
+	if w.H == nil {
+		panic("IMWBF H matrix must be set before calling Algorithm")
+	}
+	if w.AlphaFactor < 0 {
+		panic(fmt.Sprintf("α>=0 is required but found %v ", w.AlphaFactor))
+	}
+	//first we check if the syndromes was zero
+	if currentSyndromes.IsZero() {
+		return currentCodeword, true
+	}
+
+	if w.y == nil {
+		w.init(w.H, w.ChannelLLR, currentCodeword)
+	}
+
+	// E_n = sum((2*s_m-1)*w_mn, m ∈ M(n)) - α*|y_n|
+	// where w_mn = min(|y_n'|, n' ∈ N(m), n' ≠ n)
+	return w.flipArgMax(currentSyndromes, currentCodeword, w.AlphaFactor, true, layer), false
+}
+
+// reliabilities is the channel information shared by the weighted bit flipping algs.
+// The channel values y_n are in bipolar form where a positive value favors a 0.
+type reliabilities struct {
+	y      []float64
+	min1   []float64 //smallest |y_n| of each check node
+	min2   []float64 //second smallest |y_n| of each check node
+	minIdx []int     //variable node with the smallest |y_n| of each check node
+	e_n    []float64
+	tanner *messagepassing.TannerGraph
+}
+
+func (r *reliabilities) reset() {
+	r.y = nil
+}
+
+// init captures the channel values for the codeword being corrected. Without channelLLR the
+// hard decisions of the received codeword are used, giving each bit a reliability of 1.
+func (r *reliabilities) init(H mat.SparseMat, channelLLR []float64, codeword mat.SparseVector) {
+	if r.tanner == nil {
+		r.tanner = messagepassing.NewTannerGraph(H)
+	}
+
+	cols := codeword.Len()
+	if channelLLR != nil && len(channelLLR) != cols {
+		panic(fmt.Sprintf("expected %v channel LLRs but found %v", cols, len(channelLLR)))
+	}
+
+	r.y = make([]float64, cols)
+	for n := range r.y {
+		if channelLLR != nil {
+			r.y[n] = channelLLR[n]
+		} else {
+			r.y[n] = float64(1 - 2*codeword.At(n))
+		}
+	}
+	r.e_n = make([]float64, cols)
+
+	checks := r.tanner.Checks()
+	r.min1 = make([]float64, checks)
+	r.min2 = make([]float64, checks)
+	r.minIdx = make([]int, checks)
+	for m, vars := range r.tanner.CheckToVars {
+		r.min1[m], r.min2[m], r.minIdx[m] = math.Inf(1), math.Inf(1), -1
+		for _, n := range vars {
+			v := math.Abs(r.y[n])
+			if v < r.min1[m] {
+				r.min2[m] = r.min1[m]
+				r.min1[m], r.minIdx[m] = v, n
+			} else if v < r.min2[m] {
+				r.min2[m] = v
+			}
+		}
+	}
+}
+
+// flipArgMax calculates the flipping function
+// E_n = sum((2*s_m-1)*w, m ∈ M(n)) - α*|y_n|
+// and flips the bit in the layer (every bit when nil) with the largest E_n. The check weight w is w_m or, when
+// excludeSelf is set, w_mn.
+func (r *reliabilities) flipArgMax(syndromes mat.SparseVector, codeword mat.SparseVector, alpha float64, excludeSelf bool, layer []bool) (nextCodeword mat.SparseVector) {
+	unsatisfied := unsatisfiedChecks(syndromes, r.tanner.Checks())
+
+	for n, checks := range r.tanner.VarToChecks {
+		sum := 0.0
+		for _, m := range checks {
+			w := r.min1[m]
+			if excludeSelf && r.minIdx[m] == n {
+				w = r.min2[m]
+				if math.IsInf(w, 1) {
+					//n is the only bit in the check
+					w = 0
+				}
+			}
+			if unsatisfied[m] {
+				sum += w
+			} else {
+				sum -= w
+			}
+		}
+		r.e_n[n] = sum - alpha*math.Abs(r.y[n])
+	}
+
+	n := argMaxFloat(r.e_n, layer)
+
+	nextCodeword = mat.CSRVecCopy(codeword)
+	nextCodeword.Set(n, nextCodeword.At(n)+1)
+	return nextCodeword
+}
+
+func unsatisfiedChecks(syndromes mat.SparseVector, checks int) []bool {
+	unsatisfied := make([]bool, checks)
+	for _, m := range syndromes.NonzeroArray() {
+		unsatisfied[m] = true
+	}
+	return unsatisfied
+}
@@ -0,0 +1,87 @@
+package harddecision
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// channelLLR creates the LLRs for the received codeword where every bit has a
+// reliability of strong except for the bits listed in weak
+func channelLLR(codeword mat.SparseVector, strong, weak float64, weakBits []int) []float64 {
+	llr := make([]float64, codeword.Len())
+	for i := range llr {
+		llr[i] = strong * float64(1-2*codeword.At(i))
+	}
+	for _, i := range weakBits {
+		llr[i] = weak * float64(1-2*codeword.At(i))
+	}
+	return llr
+}
+
+func TestWBF_BitFlippingHammingCodes(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	algs := map[string]func(llr []float64) BitFlippingAlg{
+		"WBF":   func(llr []float64) BitFlippingAlg { return &WBF{H: block.H, ChannelLLR: llr} },
+		"MWBF":  func(llr []float64) BitFlippingAlg { return &MWBF{AlphaFactor: .2, H: block.H, ChannelLLR: llr} },
+		"IMWBF": func(llr []float64) BitFlippingAlg { return &IMWBF{AlphaFactor: .2, H: block.H, ChannelLLR: llr} },
+	}
+	tests := []struct {
+		message          mat.SparseVector
+		flipCodewordBits []int
+		soft             bool
+		maxIter          int
+	}{
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{1}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{2}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{3}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{4}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{5}, false, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{6}, true, 20},
+		{mat.DOKVec(4, 0, 1, 1, 0), []int{3}, true, 20},
+	}
+	for name, newAlg := range algs {
+		for i, test := range tests {
+			t.Run(name+"/"+strconv.Itoa(i), func(t *testing.T) {
+				//create codeword
+				codeword := block.Encode(test.message)
+				expected := mat.CSRVecCopy(codeword)
+
+				for _, index := range test.flipCodewordBits {
+					codeword.Set(index, codeword.At(index)+1)
+				}
+
+				var llr []float64
+				if test.soft {
+					llr = channelLLR(codeword, 4, .5, test.flipCodewordBits)
+				}
+				actual := BitFlipping(newAlg(llr), block.H, codeword, test.maxIter)
+
+				if !actual.Equals(expected) {
+					t.Fatalf("expected %v but found %v", expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkIMWBF_BitFlipping(b *testing.B) {
+	h := mat.CSRMat(4, 6, 1, 1, 0, 1, 0, 0, 0, 1, 1, 0, 1, 0, 1, 0, 0, 0, 1, 1, 0, 0, 1, 1, 0, 1)
+	input := mat.CSRVec(6, 1, 0, 1, 0, 1, 1)
+	w := &IMWBF{
+		AlphaFactor: .2,
+		H:           h,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		BitFlipping(w, h, input, 1)
+	}
+}
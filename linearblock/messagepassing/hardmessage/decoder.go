@@ -0,0 +1,44 @@
+package hardmessage
+
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// IterativeDecoder is a hard message decoder that runs at most maxIter iterations and
+// stops when the ctx is canceled, see GallagerA and GallagerB.
+type IterativeDecoder interface {
+	DecodeContext(ctx context.Context, codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, stop messagepassing.StopReason)
+}
+
+// Decoder adapts an IterativeDecoder to a linearblock.Decoder.
+type Decoder struct {
+	Block   *linearblock.LinearBlock
+	Alg     IterativeDecoder
+	MaxIter int
+}
+
+// Decode runs the Alg on the hard decisions of the received word.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	codeword, iterations, stop := d.Alg.DecodeContext(ctx, received.HardDecision(), d.MaxIter)
+	return d.Block.NewDecodeResult(codeword, iterations, stop)
+}
+
+// MajorityLogicDecoder adapts MajorityLogic to a linearblock.Decoder.
+type MajorityLogicDecoder struct {
+	Block *linearblock.LinearBlock
+	Alg   *MajorityLogic
+}
+
+// Decode runs the one-step majority-logic decoding on the hard decisions
+// of the received word, it always takes a single iteration.
+func (d *MajorityLogicDecoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	if ctx.Err() != nil {
+		return d.Block.NewDecodeResult(received.HardDecision(), 0, messagepassing.Canceled)
+	}
+	codeword, _ := d.Alg.Decode(received.HardDecision())
+	return d.Block.NewDecodeResult(codeword, 1, messagepassing.Stalled)
+}
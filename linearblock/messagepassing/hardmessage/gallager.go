@@ -0,0 +1,162 @@
+package hardmessage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// GallagerA is Gallager's algorithm A from "Low-Density Parity-Check Codes" by Robert G. Gallager.
+// Binary messages are passed along the edges of the Tanner graph. A variable node sends the
+// received bit to a check node unless every other check node connected to it disagrees with it.
+// The check nodes send the XOR of the other incoming messages.
+type GallagerA struct {
+	H      mat.SparseMat
+	tanner messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of Gallager-A starting from the received codeword. It stops early
+// when the decision satisfies every parity check. The decision is returned along with the number of
+// iterations used and whether the syndrome converged to zero.
+func (g *GallagerA) Decode(codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, converged bool) {
+	result, iterations, stop := g.DecodeContext(context.Background(), codeword, maxIter)
+	return result, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (g *GallagerA) DecodeContext(ctx context.Context, codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	return gallager(ctx, g.tanner.Graph(g.H, "GallagerA"), codeword, maxIter, func(degree int) int {
+		return degree - 1
+	})
+}
+
+// GallagerB is Gallager's algorithm B from "Low-Density Parity-Check Codes" by Robert G. Gallager.
+// It is Gallager-A except a variable node sends the complement of the received bit as soon as at least
+// b of the other d_v-1 check nodes connected to it disagree with the received bit.
+type GallagerB struct {
+	H          mat.SparseMat
+	Thresholds map[int]int // vote threshold b for variable nodes of degree d_v: 1 <= b <= d_v-1, degrees not listed use a simple majority b = ⌊(d_v-1)/2⌋+1
+	tanner     messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of Gallager-B starting from the received codeword. It stops early
+// when the decision satisfies every parity check. The decision is returned along with the number of
+// iterations used and whether the syndrome converged to zero.
+func (g *GallagerB) Decode(codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, converged bool) {
+	result, iterations, stop := g.DecodeContext(context.Background(), codeword, maxIter)
+	return result, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (g *GallagerB) DecodeContext(ctx context.Context, codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	return gallager(ctx, g.tanner.Graph(g.H, "GallagerB"), codeword, maxIter, func(degree int) int {
+		b, has := g.Thresholds[degree]
+		if !has {
+			return (degree-1)/2 + 1
+		}
+		if b < 1 || degree-1 < b {
+			panic(fmt.Sprintf("1<=b<=%v is required for degree %v but found %v", degree-1, degree, b))
+		}
+		return b
+	})
+}
+
+// gallager is the message passing core shared by Gallager-A and Gallager-B. The threshold returns
+// the number of the other check nodes that must disagree with the received bit before a variable
+// node of the given degree sends its complement. The ctx is checked every iteration.
+func gallager(ctx context.Context, graph *messagepassing.TannerGraph, codeword mat.SparseVector, maxIter int, threshold func(degree int) int) (result mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	if codeword.Len() != graph.Vars() {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", graph.Vars(), codeword.Len()))
+	}
+
+	received := make([]int, graph.Vars())
+	for _, v := range codeword.NonzeroArray() {
+		received[v] = 1
+	}
+	if graph.SyndromeZero(codeword) {
+		return mat.CSRVecCopy(codeword), 0, messagepassing.Converged
+	}
+
+	thresholds := make([]int, graph.Vars())
+	for v, checks := range graph.VarToChecks {
+		if len(checks) > 1 {
+			thresholds[v] = threshold(len(checks))
+		}
+	}
+
+	// variable to check and check to variable messages, indexed by [check][position]
+	v2c := make([][]int, graph.Checks())
+	c2v := make([][]int, graph.Checks())
+	for c, vars := range graph.CheckToVars {
+		v2c[c] = make([]int, len(vars))
+		c2v[c] = make([]int, len(vars))
+		for i, v := range vars {
+			v2c[c][i] = received[v]
+		}
+	}
+
+	result = mat.CSRVecCopy(codeword)
+	decision := make([]int, graph.Vars())
+	for iterations = 1; iterations <= maxIter; iterations++ {
+		if ctx.Err() != nil {
+			return result, iterations - 1, messagepassing.Canceled
+		}
+
+		//check node update
+		for c := range graph.CheckToVars {
+			parity := 0
+			for _, m := range v2c[c] {
+				parity ^= m
+			}
+			for i, m := range v2c[c] {
+				c2v[c][i] = parity ^ m
+			}
+		}
+
+		//variable node update
+		for v, edges := range graph.VarToEdges {
+			disagree := 0
+			for _, e := range edges {
+				if c2v[e.Check][e.Position] != received[v] {
+					disagree++
+				}
+			}
+
+			// the decision is the majority of the received bit and every check node, ties keep the received bit
+			decision[v] = received[v]
+			if disagree > len(edges)+1-disagree {
+				decision[v] = 1 - received[v]
+			}
+
+			for _, e := range edges {
+				others := disagree
+				if c2v[e.Check][e.Position] != received[v] {
+					others--
+				}
+				v2c[e.Check][e.Position] = received[v]
+				if len(edges) > 1 && others >= thresholds[v] {
+					v2c[e.Check][e.Position] = 1 - received[v]
+				}
+			}
+		}
+
+		result = toVector(decision)
+		if graph.SyndromeZero(result) {
+			return result, iterations, messagepassing.Converged
+		}
+	}
+
+	return result, maxIter, messagepassing.MaxIterations
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
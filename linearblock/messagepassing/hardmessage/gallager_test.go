@@ -0,0 +1,137 @@
+package hardmessage
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// fanoH is the incidence matrix of the projective plane PG(2,2), every column has
+// weight 3 and no two columns share more than one row
+func fanoH() mat.SparseMat {
+	H := mat.CSRMat(7, 7)
+	for r := 0; r < 7; r++ {
+		for _, c := range []int{0, 1, 3} {
+			H.Set(r, (r+c)%7, 1)
+		}
+	}
+	return H
+}
+
+// codewords returns every codeword of H by brute force
+func codewords(H mat.SparseMat) []mat.SparseVector {
+	rows, cols := H.Dims()
+	result := make([]mat.SparseVector, 0)
+	syndrome := mat.CSRVec(rows)
+	for x := 0; x < 1<<cols; x++ {
+		codeword := mat.CSRVec(cols)
+		for i := 0; i < cols; i++ {
+			codeword.Set(i, (x>>i)&1)
+		}
+		syndrome.MatMul(H, codeword)
+		if syndrome.IsZero() {
+			result = append(result, codeword)
+		}
+	}
+	return result
+}
+
+type decoder interface {
+	Decode(codeword mat.SparseVector, maxIter int) (result mat.SparseVector, iterations int, converged bool)
+}
+
+func TestGallager_SingleErrors(t *testing.T) {
+	H := fanoH()
+	decoders := map[string]decoder{
+		"A":          &GallagerA{H: H},
+		"B":          &GallagerB{H: H},
+		"Thresholds": &GallagerB{H: H, Thresholds: map[int]int{3: 2}},
+	}
+
+	cws := codewords(H)
+	if len(cws) != 8 {
+		t.Fatalf("expected %v codewords but found %v", 8, len(cws))
+	}
+
+	for name, d := range decoders {
+		for i, expected := range cws {
+			for flip := 0; flip < 7; flip++ {
+				t.Run(name+"/"+strconv.Itoa(i)+"/"+strconv.Itoa(flip), func(t *testing.T) {
+					codeword := mat.CSRVecCopy(expected)
+					codeword.Set(flip, codeword.At(flip)+1)
+
+					actual, iterations, converged := d.Decode(codeword, 10)
+					if !converged {
+						t.Fatalf("expected to converge")
+					}
+					if iterations < 1 {
+						t.Fatalf("expected at least one iteration but found %v", iterations)
+					}
+					if !actual.Equals(expected) {
+						t.Fatalf("expected %v but found %v", expected, actual)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestGallager_NoErrors(t *testing.T) {
+	H := fanoH()
+	for _, expected := range codewords(H) {
+		actual, iterations, converged := (&GallagerB{H: H}).Decode(expected, 10)
+		if !converged || iterations != 0 {
+			t.Fatalf("expected to converge with 0 iterations but found %v %v", converged, iterations)
+		}
+		if !actual.Equals(expected) {
+			t.Fatalf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func TestGallagerB_InvalidThreshold(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic for b > d_v-1")
+		}
+	}()
+
+	codeword := mat.CSRVec(7)
+	codeword.Set(0, 1)
+	(&GallagerB{H: fanoH(), Thresholds: map[int]int{3: 3}}).Decode(codeword, 10)
+}
+
+func BenchmarkGallagerB_Decode(b *testing.B) {
+	H := fanoH()
+	d := &GallagerB{H: H}
+	codeword := mat.CSRVec(7)
+	codeword.Set(2, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Decode(codeword, 10)
+	}
+}
+
+func TestGallager_Canceled(t *testing.T) {
+	H := fanoH()
+	codeword := mat.CSRVecCopy(codewords(H)[3])
+	codeword.Set(2, codeword.At(2)+1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual, iterations, stop := (&GallagerA{H: H}).DecodeContext(ctx, codeword, 10)
+	if stop != messagepassing.Canceled || iterations != 0 {
+		t.Fatalf("expected %v after 0 iterations but found %v after %v", messagepassing.Canceled, stop, iterations)
+	}
+	if !actual.Equals(codeword) {
+		t.Fatalf("expected the received %v but found %v", codeword, actual)
+	}
+
+	_, iterations, stop = (&GallagerA{H: H}).DecodeContext(context.Background(), codeword, 0)
+	if stop != messagepassing.MaxIterations || iterations != 0 {
+		t.Fatalf("expected %v after 0 iterations but found %v after %v", messagepassing.MaxIterations, stop, iterations)
+	}
+}
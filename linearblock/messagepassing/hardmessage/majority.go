@@ -0,0 +1,63 @@
+package hardmessage
+
+import (
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MajorityLogic is one-step majority-logic decoding. It expects the J check nodes
+// connected to each bit to be orthogonal on that bit (see Orthogonal), which is the
+// case for finite geometry (EG/PG) codes, so it corrects any ⌊J/2⌋ or fewer errors.
+// Each bit is flipped when the majority of its check sums are unsatisfied.
+type MajorityLogic struct {
+	H      mat.SparseMat
+	tanner messagepassing.TannerCache
+}
+
+// Decode computes the check sums of the received codeword once and flips every bit that
+// has more unsatisfied check sums than satisfied ones. The decision is returned along with
+// whether it satisfies every parity check.
+func (m *MajorityLogic) Decode(codeword mat.SparseVector) (result mat.SparseVector, converged bool) {
+	graph := m.tanner.Graph(m.H, "MajorityLogic")
+
+	rows, _ := m.H.Dims()
+	syndrome := mat.CSRVec(rows)
+	syndrome.MatMul(m.H, codeword)
+	result = mat.CSRVecCopy(codeword)
+	if syndrome.IsZero() {
+		return result, true
+	}
+
+	for v, checks := range graph.VarToChecks {
+		unsatisfied := 0
+		for _, c := range checks {
+			unsatisfied += syndrome.At(c)
+		}
+		if unsatisfied > len(checks)-unsatisfied {
+			result.Set(v, result.At(v)+1)
+		}
+	}
+
+	return result, graph.SyndromeZero(result)
+}
+
+// Orthogonal returns true when the check nodes connected to each bit are orthogonal on it. Meaning
+// no other bit is in more than one of them, or equivalently no two columns of H share more than one row.
+func Orthogonal(H mat.SparseMat) bool {
+	_, cols := H.Dims()
+	for v := 0; v < cols; v++ {
+		seen := make(map[int]bool)
+		for _, c := range H.Column(v).NonzeroArray() {
+			for _, u := range H.Row(c).NonzeroArray() {
+				if u == v {
+					continue
+				}
+				if seen[u] {
+					return false
+				}
+				seen[u] = true
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package hardmessage
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestMajorityLogic_SingleErrors(t *testing.T) {
+	H := fanoH()
+	d := &MajorityLogic{H: H}
+
+	for i, expected := range codewords(H) {
+		for flip := 0; flip < 7; flip++ {
+			t.Run(strconv.Itoa(i)+"/"+strconv.Itoa(flip), func(t *testing.T) {
+				codeword := mat.CSRVecCopy(expected)
+				codeword.Set(flip, codeword.At(flip)+1)
+
+				actual, converged := d.Decode(codeword)
+				if !converged {
+					t.Fatalf("expected to converge")
+				}
+				if !actual.Equals(expected) {
+					t.Fatalf("expected %v but found %v", expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func TestOrthogonal(t *testing.T) {
+	if !Orthogonal(fanoH()) {
+		t.Fatalf("expected PG(2,2) to be orthogonal")
+	}
+
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if Orthogonal(block.H) {
+		t.Fatalf("expected the hamming code to not be orthogonal")
+	}
+}
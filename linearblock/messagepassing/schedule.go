@@ -0,0 +1,48 @@
+package messagepassing
+
+import "fmt"
+
+// Schedule is the order the check nodes are processed during a single iteration.
+type Schedule int
+
+const (
+	// Flooding updates every check node and then every variable node.
+	Flooding Schedule = iota
+	// Layered processes the check nodes a layer (group of rows) at a time, updating
+	// the variable nodes' posteriors immediately after each layer so the next layer
+	// sees the new information within the same iteration.
+	Layered
+)
+
+func (s Schedule) String() string {
+	switch s {
+	case Flooding:
+		return "Flooding"
+	case Layered:
+		return "Layered"
+	}
+	return fmt.Sprintf("Schedule(%d)", int(s))
+}
+
+// Layers splits the check nodes into consecutive layers of layerSize rows. For a quasi-cyclic H
+// layerSize is normally the circulant size so each layer is a row of circulants. A layerSize <= 1
+// places each check node in its own layer (row-serial).
+func Layers(checks, layerSize int) [][]int {
+	if layerSize < 1 {
+		layerSize = 1
+	}
+
+	layers := make([][]int, 0, (checks+layerSize-1)/layerSize)
+	for start := 0; start < checks; start += layerSize {
+		end := start + layerSize
+		if end > checks {
+			end = checks
+		}
+		layer := make([]int, 0, end-start)
+		for c := start; c < end; c++ {
+			layer = append(layer, c)
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
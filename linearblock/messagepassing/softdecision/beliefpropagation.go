@@ -0,0 +1,139 @@
+package softdecision
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// llrLimit bounds the magnitude of every message so the log domain
+// calculations never overflow into ±Inf
+const llrLimit = 30.0
+
+// checkNodeUpdate computes every outgoing check to variable message out[i]
+// from the incoming variable to check messages in, excluding in[i]
+type checkNodeUpdate func(in, out []float64)
+
+// beliefPropagation is the message passing core shared by all the soft decision decoders. Each
+// iteration updates the check nodes with checkUpdate and the variable nodes, either flooding or
+// layered according to schedule. It stops after maxIter iterations, as soon as the hard
+// decision has a zero syndrome or when the ctx is canceled (checked every iteration).
+func beliefPropagation(ctx context.Context, graph *messagepassing.TannerGraph, channelLLR mat2.Vector, maxIter int, schedule messagepassing.Schedule, layerSize int, checkUpdate checkNodeUpdate) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	if channelLLR.Len() != graph.Vars() {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", graph.Vars(), channelLLR.Len()))
+	}
+
+	// variable to check and check to variable messages, indexed by [check][position]
+	v2c := make([][]float64, graph.Checks())
+	c2v := make([][]float64, graph.Checks())
+	for c, vars := range graph.CheckToVars {
+		v2c[c] = make([]float64, len(vars))
+		c2v[c] = make([]float64, len(vars))
+		for i, v := range vars {
+			v2c[c][i] = clamp(channelLLR.AtVec(v))
+		}
+	}
+
+	posterior := make([]float64, channelLLR.Len())
+	for v := range posterior {
+		posterior[v] = channelLLR.AtVec(v)
+	}
+	codeword = hardDecision(posterior)
+	if graph.SyndromeZero(codeword) {
+		return codeword, 0, messagepassing.Converged
+	}
+
+	var layers [][]int
+	if schedule == messagepassing.Layered {
+		layers = messagepassing.Layers(graph.Checks(), layerSize)
+	}
+
+	for iterations = 1; iterations <= maxIter; iterations++ {
+		if ctx.Err() != nil {
+			return codeword, iterations - 1, messagepassing.Canceled
+		}
+
+		switch schedule {
+		case messagepassing.Flooding:
+			floodingIteration(graph, channelLLR, v2c, c2v, posterior, checkUpdate)
+		case messagepassing.Layered:
+			layeredIteration(graph, layers, v2c, c2v, posterior, checkUpdate)
+		default:
+			panic(fmt.Sprintf("unknown schedule %v", schedule))
+		}
+
+		codeword = hardDecision(posterior)
+		if graph.SyndromeZero(codeword) {
+			return codeword, iterations, messagepassing.Converged
+		}
+	}
+
+	return codeword, maxIter, messagepassing.MaxIterations
+}
+
+// floodingIteration updates every check node followed by every variable node
+func floodingIteration(graph *messagepassing.TannerGraph, channelLLR mat2.Vector, v2c, c2v [][]float64, posterior []float64, checkUpdate checkNodeUpdate) {
+	//check node update
+	for c := range graph.CheckToVars {
+		checkUpdate(v2c[c], c2v[c])
+	}
+
+	//variable node update
+	for v, edges := range graph.VarToEdges {
+		sum := channelLLR.AtVec(v)
+		for _, e := range edges {
+			sum += c2v[e.Check][e.Position]
+		}
+		posterior[v] = sum
+		for _, e := range edges {
+			v2c[e.Check][e.Position] = clamp(sum - c2v[e.Check][e.Position])
+		}
+	}
+}
+
+// layeredIteration processes one layer at a time, the variable to check messages for a layer
+// are made from the current posteriors (removing the layer's old contribution) and the
+// posteriors are updated with the new check to variable messages before the next layer
+func layeredIteration(graph *messagepassing.TannerGraph, layers [][]int, v2c, c2v [][]float64, posterior []float64, checkUpdate checkNodeUpdate) {
+	for _, layer := range layers {
+		for _, c := range layer {
+			for i, v := range graph.CheckToVars[c] {
+				v2c[c][i] = clamp(posterior[v] - c2v[c][i])
+			}
+		}
+
+		for _, c := range layer {
+			for i, v := range graph.CheckToVars[c] {
+				//remove the old contribution, the new one is added after the update
+				posterior[v] -= c2v[c][i]
+			}
+			checkUpdate(v2c[c], c2v[c])
+			for i, v := range graph.CheckToVars[c] {
+				posterior[v] += c2v[c][i]
+			}
+		}
+	}
+}
+
+func clamp(x float64) float64 {
+	if x > llrLimit {
+		return llrLimit
+	}
+	if x < -llrLimit {
+		return -llrLimit
+	}
+	return x
+}
+
+func hardDecision(llr []float64) mat.SparseVector {
+	result := mat.CSRVec(len(llr))
+	for i, l := range llr {
+		if l < 0 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
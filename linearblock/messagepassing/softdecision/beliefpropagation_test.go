@@ -0,0 +1,50 @@
+package softdecision
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestBeliefPropagation_Layered(t *testing.T) {
+	block, err := hamming.New(context.Background(), 4, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	graph := messagepassing.NewTannerGraph(block.H)
+
+	tests := []struct {
+		layerSize    int
+		weakenedBits []int
+	}{
+		{0, []int{6}},
+		{1, []int{10}},
+		{2, []int{12}},
+		{4, []int{13}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			expected := block.Encode(mat.DOKVec(11, 1, 0, 1, 1, 0, 0, 1, 0, 1, 1, 1))
+			llr := bitsToLLR(expected, 2)
+			for _, index := range test.weakenedBits {
+				llr.SetVec(index, -0.5*llr.AtVec(index))
+			}
+
+			flooded, floodIterations, _ := beliefPropagation(context.Background(), graph, llr, 20, messagepassing.Flooding, 0, sumProductCheck)
+			layered, layeredIterations, stop := beliefPropagation(context.Background(), graph, llr, 20, messagepassing.Layered, test.layerSize, sumProductCheck)
+			if stop != messagepassing.Converged {
+				t.Fatalf("expected to converge but did not after %v iterations", layeredIterations)
+			}
+			if !layered.Equals(expected) || !flooded.Equals(expected) {
+				t.Fatalf("expected %v but found layered %v and flooded %v", expected, layered, flooded)
+			}
+			if layeredIterations > floodIterations {
+				t.Fatalf("expected layered iterations (%v) <= flooding iterations (%v)", layeredIterations, floodIterations)
+			}
+		})
+	}
+}
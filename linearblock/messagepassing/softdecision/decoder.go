@@ -0,0 +1,32 @@
+package softdecision
+
+import (
+	"context"
+
+	"github.com/nathanhack/ecc/linearblock"
+)
+
+// Decoder adapts an IterativeDecoder to a linearblock.Decoder.
+type Decoder struct {
+	Block   *linearblock.LinearBlock
+	Alg     IterativeDecoder
+	MaxIter int
+}
+
+// Decode runs the Alg on the soft decisions of the received word, see linearblock.Received.SoftDecision.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	codeword, iterations, stop := d.Alg.DecodeContext(ctx, received.SoftDecision(), d.MaxIter)
+	return d.Block.NewDecodeResult(codeword, iterations, stop)
+}
+
+// OSDDecoder adapts the OSD to a linearblock.Decoder.
+type OSDDecoder struct {
+	Block *linearblock.LinearBlock
+	OSD   *OSD
+}
+
+// Decode runs the OSD on the soft decisions of the received word, it isn't iterative so Iterations is 0.
+func (d *OSDDecoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	codeword, stop := d.OSD.DecodeContext(ctx, received.SoftDecision())
+	return d.Block.NewDecodeResult(codeword, 0, stop)
+}
@@ -0,0 +1,78 @@
+package softdecision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestDecoder_Hamming(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	message := mat.DOKVec(4, 0, 1, 1, 0)
+	codeword := block.Encode(message)
+
+	// a weak wrong bit
+	llr := bitsToLLR(codeword, 4)
+	llr.SetVec(2, -0.5*llr.AtVec(2))
+
+	decoders := map[string]linearblock.Decoder{
+		"SumProduct": &Decoder{Block: block, Alg: &SumProduct{H: block.H}, MaxIter: 20},
+		"OSD":        &OSDDecoder{Block: block, OSD: &OSD{H: block.H, Order: 1, Threads: 1}},
+	}
+	for name, decoder := range decoders {
+		t.Run(name, func(t *testing.T) {
+			actual := decoder.Decode(context.Background(), linearblock.Received{LLR: llr})
+			if !actual.Converged || actual.SyndromeWeight != 0 {
+				t.Fatalf("expected to converge but found syndrome weight %v", actual.SyndromeWeight)
+			}
+			if !actual.Codeword.Equals(codeword) {
+				t.Fatalf("expected %v but found %v", codeword, actual.Codeword)
+			}
+			if !actual.Message.Equals(message) {
+				t.Fatalf("expected message %v but found %v", message, actual.Message)
+			}
+		})
+	}
+
+	// without LLRs every bit is equally reliable, the one error is still corrected
+	bits := mat.CSRVecCopy(codeword)
+	bits.Set(5, bits.At(5)+1)
+	actual := decoders["OSD"].Decode(context.Background(), linearblock.Received{Bits: bits})
+	if !actual.Codeword.Equals(codeword) {
+		t.Fatalf("expected %v but found %v", codeword, actual.Codeword)
+	}
+}
+
+func TestDecoder_Canceled(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	llr := bitsToLLR(block.Encode(mat.DOKVec(4, 1, 1, 0, 1)), 2)
+	llr.SetVec(0, -llr.AtVec(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	decoders := map[string]linearblock.Decoder{
+		"SumProduct": &Decoder{Block: block, Alg: &SumProduct{H: block.H}, MaxIter: 20},
+		"MinSum":     &Decoder{Block: block, Alg: &MinSum{H: block.H}, MaxIter: 20},
+		"WithOSD":    &Decoder{Block: block, Alg: &WithOSD{Decoder: &MinSum{H: block.H}, OSD: &OSD{H: block.H, Order: 2}}, MaxIter: 20},
+		"OSD":        &OSDDecoder{Block: block, OSD: &OSD{H: block.H, Order: 2}},
+	}
+	for name, decoder := range decoders {
+		t.Run(name, func(t *testing.T) {
+			actual := decoder.Decode(ctx, linearblock.Received{LLR: llr})
+			if actual.Stop != messagepassing.Canceled || actual.Iterations != 0 {
+				t.Fatalf("expected %v after 0 iterations but found %v after %v", messagepassing.Canceled, actual.Stop, actual.Iterations)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package softdecision
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// MinSum is the min-sum approximation of sum-product, the check node
+// magnitude is the smallest incoming magnitude. Since it is invariant
+// to scaling the channel LLRs do not need to know the noise variance.
+type MinSum struct {
+	H         mat.SparseMat
+	Schedule  messagepassing.Schedule // Flooding (default) or Layered
+	LayerSize int                     // rows per layer when Layered, <=1 is row-serial
+	tanner    messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of min-sum, see SumProduct.Decode.
+func (m *MinSum) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := m.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (m *MinSum) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	return beliefPropagation(ctx, m.tanner.Graph(m.H, "MinSum"), channelLLR, maxIter, m.Schedule, m.LayerSize, func(in, out []float64) {
+		minSumCheck(in, out, func(magnitude float64) float64 { return magnitude })
+	})
+}
+
+// NormalizedMinSum scales the min-sum check node magnitude by AlphaFactor
+// to compensate for min-sum overestimating the sum-product magnitude.
+type NormalizedMinSum struct {
+	AlphaFactor float64 //α: 0 < α <= 1, frequently 0.7 to 0.9
+	H           mat.SparseMat
+	Schedule    messagepassing.Schedule // Flooding (default) or Layered
+	LayerSize   int                     // rows per layer when Layered, <=1 is row-serial
+	tanner      messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of normalized min-sum, see SumProduct.Decode.
+func (n *NormalizedMinSum) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := n.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (n *NormalizedMinSum) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	if n.AlphaFactor <= 0 || 1 < n.AlphaFactor {
+		panic(fmt.Sprintf("0<α<=1 is required but found %v ", n.AlphaFactor))
+	}
+	return beliefPropagation(ctx, n.tanner.Graph(n.H, "NormalizedMinSum"), channelLLR, maxIter, n.Schedule, n.LayerSize, func(in, out []float64) {
+		minSumCheck(in, out, func(magnitude float64) float64 { return n.AlphaFactor * magnitude })
+	})
+}
+
+// OffsetMinSum subtracts BetaOffset from the min-sum check node magnitude (never going below zero)
+// to compensate for min-sum overestimating the sum-product magnitude.
+type OffsetMinSum struct {
+	BetaOffset float64 //β: β >= 0, frequently 0.15 to 0.5
+	H          mat.SparseMat
+	Schedule   messagepassing.Schedule // Flooding (default) or Layered
+	LayerSize  int                     // rows per layer when Layered, <=1 is row-serial
+	tanner     messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of offset min-sum, see SumProduct.Decode.
+func (o *OffsetMinSum) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := o.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (o *OffsetMinSum) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	if o.BetaOffset < 0 {
+		panic(fmt.Sprintf("β>=0 is required but found %v ", o.BetaOffset))
+	}
+	return beliefPropagation(ctx, o.tanner.Graph(o.H, "OffsetMinSum"), channelLLR, maxIter, o.Schedule, o.LayerSize, func(in, out []float64) {
+		minSumCheck(in, out, func(magnitude float64) float64 { return math.Max(magnitude-o.BetaOffset, 0) })
+	})
+}
+
+// minSumCheck is the check node update
+// L_{m->n} = Π sign(L_{n'->m}) * correction(min(|L_{n'->m}|)), n' ∈ N(m), n' ≠ n
+func minSumCheck(in, out []float64, correction func(magnitude float64) float64) {
+	sign := 1.0
+	min1, min2 := math.Inf(1), math.Inf(1)
+	minIndex := -1
+	for i, l := range in {
+		if l < 0 {
+			sign = -sign
+		}
+		a := math.Abs(l)
+		if a < min1 {
+			min2 = min1
+			min1 = a
+			minIndex = i
+		} else if a < min2 {
+			min2 = a
+		}
+	}
+
+	//the two smallest are all we need, only the smallest
+	// needs the second smallest since it's excluded
+	first := clamp(correction(min1))
+	second := clamp(correction(min2))
+	for i, l := range in {
+		s := sign
+		if l < 0 {
+			s = -s
+		}
+		if i == minIndex {
+			out[i] = s * second
+		} else {
+			out[i] = s * first
+		}
+	}
+}
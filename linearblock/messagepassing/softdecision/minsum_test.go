@@ -0,0 +1,71 @@
+package softdecision
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+type softDecoder interface {
+	Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool)
+}
+
+func TestMinSumFamily_HammingCodes(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	decoders := map[string]softDecoder{
+		"MinSum":           &MinSum{H: block.H},
+		"NormalizedMinSum": &NormalizedMinSum{H: block.H, AlphaFactor: .8},
+		"OffsetMinSum":     &OffsetMinSum{H: block.H, BetaOffset: .25},
+	}
+
+	tests := []struct {
+		message      mat.SparseVector
+		weakenedBits []int
+		maxIter      int
+	}{
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{}, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{5}, 20},
+		{mat.DOKVec(4, 0, 1, 1, 0), []int{6}, 20},
+	}
+	for name, alg := range decoders {
+		for i, test := range tests {
+			t.Run(name+strconv.Itoa(i), func(t *testing.T) {
+				expected := block.Encode(test.message)
+				llr := bitsToLLR(expected, 2)
+				for _, index := range test.weakenedBits {
+					llr.SetVec(index, -0.5*llr.AtVec(index))
+				}
+
+				actual, iterations, converged := alg.Decode(llr, test.maxIter)
+				if !converged {
+					t.Fatalf("expected to converge but did not after %v iterations", iterations)
+				}
+				if !actual.Equals(expected) {
+					t.Fatalf("expected %v but found %v", expected, actual)
+				}
+			})
+		}
+	}
+}
+
+func TestMinSumCheck(t *testing.T) {
+	in := []float64{-1, 2, 3, -4}
+	out := make([]float64, len(in))
+	expected := []float64{-2, 1, 1, -1}
+
+	minSumCheck(in, out, func(magnitude float64) float64 { return magnitude })
+	for i := range expected {
+		if out[i] != expected[i] {
+			t.Fatalf("expected %v but found %v", expected, out)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package softdecision
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nathanhack/ecc/linearblock/internal"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// IterativeDecoder is a soft decision decoder that runs at most maxIter iterations and
+// stops when the ctx is canceled, see SumProduct and MinSum.
+type IterativeDecoder interface {
+	DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason)
+}
+
+// OSD is the order-i ordered statistics decoder from the paper
+// "Soft-Decision Decoding of Linear Block Codes Based on Ordered Statistics"
+// by Marc P.C. Fossorier and Shu Lin.
+// The positions are sorted by reliability |LLR_n| and the hard decisions of the most reliable
+// basis (MRB) are re-encoded. Every test error pattern of up to Order flipped MRB bits is re-encoded
+// as well and the codeword closest to the channel LLRs is returned.
+type OSD struct {
+	H       mat.SparseMat
+	Order   int // i: the maximum number of MRB bits flipped in a test error pattern, 0 <= i
+	Threads int // threads used during elimination, <=0 will use runtime.NumCPU()
+}
+
+// Decode returns the most likely codeword found by OSD-i for the channel LLRs,
+// where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)).
+func (o *OSD) Decode(channelLLR mat2.Vector) (codeword mat.SparseVector) {
+	codeword, _ = o.DecodeContext(context.Background(), channelLLR)
+	return codeword
+}
+
+// DecodeContext is Decode that stops when the ctx is canceled, returning the best codeword found so far.
+// Every re-encoded candidate is a codeword so it stops either Converged or Canceled.
+func (o *OSD) DecodeContext(ctx context.Context, channelLLR mat2.Vector) (codeword mat.SparseVector, stop messagepassing.StopReason) {
+	if o.H == nil {
+		panic("OSD H matrix must be set before decoding")
+	}
+	if o.Order < 0 {
+		panic(fmt.Sprintf("0<=i is required but found %v ", o.Order))
+	}
+	_, cols := o.H.Dims()
+	if channelLLR.Len() != cols {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", cols, channelLLR.Len()))
+	}
+
+	hard := make([]int, cols)
+	reliability := make([]float64, cols)
+	for n := range hard {
+		l := channelLLR.AtVec(n)
+		if l < 0 {
+			hard[n] = 1
+		}
+		reliability[n] = math.Abs(l)
+	}
+
+	// eliminating H from the least reliable position up pivots on the least reliable
+	// basis (LRB), all the other columns are the most reliable basis (MRB). Each
+	// reduced row r then gives the LRB bit lrb[r] as the sum of its MRB bits.
+	order := make([]int, cols)
+	for n := range order {
+		order[n] = n
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return reliability[order[i]] < reliability[order[j]]
+	})
+	reduced, lrb := internal.GaussianJordanEliminationOrderedGF2(ctx, o.H, order, o.Threads)
+	if ctx.Err() != nil {
+		//the elimination was cut short, there is nothing to re-encode with
+		return toVector(hard), messagepassing.Canceled
+	}
+
+	isLRB := make([]bool, cols)
+	for _, n := range lrb {
+		isLRB[n] = true
+	}
+
+	// the MRB from the least to most reliable, so the likely test error patterns
+	// are tried first which lets the search prune the unlikely ones sooner
+	mrb := make([]int, 0, cols-len(lrb))
+	for _, n := range order {
+		if !isLRB[n] {
+			mrb = append(mrb, n)
+		}
+	}
+
+	// flips[j] are the reduced rows (LRB bits) that change when MRB bit mrb[j] changes
+	flips := make([][]int, len(mrb))
+	for j, n := range mrb {
+		flips[j] = reduced.Column(n).NonzeroArray()
+	}
+
+	// OSD-0: re-encode the MRB hard decisions
+	lrbBits := make([]int, len(lrb))
+	for r := range lrb {
+		for _, n := range reduced.Row(r).NonzeroArray() {
+			if !isLRB[n] {
+				lrbBits[r] ^= hard[n]
+			}
+		}
+	}
+
+	// the cost of a candidate is the discrepancy Σ|LLR_n| over the bits that disagree with the hard decisions
+	lrbCost := func() float64 {
+		cost := 0.0
+		for r, n := range lrb {
+			if lrbBits[r] != hard[n] {
+				cost += reliability[n]
+			}
+		}
+		return cost
+	}
+
+	best := lrbCost()
+	bestFlipped := []int{}
+	flipped := make([]int, 0, o.Order)
+
+	done := ctx.Done()
+	canceled := false
+
+	var search func(start int, mrbCost float64)
+	search = func(start int, mrbCost float64) {
+		if len(flipped) == o.Order {
+			return
+		}
+		for j := start; j < len(mrb); j++ {
+			select {
+			case <-done:
+				canceled = true
+				return
+			default:
+			}
+
+			// the MRB is sorted so every following bit costs at least as much
+			cost := mrbCost + reliability[mrb[j]]
+			if cost >= best {
+				return
+			}
+
+			for _, r := range flips[j] {
+				lrbBits[r] ^= 1
+			}
+			flipped = append(flipped, j)
+
+			if total := cost + lrbCost(); total < best {
+				best = total
+				bestFlipped = append(bestFlipped[:0], flipped...)
+			}
+			search(j+1, cost)
+
+			flipped = flipped[:len(flipped)-1]
+			for _, r := range flips[j] {
+				lrbBits[r] ^= 1
+			}
+		}
+	}
+	search(0, 0)
+
+	result := make([]int, cols)
+	copy(result, hard)
+	for _, j := range bestFlipped {
+		result[mrb[j]] ^= 1
+		for _, r := range flips[j] {
+			lrbBits[r] ^= 1
+		}
+	}
+	for r, n := range lrb {
+		result[n] = lrbBits[r]
+	}
+
+	if canceled {
+		return toVector(result), messagepassing.Canceled
+	}
+	return toVector(result), messagepassing.Converged
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
+
+// WithOSD runs the Decoder and only when it fails to converge to a zero syndrome
+// is the OSD used as a post-processor on the channel LLRs.
+type WithOSD struct {
+	Decoder IterativeDecoder
+	OSD     *OSD
+}
+
+// Decode returns the Decoder's result when it converged, otherwise the OSD's. The iterations
+// are the Decoder's and converged is true when the final codeword has a zero syndrome.
+func (w *WithOSD) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := w.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (w *WithOSD) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	codeword, iterations, stop = w.Decoder.DecodeContext(ctx, channelLLR, maxIter)
+	if stop == messagepassing.Converged || stop == messagepassing.Canceled {
+		return codeword, iterations, stop
+	}
+
+	codeword, stop = w.OSD.DecodeContext(ctx, channelLLR)
+	if stop == messagepassing.Canceled {
+		return codeword, iterations, stop
+	}
+
+	rows, _ := w.OSD.H.Dims()
+	syndrome := mat.CSRVec(rows)
+	syndrome.MatMul(w.OSD.H, codeword)
+	if !syndrome.IsZero() {
+		stop = messagepassing.Stalled
+	}
+	return codeword, iterations, stop
+}
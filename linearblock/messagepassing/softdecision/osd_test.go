@@ -0,0 +1,159 @@
+package softdecision
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// discrepancy is the OSD cost of the codeword, Σ|LLR_n| over the bits disagreeing with the hard decisions
+func discrepancy(codeword mat.SparseVector, channelLLR mat2.Vector) float64 {
+	cost := 0.0
+	for n := 0; n < codeword.Len(); n++ {
+		l := channelLLR.AtVec(n)
+		if (l < 0) != (codeword.At(n) == 1) {
+			cost += math.Abs(l)
+		}
+	}
+	return cost
+}
+
+func TestOSD_MaximumLikelihood(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	// with i == k every codeword is a candidate so OSD-k is maximum-likelihood
+	osd := &OSD{H: block.H, Order: block.MessageLength()}
+	random := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			llr := mat2.NewVecDense(block.CodewordLength(), nil)
+			for n := 0; n < llr.Len(); n++ {
+				llr.SetVec(n, random.NormFloat64())
+			}
+
+			best := math.Inf(1)
+			for m := 0; m < 1<<block.MessageLength(); m++ {
+				message := mat.CSRVec(block.MessageLength())
+				for j := 0; j < message.Len(); j++ {
+					message.Set(j, (m>>j)&1)
+				}
+				best = math.Min(best, discrepancy(block.Encode(message), llr))
+			}
+
+			actual := osd.Decode(llr)
+			if !block.Syndrome(actual).IsZero() {
+				t.Fatalf("expected a codeword but found %v", actual)
+			}
+			if cost := discrepancy(actual, llr); math.Abs(cost-best) > 1e-9 {
+				t.Fatalf("expected cost %v but found %v", best, cost)
+			}
+		})
+	}
+}
+
+func TestOSD_NoPivots(t *testing.T) {
+	// every word satisfies an all zero H so the hard decision is the best codeword
+	osd := &OSD{H: mat.CSRMat(2, 4), Order: 1}
+	llr := mat2.NewVecDense(4, []float64{1.5, -0.5, -2, 0.25})
+
+	expected := mat.DOKVec(4, 0, 1, 1, 0)
+	if actual := osd.Decode(llr); !actual.Equals(expected) {
+		t.Fatalf("expected %v but found %v", expected, actual)
+	}
+}
+
+func TestOSD_HammingCodes(t *testing.T) {
+	block, err := hamming.New(context.Background(), 4, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	tests := []struct {
+		message     mat.SparseVector
+		flippedBits []int
+		order       int
+	}{
+		{mat.DOKVec(11, 1, 0, 1, 1, 0, 0, 1, 0, 1, 1, 1), []int{}, 0},
+		{mat.DOKVec(11, 1, 0, 1, 1, 0, 0, 1, 0, 1, 1, 1), []int{3}, 1},
+		{mat.DOKVec(11, 1, 0, 1, 1, 0, 0, 1, 0, 1, 1, 1), []int{14}, 1},
+		{mat.DOKVec(11, 0, 1, 1, 0, 0, 1, 0, 0, 0, 1, 0), []int{2, 9}, 2},
+		{mat.DOKVec(11, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0), []int{0, 7}, 2},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			expected := block.Encode(test.message)
+
+			// the flipped bits are the least reliable so the closest codeword is the original
+			llr := bitsToLLR(expected, 2)
+			for _, n := range test.flippedBits {
+				llr.SetVec(n, -0.25*llr.AtVec(n))
+			}
+
+			actual := (&OSD{H: block.H, Order: test.order}).Decode(llr)
+			if !actual.Equals(expected) {
+				t.Fatalf("expected %v but found %v", expected, actual)
+			}
+		})
+	}
+}
+
+// stalled is an iterative decoder that never converges
+type stalled struct {
+	calls int
+}
+
+func (s *stalled) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	s.calls++
+	return mat.CSRVec(channelLLR.Len()), maxIter, messagepassing.MaxIterations
+}
+
+func TestWithOSD_Fallback(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	expected := block.Encode(mat.DOKVec(4, 1, 0, 1, 1))
+	llr := bitsToLLR(expected, 2)
+	llr.SetVec(5, -0.25*llr.AtVec(5))
+
+	// when the decoder converges the OSD isn't used
+	d := &WithOSD{Decoder: &SumProduct{H: block.H}, OSD: &OSD{H: block.H, Order: 1}}
+	actual, iterations, converged := d.Decode(llr, 20)
+	if !converged || iterations == 0 || !actual.Equals(expected) {
+		t.Fatalf("expected %v to converge but found %v %v %v", expected, actual, iterations, converged)
+	}
+
+	// when it doesn't the OSD fixes it
+	s := &stalled{}
+	d = &WithOSD{Decoder: s, OSD: &OSD{H: block.H, Order: 1}}
+	actual, iterations, converged = d.Decode(llr, 20)
+	if s.calls != 1 {
+		t.Fatalf("expected the decoder to be called once but found %v", s.calls)
+	}
+	if !converged || iterations != 20 || !actual.Equals(expected) {
+		t.Fatalf("expected %v to converge but found %v %v %v", expected, actual, iterations, converged)
+	}
+}
+
+func BenchmarkOSD_Decode(b *testing.B) {
+	block, err := hamming.New(context.Background(), 4, 0)
+	if err != nil {
+		b.Fatalf("expected no error but found: %v", err)
+	}
+	llr := bitsToLLR(block.Encode(mat.CSRVec(11)), 2)
+	llr.SetVec(3, -0.5)
+	osd := &OSD{H: block.H, Order: 2, Threads: 1}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		osd.Decode(llr)
+	}
+}
@@ -0,0 +1,67 @@
+package softdecision
+
+import (
+	"context"
+	"math"
+
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// SumProduct is the log domain sum-product (belief propagation) decoder.
+// The channel information is given as log likelihood ratios (LLR) where
+// LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), so a positive value favors a 0.
+type SumProduct struct {
+	H         mat.SparseMat
+	Schedule  messagepassing.Schedule // Flooding (default) or Layered
+	LayerSize int                     // rows per layer when Layered, <=1 is row-serial
+	tanner    messagepassing.TannerCache
+}
+
+// Decode runs at most maxIter iterations of sum-product over the Tanner graph of H starting
+// from the channel LLRs. It stops early when the hard decision satisfies every parity check.
+// The hard decision is returned along with the number of iterations used and whether the
+// syndrome converged to zero.
+func (s *SumProduct) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := s.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (s *SumProduct) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	return beliefPropagation(ctx, s.tanner.Graph(s.H, "SumProduct"), channelLLR, maxIter, s.Schedule, s.LayerSize, sumProductCheck)
+}
+
+// sumProductCheck is the check node update
+// L_{m->n} = Π sign(L_{n'->m}) * φ(Σ φ(|L_{n'->m}|)), n' ∈ N(m), n' ≠ n
+// where φ(x) = -ln(tanh(x/2))
+func sumProductCheck(in, out []float64) {
+	sign := 1.0
+	sum := 0.0
+	for _, l := range in {
+		if l < 0 {
+			sign = -sign
+		}
+		sum += phi(math.Abs(l))
+	}
+
+	for i, l := range in {
+		s := sign
+		if l < 0 {
+			s = -s
+		}
+		out[i] = clamp(s * phi(sum-phi(math.Abs(l))))
+	}
+}
+
+// phi is φ(x) = -ln(tanh(x/2)) = ln((e^x+1)/(e^x-1)), note φ(φ(x)) = x
+func phi(x float64) float64 {
+	if x < 1e-12 {
+		x = 1e-12
+	}
+	if x > llrLimit {
+		x = llrLimit
+	}
+	return math.Log1p(2 / math.Expm1(x))
+}
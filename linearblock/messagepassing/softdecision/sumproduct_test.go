@@ -0,0 +1,80 @@
+package softdecision
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// bitsToLLR makes a LLR vector from the codeword where every bit has the given reliability
+func bitsToLLR(codeword mat.SparseVector, reliability float64) *mat2.VecDense {
+	llr := mat2.NewVecDense(codeword.Len(), nil)
+	for i := 0; i < codeword.Len(); i++ {
+		if codeword.At(i) > 0 {
+			llr.SetVec(i, -reliability)
+		} else {
+			llr.SetVec(i, reliability)
+		}
+	}
+	return llr
+}
+
+func TestSumProduct_HammingCodes(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	tests := []struct {
+		message      mat.SparseVector
+		weakenedBits []int
+		maxIter      int
+	}{
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{}, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{0}, 20},
+		{mat.DOKVec(4, 1, 0, 1, 1), []int{3}, 20},
+		{mat.DOKVec(4, 0, 1, 1, 0), []int{6}, 20},
+		{mat.DOKVec(4, 0, 0, 0, 0), []int{2}, 20},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			alg := &SumProduct{
+				H: block.H,
+			}
+
+			expected := block.Encode(test.message)
+			llr := bitsToLLR(expected, 2)
+
+			//an unreliable error, it has the wrong sign
+			for _, index := range test.weakenedBits {
+				llr.SetVec(index, -0.5*llr.AtVec(index))
+			}
+
+			actual, iterations, converged := alg.Decode(llr, test.maxIter)
+			if !converged {
+				t.Fatalf("expected to converge but did not after %v iterations", iterations)
+			}
+			if !actual.Equals(expected) {
+				t.Fatalf("expected %v but found %v", expected, actual)
+			}
+			if len(test.weakenedBits) == 0 && iterations != 0 {
+				t.Fatalf("expected 0 iterations for a valid codeword but found %v", iterations)
+			}
+		})
+	}
+}
+
+func BenchmarkSumProduct(b *testing.B) {
+	h := mat.CSRMat(4, 6, 1, 1, 0, 1, 0, 0, 0, 1, 1, 0, 1, 0, 1, 0, 0, 0, 1, 1, 0, 0, 1, 1, 0, 1)
+	s := &SumProduct{
+		H: h,
+	}
+	input := mat2.NewVecDense(6, []float64{-1, 1.5, -0.2, 1, -2, -1})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Decode(input, 1)
+	}
+}
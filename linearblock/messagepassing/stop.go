@@ -0,0 +1,32 @@
+package messagepassing
+
+import "fmt"
+
+// StopReason is why a decoder stopped.
+type StopReason int
+
+const (
+	// Converged means the decision satisfies every parity check.
+	Converged StopReason = iota
+	// MaxIterations means the iteration cap was reached before converging.
+	MaxIterations
+	// Stalled means the decoder finished before the iteration cap without converging,
+	// ex: peeling stuck on a stopping set or a one-shot decoder that couldn't correct the errors.
+	Stalled
+	// Canceled means the context was canceled before the decoder finished.
+	Canceled
+)
+
+func (s StopReason) String() string {
+	switch s {
+	case Converged:
+		return "Converged"
+	case MaxIterations:
+		return "MaxIterations"
+	case Stalled:
+		return "Stalled"
+	case Canceled:
+		return "Canceled"
+	}
+	return fmt.Sprintf("StopReason(%d)", int(s))
+}
@@ -0,0 +1,95 @@
+package messagepassing
+
+import (
+	"fmt"
+	"sync"
+
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Edge is the location of a message on the Tanner graph. It is the check node
+// and the position of the variable node in that check node's CheckToVars list.
+type Edge struct {
+	Check    int
+	Position int
+}
+
+// TannerGraph is the row/column adjacency of a parity matrix H. Each check node (row)
+// lists the variable nodes (columns) connected to it and each variable node lists
+// the check nodes connected to it.
+type TannerGraph struct {
+	CheckToVars [][]int
+	VarToChecks [][]int
+	VarToEdges  [][]Edge
+}
+
+// NewTannerGraph creates the Tanner graph adjacency for the parity matrix H.
+func NewTannerGraph(H mat.SparseMat) *TannerGraph {
+	rows, cols := H.Dims()
+
+	t := &TannerGraph{
+		CheckToVars: make([][]int, rows),
+		VarToChecks: make([][]int, cols),
+		VarToEdges:  make([][]Edge, cols),
+	}
+	for v := 0; v < cols; v++ {
+		t.VarToChecks[v] = make([]int, 0)
+		t.VarToEdges[v] = make([]Edge, 0)
+	}
+
+	for c := range t.CheckToVars {
+		t.CheckToVars[c] = H.Row(c).NonzeroArray()
+		for i, v := range t.CheckToVars[c] {
+			t.VarToChecks[v] = append(t.VarToChecks[v], c)
+			t.VarToEdges[v] = append(t.VarToEdges[v], Edge{Check: c, Position: i})
+		}
+	}
+	return t
+}
+
+// TannerCache lazily builds the Tanner graph once so a decoder can be shared between threads.
+type TannerCache struct {
+	once  sync.Once
+	graph *TannerGraph
+}
+
+// Graph returns the Tanner graph of H, built on the first call. The name is the decoder's
+// name used in the panic when H isn't set.
+func (t *TannerCache) Graph(H mat.SparseMat, name string) *TannerGraph {
+	// checked before the once so a recovered panic doesn't leave a nil graph behind
+	if H == nil {
+		panic(fmt.Sprintf("%v H matrix must be set before decoding", name))
+	}
+	t.once.Do(func() {
+		t.graph = NewTannerGraph(H)
+	})
+	return t.graph
+}
+
+// Checks returns the number of check nodes.
+func (t *TannerGraph) Checks() int {
+	return len(t.CheckToVars)
+}
+
+// Vars returns the number of variable nodes.
+func (t *TannerGraph) Vars() int {
+	return len(t.VarToChecks)
+}
+
+// SyndromeZero returns true if the codeword satisfies every check node.
+func (t *TannerGraph) SyndromeZero(codeword mat.SparseVector) bool {
+	return t.SyndromeWeight(codeword) == 0
+}
+
+// SyndromeWeight returns the number of unsatisfied check nodes.
+func (t *TannerGraph) SyndromeWeight(codeword mat.SparseVector) int {
+	weight := 0
+	for _, vars := range t.CheckToVars {
+		parity := 0
+		for _, v := range vars {
+			parity += codeword.At(v)
+		}
+		weight += parity % 2
+	}
+	return weight
+}
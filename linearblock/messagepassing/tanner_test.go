@@ -0,0 +1,29 @@
+package messagepassing
+
+import (
+	"testing"
+
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func TestTannerCache_Graph(t *testing.T) {
+	cache := TannerCache{}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected a panic for a nil H")
+			}
+		}()
+		cache.Graph(nil, "Test")
+	}()
+
+	H := mat.DOKMat(2, 3, 1, 1, 0, 0, 1, 1)
+	graph := cache.Graph(H, "Test")
+	if graph == nil || graph.Checks() != 2 || graph.Vars() != 3 {
+		t.Fatalf("expected the 2x3 Tanner graph of H after the panic but found %v", graph)
+	}
+	if cache.Graph(H, "Test") != graph {
+		t.Fatalf("expected the graph to be built once")
+	}
+}
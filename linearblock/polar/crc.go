@@ -0,0 +1,49 @@
+package polar
+
+import (
+	"sort"
+)
+
+// crcPolynomials are the CRC generator polynomials by length without the x^length term, bit i is the coefficient of x^i
+var crcPolynomials = map[int]int{
+	6:  0x21,     // 5G NR CRC6: x^6+x^5+1
+	8:  0x07,     // CRC-8: x^8+x^2+x+1
+	11: 0x621,    // 5G NR CRC11: x^11+x^10+x^9+x^5+1
+	16: 0x1021,   // CRC-16-CCITT: x^16+x^12+x^5+1
+	24: 0xB2B117, // 5G NR CRC24C
+}
+
+// CRCLengths returns the supported CRC lengths.
+func CRCLengths() []int {
+	lengths := make([]int, 0, len(crcPolynomials))
+	for l := range crcPolynomials {
+		lengths = append(lengths, l)
+	}
+	sort.Ints(lengths)
+	return lengths
+}
+
+// crc returns the length CRC bits of the bits, the highest degree first. The register starts at
+// zero with nothing XORed into the result so the CRC is linear in the bits.
+func crc(bits []int, length int) []int {
+	if length == 0 {
+		return []int{}
+	}
+	polynomial := crcPolynomials[length]
+	mask := 1<<length - 1
+
+	register := 0
+	for _, b := range bits {
+		feedback := (register>>(length-1))&1 ^ b
+		register = (register << 1) & mask
+		if feedback == 1 {
+			register ^= polynomial
+		}
+	}
+
+	result := make([]int, length)
+	for i := range result {
+		result[i] = (register >> (length - 1 - i)) & 1
+	}
+	return result
+}
@@ -0,0 +1,267 @@
+package polar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// Decoder is a polar code decoder for the channel LLRs, where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)), see SC and SCL.
+type Decoder interface {
+	Decode(channelLLR mat2.Vector) (codeword mat.SparseVector)
+}
+
+// SC is the successive cancellation decoder from "Channel Polarization: A Method for Constructing
+// Capacity-Achieving Codes for Symmetric Binary-Input Memoryless Channels" by Erdal Arıkan.
+// The bits of u are decided in order, each from the LLR of its bit channel given the earlier decisions,
+// using the min-sum approximation f(a,b) = sign(a)sign(b)min(|a|,|b|) for the worse channels.
+type SC struct {
+	Code *Polar
+}
+
+// Decode returns the codeword of the decisions for the channel LLRs, where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)).
+func (s *SC) Decode(channelLLR mat2.Vector) (codeword mat.SparseVector) {
+	llr := channelLLRs(s.Code, channelLLR)
+	return toVector(sc(llr, s.Code.frozen))
+}
+
+// sc decodes the bits of u under the LLRs and returns their re-encoding x
+func sc(llr []float64, frozen []bool) []int {
+	if len(llr) == 1 {
+		if !frozen[0] && llr[0] < 0 {
+			return []int{1}
+		}
+		return []int{0}
+	}
+
+	// x = [(a⊕b)F, bF] for u = [a, b], so the first half sees x_i⊕x_{i+h} and the second
+	// half sees x_{i+h} and x_i corrected by the first half's re-encoding
+	h := len(llr) / 2
+	upper := make([]float64, h)
+	for i := range upper {
+		upper[i] = f(llr[i], llr[i+h])
+	}
+	a := sc(upper, frozen[:h])
+
+	lower := make([]float64, h)
+	for i := range lower {
+		lower[i] = g(llr[i], llr[i+h], a[i])
+	}
+	b := sc(lower, frozen[h:])
+
+	x := make([]int, 2*h)
+	for i := 0; i < h; i++ {
+		x[i] = a[i] ^ b[i]
+		x[i+h] = b[i]
+	}
+	return x
+}
+
+func f(a, b float64) float64 {
+	result := math.Min(math.Abs(a), math.Abs(b))
+	if (a < 0) != (b < 0) {
+		return -result
+	}
+	return result
+}
+
+func g(a, b float64, decision int) float64 {
+	if decision == 1 {
+		return b - a
+	}
+	return b + a
+}
+
+// SCL is the successive cancellation list decoder from "List Decoding of Polar Codes" by Ido Tal and
+// Alexander Vardy. It's SC that keeps both decisions of every unfrozen bit, pruning to the ListSize paths
+// with the smallest path metrics, the sum of |LLR| over the decisions that disagree with their LLRs.
+// When the code has a CRC the best path that passes it is chosen (CA-SCL), otherwise the best path.
+type SCL struct {
+	Code     *Polar
+	ListSize int // L: the number of paths kept, 1 <= L
+}
+
+// Decode returns the codeword of the chosen path for the channel LLRs, where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)).
+func (s *SCL) Decode(channelLLR mat2.Vector) (codeword mat.SparseVector) {
+	if s.ListSize < 1 {
+		panic(fmt.Sprintf("1<=L is required but found %v", s.ListSize))
+	}
+	llr := channelLLRs(s.Code, channelLLR)
+
+	l := &list{
+		frozen: s.Code.frozen,
+		size:   s.ListSize,
+		u:      []*decision{nil},
+		metric: []float64{0},
+	}
+	x, _ := l.node([][]float64{llr}, 0)
+
+	paths := make([]int, len(x))
+	for i := range paths {
+		paths[i] = i
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return l.metric[paths[i]] < l.metric[paths[j]]
+	})
+
+	for _, path := range paths {
+		if s.Code.crcPasses(l.decisions(path, len(llr))) {
+			return toVector(x[path])
+		}
+	}
+	return toVector(x[paths[0]])
+}
+
+// list is the state of the SCL paths, path i has decided u[i] so far with metric[i]
+type list struct {
+	frozen []bool
+	size   int
+	u      []*decision
+	metric []float64
+}
+
+// decision is an unfrozen bit of u, the paths share the decisions they have in common
+// so a path is extended without copying the bits decided before it
+type decision struct {
+	offset   int
+	bit      int
+	previous *decision
+}
+
+// decisions returns the n bits of u decided by path, the frozen bits are 0
+func (l *list) decisions(path, n int) []int {
+	u := make([]int, n)
+	for d := l.u[path]; d != nil; d = d.previous {
+		u[d.offset] = d.bit
+	}
+	return u
+}
+
+// node decodes the subtree of the bits of u starting at offset, llr[i] are the LLRs of path i.
+// The paths change as bits are decided so it returns the re-encoding of each new path and the
+// path each came from.
+func (l *list) node(llr [][]float64, offset int) (x [][]int, origin []int) {
+	n := len(llr[0])
+	if n == 1 {
+		return l.leaf(llr, offset)
+	}
+
+	h := n / 2
+	upper := make([][]float64, len(llr))
+	for p, values := range llr {
+		upper[p] = make([]float64, h)
+		for i := range upper[p] {
+			upper[p][i] = f(values[i], values[i+h])
+		}
+	}
+	a, upperOrigin := l.node(upper, offset)
+
+	lower := make([][]float64, len(a))
+	for p := range a {
+		values := llr[upperOrigin[p]]
+		lower[p] = make([]float64, h)
+		for i := range lower[p] {
+			lower[p][i] = g(values[i], values[i+h], a[p][i])
+		}
+	}
+	b, lowerOrigin := l.node(lower, offset+h)
+
+	x = make([][]int, len(b))
+	origin = make([]int, len(b))
+	for p := range b {
+		q := lowerOrigin[p]
+		x[p] = make([]int, n)
+		for i := 0; i < h; i++ {
+			x[p][i] = a[q][i] ^ b[p][i]
+			x[p][i+h] = b[p][i]
+		}
+		origin[p] = upperOrigin[q]
+	}
+	return x, origin
+}
+
+func (l *list) leaf(llr [][]float64, offset int) (x [][]int, origin []int) {
+	if l.frozen[offset] {
+		x = make([][]int, len(llr))
+		origin = make([]int, len(llr))
+		for p, values := range llr {
+			if values[0] < 0 {
+				l.metric[p] += -values[0]
+			}
+			x[p] = []int{0}
+			origin[p] = p
+		}
+		return x, origin
+	}
+
+	type candidate struct {
+		path   int
+		bit    int
+		metric float64
+	}
+	candidates := make([]candidate, 0, 2*len(llr))
+	for p, values := range llr {
+		for bit := 0; bit < 2; bit++ {
+			metric := l.metric[p]
+			if (values[0] < 0) != (bit == 1) {
+				metric += math.Abs(values[0])
+			}
+			candidates = append(candidates, candidate{p, bit, metric})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].metric < candidates[j].metric
+	})
+	if len(candidates) > l.size {
+		candidates = candidates[:l.size]
+	}
+
+	u := make([]*decision, len(candidates))
+	metric := make([]float64, len(candidates))
+	x = make([][]int, len(candidates))
+	origin = make([]int, len(candidates))
+	for i, c := range candidates {
+		u[i] = &decision{offset, c.bit, l.u[c.path]}
+		metric[i] = c.metric
+		x[i] = []int{c.bit}
+		origin[i] = c.path
+	}
+	l.u = u
+	l.metric = metric
+	return x, origin
+}
+
+// crcPasses returns true when the CRC bits of u match the CRC of its message bits
+func (p *Polar) crcPasses(u []int) bool {
+	if p.CRC == 0 {
+		return true
+	}
+	bits := make([]int, p.K+p.CRC)
+	for i, pos := range p.info {
+		bits[i] = u[pos]
+	}
+	for i, b := range crc(bits[:p.K], p.CRC) {
+		if bits[p.K+i] != b {
+			return false
+		}
+	}
+	return true
+}
+
+func channelLLRs(code *Polar, channelLLR mat2.Vector) []float64 {
+	if code == nil {
+		panic("polar code must be set before decoding")
+	}
+	code.init()
+	if channelLLR.Len() != code.N {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", code.N, channelLLR.Len()))
+	}
+	llr := make([]float64, code.N)
+	for i := range llr {
+		llr[i] = channelLLR.AtVec(i)
+	}
+	return llr
+}
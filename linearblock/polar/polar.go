@@ -0,0 +1,305 @@
+package polar
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Construction is how the frozen set of a polar code is chosen.
+type Construction string
+
+const (
+	// Bhattacharyya tracks the Bhattacharyya parameter Z of each bit channel, z->2z-z^2 for the
+	// worse channel and z->z^2 for the better one, starting from the BPSK channel's Z = exp(-E_b/N_0).
+	Bhattacharyya Construction = "bhattacharyya"
+	// GaussianApproximation tracks the mean LLR of each bit channel assuming the LLRs are Gaussian,
+	// m->φ^-1(1-(1-φ(m))^2) for the worse channel and m->2m for the better one, starting from 4E_b/N_0.
+	GaussianApproximation Construction = "ga"
+)
+
+// Polar is a polar code from "Channel Polarization: A Method for Constructing Capacity-Achieving Codes for
+// Symmetric Binary-Input Memoryless Channels" by Erdal Arıkan. The codeword is x = uF^⊗n with F = [[1,0],[1,1]]
+// and no bit reversal, where u has its frozen bits set to 0 and the rest, the info set, carry the message followed
+// by its CRC (see CRCLengths). The frozen set holds the least reliable bit channels for the design E_b/N_0.
+type Polar struct {
+	N            int          // the codeword length, a power of 2
+	K            int          // the message length
+	CRC          int          // the number of CRC bits appended to the message, 0 for none
+	Frozen       []int        // the frozen bits of u in increasing order
+	Construction Construction // how the frozen set was chosen
+	DesignEbN0   float64      // the E_b/N_0 (linear, not dB) the frozen set was chosen for
+
+	once   sync.Once
+	frozen []bool // frozen[i] is true when bit i of u is frozen
+	info   []int  // the info set in increasing order
+}
+
+// New creates the (n,k) polar code with crc CRC bits, choosing the n-k-crc frozen bits with the construction
+// for the BPSK channel with the design E_b/N_0 (linear, not dB) as used by benchmarking.RandomNoiseBPSK.
+func New(n, k, crc int, construction Construction, designEbN0 float64) (*Polar, error) {
+	if n < 2 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("polar codes require a codeword length that is a power of 2 but found %v", n)
+	}
+	if _, has := crcPolynomials[crc]; !has && crc != 0 {
+		return nil, fmt.Errorf("CRC length must be 0 or one of %v but found %v", CRCLengths(), crc)
+	}
+	if k < 1 || n < k+crc {
+		return nil, fmt.Errorf("polar codes require 1<=message length<=%v but found %v", n-crc, k)
+	}
+	if designEbN0 <= 0 {
+		return nil, fmt.Errorf("design E_b/N_0 must be >0 but found %v", designEbN0)
+	}
+
+	var reliability []float64
+	switch construction {
+	case Bhattacharyya:
+		reliability = bhattacharyya(n, designEbN0)
+	case GaussianApproximation:
+		reliability = gaussianApproximation(n, designEbN0)
+	default:
+		return nil, fmt.Errorf("construction must be %v or %v but found %v", Bhattacharyya, GaussianApproximation, construction)
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return reliability[order[i]] < reliability[order[j]]
+	})
+	frozen := append([]int{}, order[:n-k-crc]...)
+	sort.Ints(frozen)
+
+	return &Polar{
+		N:            n,
+		K:            k,
+		CRC:          crc,
+		Frozen:       frozen,
+		Construction: construction,
+		DesignEbN0:   designEbN0,
+	}, nil
+}
+
+// bhattacharyya returns -ln(Z) of each bit channel, larger is more reliable. It's done in the log domain so
+// the very reliable channels don't underflow to Z=0. Without bit reversal the first polarization of the
+// channel decides the most significant bit of the index, see SC.
+func bhattacharyya(n int, ebN0 float64) []float64 {
+	z := make([]float64, n)
+	z[0] = -ebN0
+	for size := n / 2; size >= 1; size /= 2 {
+		for j := 0; j < n; j += 2 * size {
+			t := z[j]
+			z[j] = t + math.Log(2-math.Exp(t))
+			z[j+size] = 2 * t
+		}
+	}
+	for i := range z {
+		z[i] = -z[i]
+	}
+	return z
+}
+
+// gaussianApproximation returns the mean LLR of each bit channel, larger is more reliable.
+func gaussianApproximation(n int, ebN0 float64) []float64 {
+	m := make([]float64, n)
+	m[0] = 4 * ebN0
+	for size := n / 2; size >= 1; size /= 2 {
+		for j := 0; j < n; j += 2 * size {
+			t := m[j]
+			m[j] = gaWorse(t)
+			m[j+size] = 2 * t
+		}
+	}
+	return m
+}
+
+// gaWorse is φ^-1(1-(1-φ(m))^2), for large means φ(m)≈exp(-m/4) so it's m-4ln(2)
+func gaWorse(m float64) float64 {
+	p := phi(m)
+	if p < 1e-300 {
+		return m - 4*math.Ln2
+	}
+	return phiInverse(p * (2 - p))
+}
+
+// phi is the approximation of the function φ used by the Gaussian approximation of density evolution from
+// "On the Design of Low-Density Parity-Check Codes within 0.0045 dB of the Shannon Limit" by Chung et al.
+func phi(x float64) float64 {
+	switch {
+	case x <= 0:
+		return 1
+	case x < 10:
+		return math.Exp(-0.4527*math.Pow(x, 0.86) + 0.0218)
+	}
+	return math.Sqrt(math.Pi/x) * math.Exp(-x/4) * (1 - 10/(7*x))
+}
+
+// phiInverse finds x with φ(x)=y by bisection, φ is decreasing
+func phiInverse(y float64) float64 {
+	if y >= 1 {
+		return 0
+	}
+	lo, hi := 0.0, 1.0
+	for phi(hi) > y {
+		lo, hi = hi, 2*hi
+	}
+	for i := 0; i < 100 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if phi(mid) > y {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+func (p *Polar) init() {
+	p.once.Do(func() {
+		if p.N < 2 || p.N&(p.N-1) != 0 || len(p.Frozen) != p.N-p.K-p.CRC {
+			panic(fmt.Sprintf("invalid polar code N=%v K=%v CRC=%v with %v frozen bits", p.N, p.K, p.CRC, len(p.Frozen)))
+		}
+		p.frozen = make([]bool, p.N)
+		for _, i := range p.Frozen {
+			p.frozen[i] = true
+		}
+		p.info = make([]int, 0, p.K+p.CRC)
+		for i, f := range p.frozen {
+			if !f {
+				p.info = append(p.info, i)
+			}
+		}
+	})
+}
+
+func (p *Polar) String() string {
+	if p.CRC > 0 {
+		return fmt.Sprintf("Polar(%v,%v)+CRC(%v)", p.N, p.K, p.CRC)
+	}
+	return fmt.Sprintf("Polar(%v,%v)", p.N, p.K)
+}
+
+// CodewordLength is N.
+func (p *Polar) CodewordLength() int {
+	return p.N
+}
+
+// MessageLength is K.
+func (p *Polar) MessageLength() int {
+	return p.K
+}
+
+// transform computes xF^⊗n in place, F^⊗n is its own inverse so it also returns u from x
+func transform(x []int) {
+	for size := 1; size < len(x); size *= 2 {
+		for i := 0; i < len(x); i += 2 * size {
+			for j := i; j < i+size; j++ {
+				x[j] ^= x[j+size]
+			}
+		}
+	}
+}
+
+// Encode places the message followed by its CRC in the info set of u and returns x = uF^⊗n.
+func (p *Polar) Encode(message mat.SparseVector) mat.SparseVector {
+	p.init()
+	if message.Len() != p.K {
+		panic(fmt.Sprintf("message length == %v required but found %v", p.K, message.Len()))
+	}
+
+	bits := make([]int, p.K, p.K+p.CRC)
+	for _, i := range message.NonzeroArray() {
+		bits[i] = 1
+	}
+	bits = append(bits, crc(bits, p.CRC)...)
+
+	u := make([]int, p.N)
+	for i, b := range bits {
+		u[p.info[i]] = b
+	}
+	transform(u)
+	return toVector(u)
+}
+
+// InfoBits returns the info set of u = xF^⊗n for the codeword, the message followed by its CRC.
+func (p *Polar) InfoBits(codeword mat.SparseVector) mat.SparseVector {
+	p.init()
+	if codeword.Len() != p.N {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", p.N, codeword.Len()))
+	}
+
+	u := make([]int, p.N)
+	for _, i := range codeword.NonzeroArray() {
+		u[i] = 1
+	}
+	transform(u)
+
+	result := mat.CSRVec(len(p.info))
+	for i, pos := range p.info {
+		result.Set(i, u[pos])
+	}
+	return result
+}
+
+// Message returns the message of the codeword, the first K bits of InfoBits.
+func (p *Polar) Message(codeword mat.SparseVector) mat.SparseVector {
+	return p.InfoBits(codeword).Slice(0, p.K)
+}
+
+// LinearBlock returns the equivalent linear block code so the polar code can be used with the other decoders.
+// Since u = xF^⊗n each frozen bit u_i=0 is the parity check of column i of F^⊗n, the bits x_r where r has every
+// bit of i set, and each CRC bit is the parity check of the sum of the columns of the info bits it covers.
+func (p *Polar) LinearBlock(ctx context.Context, threads int) (*linearblock.LinearBlock, error) {
+	p.init()
+
+	// the u domain checks, each is the set of u bits that sum to 0
+	checks := make([][]int, 0, p.N-p.K)
+	for _, i := range p.Frozen {
+		checks = append(checks, []int{i})
+	}
+	for j := 0; j < p.CRC; j++ {
+		checks = append(checks, []int{p.info[p.K+j]})
+	}
+	for i := 0; i < p.K && p.CRC > 0; i++ {
+		unit := make([]int, p.K)
+		unit[i] = 1
+		for j, b := range crc(unit, p.CRC) {
+			if b == 1 {
+				checks[len(p.Frozen)+j] = append(checks[len(p.Frozen)+j], p.info[i])
+			}
+		}
+	}
+
+	H := mat.CSRMat(len(checks), p.N)
+	for row, check := range checks {
+		for _, i := range check {
+			for r := 0; r < p.N; r++ {
+				if r&i == i {
+					H.Set(row, r, H.At(row, r)+1)
+				}
+			}
+		}
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+	return result, nil
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package polar
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestNew_Frozen(t *testing.T) {
+	tests := []struct {
+		n, k         int
+		construction Construction
+		designEbN0   float64
+		expected     []int
+	}{
+		// Z=1/2 is Arıkan's BEC(1/2) example
+		{8, 4, Bhattacharyya, math.Ln2, []int{0, 1, 2, 4}},
+		{8, 4, GaussianApproximation, 1, []int{0, 1, 2, 4}},
+		{4, 1, Bhattacharyya, 1, []int{0, 1, 2}},
+		{16, 8, GaussianApproximation, 1, []int{0, 1, 2, 3, 4, 5, 6, 8}},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			code, err := New(test.n, test.k, 0, test.construction, test.designEbN0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !reflect.DeepEqual(code.Frozen, test.expected) {
+				t.Fatalf("expected frozen %v but found %v", test.expected, code.Frozen)
+			}
+		})
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		n, k, crc    int
+		construction Construction
+		designEbN0   float64
+	}{
+		{12, 4, 0, Bhattacharyya, 1},
+		{1, 1, 0, Bhattacharyya, 1},
+		{8, 0, 0, Bhattacharyya, 1},
+		{8, 4, 5, Bhattacharyya, 1},
+		{16, 4, 16, Bhattacharyya, 1},
+		{8, 4, 0, "other", 1},
+		{8, 4, 0, GaussianApproximation, 0},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(test.n, test.k, test.crc, test.construction, test.designEbN0); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestCRC(t *testing.T) {
+	// "123456789" with CRC-16/XMODEM (0x1021, zero init, no reflection) is 0x31C3
+	bits := []int{}
+	for _, c := range []byte("123456789") {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, int(c>>i)&1)
+		}
+	}
+	actual := 0
+	for _, b := range crc(bits, 16) {
+		actual = actual<<1 | b
+	}
+	if actual != 0x31C3 {
+		t.Fatalf("expected 0x31C3 but found %#x", actual)
+	}
+}
+
+func TestPolar_EncodeMessage(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, crcLength := range []int{0, 6, 11} {
+		t.Run(strconv.Itoa(crcLength), func(t *testing.T) {
+			code, err := New(64, 24, crcLength, GaussianApproximation, 2)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			block, err := code.LinearBlock(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if block.MessageLength() != code.K {
+				t.Fatalf("expected message length %v but found %v", code.K, block.MessageLength())
+			}
+
+			for i := 0; i < 20; i++ {
+				message := randomMessage(random, code.K)
+				codeword := code.Encode(message)
+				if !block.Syndrome(codeword).IsZero() {
+					t.Fatalf("expected the codeword %v to satisfy H", codeword)
+				}
+				if actual := code.Message(codeword); !actual.Equals(message) {
+					t.Fatalf("expected %v but found %v", message, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestSC_Noiseless(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	code, err := New(128, 64, 0, Bhattacharyya, 2)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoders := map[string]Decoder{
+		"SC":  &SC{Code: code},
+		"SCL": &SCL{Code: code, ListSize: 4},
+	}
+	for name, decoder := range decoders {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				codeword := code.Encode(randomMessage(random, code.K))
+				llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(codeword), 1e6), 1e6)
+				if actual := decoder.Decode(llr); !actual.Equals(codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestSCL_ListSizeOneIsSC(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	code, err := New(64, 32, 0, GaussianApproximation, 1)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	sc := &SC{Code: code}
+	scl := &SCL{Code: code, ListSize: 1}
+	for i := 0; i < 50; i++ {
+		llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(randomMessage(random, code.K))), 1), 1)
+		expected := sc.Decode(llr)
+		if actual := scl.Decode(llr); !actual.Equals(expected) {
+			t.Fatalf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func TestSCL_CRCAided(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	code, err := New(128, 56, 8, GaussianApproximation, 0.8)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	sc := &SC{Code: code}
+	scl := &SCL{Code: code, ListSize: 8}
+
+	scErrors, sclErrors := 0, 0
+	for i := 0; i < 300; i++ {
+		codeword := code.Encode(randomMessage(random, code.K))
+		llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(codeword), 0.8), 0.8)
+		if !sc.Decode(llr).Equals(codeword) {
+			scErrors++
+		}
+		if !scl.Decode(llr).Equals(codeword) {
+			sclErrors++
+		}
+	}
+	if scErrors == 0 || sclErrors >= scErrors {
+		t.Fatalf("expected CA-SCL to have fewer frame errors than SC but found %v vs %v", sclErrors, scErrors)
+	}
+}
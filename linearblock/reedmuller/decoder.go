@@ -0,0 +1,178 @@
+package reedmuller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// Reed is Reed's majority-logic decoder of RM(r,m) made by New. The coefficients of the degree r monomials
+// are each decided by a majority vote of 2^(m-r) orthogonal check sums, their evaluations are removed
+// and it repeats for degree r-1 down to 0. Any ⌊(2^(m-r)-1)/2⌋ or fewer errors are corrected.
+type Reed struct {
+	Block *linearblock.LinearBlock
+	R, M  int
+}
+
+// Correct returns the codeword decided by majority-logic, it's always a codeword.
+func (d *Reed) Correct(codeword mat.SparseVector) (corrected mat.SparseVector) {
+	if err := validate(d.R, d.M); err != nil {
+		panic(err)
+	}
+	n := 1 << d.M
+	if codeword.Len() != n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", n, codeword.Len()))
+	}
+
+	received := make([]int, n)
+	for _, x := range codeword.NonzeroArray() {
+		received[x] = 1
+	}
+
+	decided := make([]int, n)
+	for degree := d.R; degree >= 0; degree-- {
+		chosen := make([]int, 0)
+		for monomial := 0; monomial < n; monomial++ {
+			if bits.OnesCount(uint(monomial)) != degree {
+				continue
+			}
+
+			// each check sum adds the received bits over a coset of the subspace spanned by
+			// the monomial's variables, only its coefficient survives in every one of them
+			votes := 0
+			for b := 0; b < n; b++ {
+				if b&monomial != 0 {
+					continue
+				}
+				sum := received[b]
+				for a := monomial; a != 0; a = (a - 1) & monomial {
+					sum ^= received[b|a]
+				}
+				votes += sum
+			}
+			if votes > 1<<(d.M-degree)/2 {
+				chosen = append(chosen, monomial)
+			}
+		}
+
+		for _, monomial := range chosen {
+			for x := 0; x < n; x++ {
+				if x&monomial == monomial {
+					received[x] ^= 1
+					decided[x] ^= 1
+				}
+			}
+		}
+	}
+
+	return toVector(decided)
+}
+
+// Decode corrects the hard decisions of the received word, see linearblock.CorrectionDecoder.
+func (d *Reed) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: d.Block, Correct: func(codeword mat.SparseVector) (mat.SparseVector, bool) {
+		return d.Correct(codeword), true
+	}}).Decode(ctx, received)
+}
+
+// Recursive is the recursive soft decision decoder of RM(r,m) made by New, from "Recursive decoding
+// and its performance for low-rate Reed-Muller codes" by Ilya Dumer. It uses the Plotkin construction
+// RM(r,m) = {(u|u+v): u in RM(r,m-1), v in RM(r-1,m-1)}, where u is the first half of the codeword
+// (x_(m-1)=0). The v is decoded first from the LLRs of u+(u+v), then u from both halves given v,
+// stopping at the repetition codes RM(0,·) and the full spaces RM(m,m) which are decoded exactly.
+type Recursive struct {
+	Block *linearblock.LinearBlock
+	R, M  int
+}
+
+// Correct returns the codeword decided for the channel LLRs,
+// where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)). It's always a codeword.
+func (d *Recursive) Correct(channelLLR mat2.Vector) (codeword mat.SparseVector) {
+	if err := validate(d.R, d.M); err != nil {
+		panic(err)
+	}
+	n := 1 << d.M
+	if channelLLR.Len() != n {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", n, channelLLR.Len()))
+	}
+
+	llr := make([]float64, n)
+	for i := range llr {
+		llr[i] = channelLLR.AtVec(i)
+	}
+	return toVector(recursive(llr, d.R, d.M))
+}
+
+// Decode corrects the soft decisions of the received word, see linearblock.CorrectionDecoder.
+func (d *Recursive) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: d.Block, CorrectLLR: func(channelLLR mat2.Vector) (mat.SparseVector, bool) {
+		return d.Correct(channelLLR), true
+	}}).Decode(ctx, received)
+}
+
+func recursive(llr []float64, r, m int) []int {
+	n := len(llr)
+	result := make([]int, n)
+	switch {
+	case r == 0:
+		sum := 0.0
+		for _, l := range llr {
+			sum += l
+		}
+		if sum < 0 {
+			for i := range result {
+				result[i] = 1
+			}
+		}
+		return result
+	case r == m:
+		for i, l := range llr {
+			if l < 0 {
+				result[i] = 1
+			}
+		}
+		return result
+	}
+
+	half := n / 2
+	sum := make([]float64, half)
+	for i := range sum {
+		sum[i] = boxplus(llr[i], llr[half+i])
+	}
+	v := recursive(sum, r-1, m-1)
+
+	for i := range sum {
+		sum[i] = llr[i] + float64(1-2*v[i])*llr[half+i]
+	}
+	u := recursive(sum, r, m-1)
+
+	for i := range u {
+		result[i] = u[i]
+		result[half+i] = u[i] ^ v[i]
+	}
+	return result
+}
+
+// boxplus is the LLR of the sum of two bits, 2·atanh(tanh(a/2)·tanh(b/2)) in a numerically stable form
+func boxplus(a, b float64) float64 {
+	sign := 1.0
+	if (a < 0) != (b < 0) {
+		sign = -1.0
+	}
+	return sign*math.Min(math.Abs(a), math.Abs(b)) + math.Log1p(math.Exp(-math.Abs(a+b))) - math.Log1p(math.Exp(-math.Abs(a-b)))
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
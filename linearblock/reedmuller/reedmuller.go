@@ -0,0 +1,100 @@
+package reedmuller
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MaxM is the largest m supported, the codeword length is 2^m.
+const MaxM = 16
+
+// New creates the systematic Reed-Muller code RM(r,m) for 0<=r<m. Codeword bit x is the evaluation
+// at the point x=(x_0,...,x_(m-1)), bit i of x is x_i, of a boolean polynomial with degree <= r.
+// The code is (2^m, Σ_{i<=r} C(m,i)) with minimum distance 2^(m-r). RM(1,m) is the first-order
+// code whose dual RM(m-2,m) is the extended Hamming code.
+func New(ctx context.Context, r, m int, threads int) (*linearblock.LinearBlock, error) {
+	if err := validate(r, m); err != nil {
+		return nil, err
+	}
+
+	// the dual of RM(r,m) is RM(m-r-1,m) so its generator, the evaluations
+	// of the monomials with degree <= m-r-1, is the H matrix
+	dual := monomials(m-r-1, m)
+	n := 1 << m
+	H := mat.CSRMat(len(dual), n)
+	for row, monomial := range dual {
+		for x := 0; x < n; x++ {
+			if x&monomial == monomial {
+				H.Set(row, x, 1)
+			}
+		}
+	}
+
+	result := linearblock.SystematicLinearBlock(ctx, H, threads)
+	if result == nil {
+		return nil, fmt.Errorf("unable to create generator for H matrix")
+	}
+
+	return result, nil
+}
+
+func validate(r, m int) error {
+	if m < 1 || MaxM < m {
+		return fmt.Errorf("1<=m<=%v is required but found %v", MaxM, m)
+	}
+	if r < 0 || m <= r {
+		return fmt.Errorf("0<=r<%v is required but found %v", m, r)
+	}
+	return nil
+}
+
+// MessageLength returns the number of message bits of RM(r,m), Σ_{i<=r} C(m,i).
+func MessageLength(r, m int) int {
+	k := 0
+	binomial := 1
+	for i := 0; i <= r && i <= m; i++ {
+		k += binomial
+		binomial = binomial * (m - i) / (i + 1)
+	}
+	return k
+}
+
+// MinimumDistance returns the minimum distance of RM(r,m), 2^(m-r).
+func MinimumDistance(r, m int) int {
+	return 1 << (m - r)
+}
+
+// Parameters returns the r and m of the Reed-Muller code with the codeword and message lengths.
+func Parameters(codewordLength, messageLength int) (r, m int, err error) {
+	m = bits.Len(uint(codewordLength)) - 1
+	if m < 1 || codewordLength != 1<<m {
+		return 0, 0, fmt.Errorf("Reed-Muller codes require a codeword length of 2^m but found %v", codewordLength)
+	}
+	for r = 0; r < m; r++ {
+		if MessageLength(r, m) == messageLength {
+			if err = validate(r, m); err != nil {
+				return 0, 0, err
+			}
+			return r, m, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("no Reed-Muller code of length %v has %v message bits", codewordLength, messageLength)
+}
+
+// monomials returns the monomials of degree <= r in m variables as bit masks of their variables,
+// ordered by degree. The monomial is 1 at the point x when x&monomial == monomial.
+func monomials(r, m int) []int {
+	result := make([]int, 0, MessageLength(r, m))
+	for degree := 0; degree <= r; degree++ {
+		for monomial := 0; monomial < 1<<m; monomial++ {
+			if bits.OnesCount(uint(monomial)) == degree {
+				result = append(result, monomial)
+			}
+		}
+	}
+	return result
+}
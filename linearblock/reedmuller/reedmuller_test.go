@@ -0,0 +1,224 @@
+package reedmuller
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		r, m int
+		n, k int
+	}{
+		{0, 3, 8, 1},
+		{1, 3, 8, 4},
+		{1, 4, 16, 5},
+		{2, 4, 16, 11},
+		{1, 5, 32, 6},
+		{2, 5, 32, 16},
+		{3, 5, 32, 26},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := New(context.Background(), test.r, test.m, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if !actual.Validate() {
+				t.Fatalf("expected valid linearblock code")
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.k || MessageLength(test.r, test.m) != test.k {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.n, test.k, actual.CodewordLength(), actual.MessageLength())
+			}
+
+			r, m, err := Parameters(test.n, test.k)
+			if err != nil || r != test.r || m != test.m {
+				t.Fatalf("expected RM(%v,%v) but found RM(%v,%v) %v", test.r, test.m, r, m, err)
+			}
+		})
+	}
+}
+
+func TestNew_MinimumDistance(t *testing.T) {
+	tests := []struct {
+		r, m int
+	}{
+		{0, 3},
+		{1, 3},
+		{1, 4},
+		{2, 4},
+		{1, 5},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := New(context.Background(), test.r, test.m, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+
+			// the minimum distance of a linear code is the smallest weight of a nonzero codeword
+			k := block.MessageLength()
+			minimum := block.CodewordLength()
+			for value := 1; value < 1<<k; value++ {
+				message := mat.CSRVec(k)
+				for i := 0; i < k; i++ {
+					message.Set(i, value>>i&1)
+				}
+				if weight := len(block.Encode(message).NonzeroArray()); weight < minimum {
+					minimum = weight
+				}
+			}
+			if minimum != MinimumDistance(test.r, test.m) {
+				t.Fatalf("expected minimum distance %v but found %v", MinimumDistance(test.r, test.m), minimum)
+			}
+		})
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		r, m int
+	}{
+		{0, 0},
+		{-1, 3},
+		{3, 3},
+		{1, MaxM + 1},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			_, err := New(context.Background(), test.r, test.m, 0)
+			if err == nil {
+				t.Fatalf("expected an error for RM(%v,%v)", test.r, test.m)
+			}
+		})
+	}
+
+	if _, _, err := Parameters(24, 5); err == nil {
+		t.Fatalf("expected an error for a length that isn't a power of two")
+	}
+	if _, _, err := Parameters(16, 6); err == nil {
+		t.Fatalf("expected an error for a message length that isn't a Reed-Muller code")
+	}
+}
+
+func TestReed_Correct(t *testing.T) {
+	tests := []struct {
+		r, m int
+	}{
+		{0, 3},
+		{1, 4},
+		{1, 5},
+		{2, 5},
+		{2, 6},
+	}
+	random := rand.New(rand.NewSource(1))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			block, err := New(context.Background(), test.r, test.m, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			decoder := &Reed{Block: block, R: test.r, M: test.m}
+
+			correctable := (MinimumDistance(test.r, test.m) - 1) / 2
+			for trial := 0; trial < 20; trial++ {
+				codeword := block.Encode(randomMessage(random, block.MessageLength()))
+				received := mat.CSRVecCopy(codeword)
+				for _, p := range random.Perm(block.CodewordLength())[:random.Intn(correctable+1)] {
+					received.Set(p, received.At(p)+1)
+				}
+
+				actual := decoder.Correct(received)
+				if !actual.Equals(codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestReed_Decode(t *testing.T) {
+	block, err := New(context.Background(), 1, 4, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Reed{Block: block, R: 1, M: 4}
+
+	message := randomMessage(rand.New(rand.NewSource(2)), block.MessageLength())
+	received := block.Encode(message)
+	received.Set(3, received.At(3)+1)
+	received.Set(12, received.At(12)+1)
+	received.Set(14, received.At(14)+1)
+
+	actual := decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if !actual.Converged || actual.Stop != messagepassing.Converged || !actual.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v %v", message, actual.Message, actual.Stop)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual = decoder.Decode(ctx, linearblock.Received{Bits: received})
+	if actual.Stop != messagepassing.Canceled {
+		t.Fatalf("expected %v but found %v", messagepassing.Canceled, actual.Stop)
+	}
+}
+
+func TestRecursive_Noiseless(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	for r := 0; r < 5; r++ {
+		block, err := New(context.Background(), r, 5, 0)
+		if err != nil {
+			t.Fatalf("expected no error but found: %v", err)
+		}
+		decoder := &Recursive{Block: block, R: r, M: 5}
+
+		for trial := 0; trial < 10; trial++ {
+			message := randomMessage(random, block.MessageLength())
+			codeword := block.Encode(message)
+			actual := decoder.Decode(context.Background(), linearblock.Received{Bits: codeword})
+			if !actual.Converged || !actual.Message.Equals(message) {
+				t.Fatalf("RM(%v,5) expected message %v but found %v", r, message, actual.Message)
+			}
+		}
+	}
+}
+
+func TestRecursive_SoftBeatsReed(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	block, err := New(context.Background(), 2, 6, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	reed := &Reed{Block: block, R: 2, M: 6}
+	soft := &Recursive{Block: block, R: 2, M: 6}
+
+	reedErrors, softErrors := 0, 0
+	for i := 0; i < 300; i++ {
+		codeword := block.Encode(randomMessage(random, block.MessageLength()))
+		received := linearblock.Received{LLR: benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(codeword), 0.5), 0.5)}
+		if !reed.Decode(context.Background(), received).Codeword.Equals(codeword) {
+			reedErrors++
+		}
+		if !soft.Decode(context.Background(), received).Codeword.Equals(codeword) {
+			softErrors++
+		}
+	}
+	if reedErrors == 0 || softErrors >= reedErrors {
+		t.Fatalf("expected recursive soft decoding to have fewer frame errors than Reed but found %v vs %v", softErrors, reedErrors)
+	}
+}
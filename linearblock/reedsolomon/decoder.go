@@ -0,0 +1,106 @@
+package reedsolomon
+
+import (
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+)
+
+// Decode corrects the errors and erasures of the received codeword, where erasures are the indices of
+// the symbols known to be unreliable (their values are ignored). The syndromes are computed and the
+// erasures removed from them giving the Forney syndromes, Berlekamp-Massey then finds the error locator
+// which together with the erasure locator gives the errata locator Λ(x). A Chien search finds its roots
+// and the Forney algorithm the errata values. Any e errors and f erasures with 2e+f <= n-k are corrected.
+// The corrected codeword is returned along with the number of symbols that changed. When it's
+// uncorrectable the codeword is returned as received and ok is false.
+func (r *ReedSolomon) Decode(received []int, erasures []int) (codeword []int, corrected int, ok bool) {
+	if len(received) != r.n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", r.n, len(received)))
+	}
+	f := r.field
+	parity := r.n - r.k
+	codeword = append([]int{}, received...)
+
+	erased := make(map[int]bool, len(erasures))
+	for _, e := range erasures {
+		if e < 0 || r.n <= e {
+			panic(fmt.Sprintf("erasures must be in [0,%v) but found %v", r.n, e))
+		}
+		erased[e] = true
+	}
+	if len(erased) > parity {
+		return codeword, 0, false
+	}
+
+	syndromes := r.Syndromes(codeword)
+	zero := true
+	for _, s := range syndromes {
+		zero = zero && s == 0
+	}
+	if zero {
+		return codeword, 0, true
+	}
+
+	// the symbol at index j is the coefficient of x^(n-1-j) so its locator is α^(n-1-j)
+	// and the erasure locator is Γ(x) = Π(1 - X_j x) over the erasures
+	gamma := gf2m.Polynomial{1}
+	for j := range erased {
+		gamma = f.PolyMul(gamma, gf2m.Polynomial{1, f.Exp(r.n - 1 - j)})
+	}
+
+	// the Forney syndromes S(x)Γ(x) beyond the erasures only depend on the errors
+	forney := coefficients(f.PolyMul(syndromes, gamma), len(erased), parity)
+	sigma, errors := f.BerlekampMassey(forney)
+	if 2*errors > len(forney) || sigma.Degree() != errors {
+		return codeword, 0, false
+	}
+
+	// the errata locator Λ(x) and evaluator Ω(x) = S(x)Λ(x) mod x^(n-k)
+	locator := f.PolyMul(sigma, gamma)
+	evaluator := gf2m.Polynomial(coefficients(f.PolyMul(syndromes, locator), 0, parity))
+
+	// Chien search: the symbol at j is in error when Λ(α^-(n-1-j)) = 0
+	positions := make([]int, 0, len(locator)-1)
+	for j := 0; j < r.n; j++ {
+		if f.PolyEval(locator, f.Exp(-(r.n-1-j))) == 0 {
+			positions = append(positions, j)
+		}
+	}
+	if len(positions) != len(locator)-1 {
+		return codeword, 0, false
+	}
+
+	derivative := f.PolyDerivative(locator)
+
+	// Forney: Y = X^(1-b) Ω(X^-1)/Λ'(X^-1) with the first root b=1
+	for _, j := range positions {
+		inverse := f.Exp(-(r.n - 1 - j))
+		denominator := f.PolyEval(derivative, inverse)
+		if denominator == 0 {
+			return append([]int{}, received...), 0, false
+		}
+		value := f.Div(f.PolyEval(evaluator, inverse), denominator)
+		if value != 0 {
+			codeword[j] ^= value
+			corrected++
+		}
+	}
+
+	for _, s := range r.Syndromes(codeword) {
+		if s != 0 {
+			return append([]int{}, received...), 0, false
+		}
+	}
+	return codeword, corrected, true
+}
+
+// coefficients returns the coefficients of x^from,...,x^(to-1) of p
+func coefficients(p gf2m.Polynomial, from, to int) []int {
+	result := make([]int, to-from)
+	for i := range result {
+		if from+i < len(p) {
+			result[i] = p[from+i]
+		}
+	}
+	return result
+}
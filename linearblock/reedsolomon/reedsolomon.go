@@ -0,0 +1,130 @@
+package reedsolomon
+
+import (
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock/gf2m"
+)
+
+// ReedSolomon is a systematic Reed-Solomon code over GF(2^m). Unlike the binary codes each symbol
+// is an element of GF(2^m), an int in [0,2^m), and the code corrects any e errors and f erasures
+// as long as 2e+f <= n-k. The generator polynomial g(x) has the roots α^1,...,α^(n-k).
+// The codeword is the coefficients of the code polynomial c(x) starting from x^(n-1) so the
+// message is the first k symbols followed by the n-k parity symbols. A code with n < 2^m-1 is
+// shortened, it's the full length code with the leading message symbols fixed to 0.
+type ReedSolomon struct {
+	n, k      int
+	field     *gf2m.Field
+	generator []int // g(x) from the highest degree, g[0] = 1
+}
+
+// New creates the (n,k) Reed-Solomon code over GF(2^m) where 1<=k<n<=2^m-1.
+func New(m, n, k int) (*ReedSolomon, error) {
+	f, err := gf2m.New(m)
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || f.Order() < n {
+		return nil, fmt.Errorf("reed-solomon codes over GF(2^%v) require 2<=n<=%v but found %v", m, f.Order(), n)
+	}
+	if k < 1 || n <= k {
+		return nil, fmt.Errorf("reed-solomon codes require 1<=k<%v but found %v", n, k)
+	}
+
+	// g(x) = (x-α^1)(x-α^2)...(x-α^(n-k))
+	g := gf2m.Polynomial{1}
+	for i := 1; i <= n-k; i++ {
+		g = f.PolyMul(g, gf2m.Polynomial{f.Exp(i), 1})
+	}
+	generator := make([]int, len(g))
+	for i, c := range g {
+		generator[len(g)-1-i] = c
+	}
+
+	return &ReedSolomon{
+		n:         n,
+		k:         k,
+		field:     f,
+		generator: generator,
+	}, nil
+}
+
+func (r *ReedSolomon) String() string {
+	return fmt.Sprintf("RS(%v,%v) over GF(2^%v)", r.n, r.k, r.field.M())
+}
+
+// SymbolSize is the number of bits m in each symbol.
+func (r *ReedSolomon) SymbolSize() int {
+	return r.field.M()
+}
+
+// CodewordLength is the number of symbols n in a codeword.
+func (r *ReedSolomon) CodewordLength() int {
+	return r.n
+}
+
+// MessageLength is the number of symbols k in a message.
+func (r *ReedSolomon) MessageLength() int {
+	return r.k
+}
+
+// ParitySymbols is the number of parity symbols n-k.
+func (r *ReedSolomon) ParitySymbols() int {
+	return r.n - r.k
+}
+
+// Encode returns the systematic codeword for the message, the message followed by the remainder of m(x)x^(n-k)/g(x).
+func (r *ReedSolomon) Encode(message []int) []int {
+	if len(message) != r.k {
+		panic(fmt.Sprintf("message length == %v required but found %v", r.k, len(message)))
+	}
+
+	for _, s := range message {
+		if s < 0 || r.field.Order() < s {
+			panic(fmt.Sprintf("symbols must be in [0,%v] but found %v", r.field.Order(), s))
+		}
+	}
+	codeword := make([]int, r.n)
+	copy(codeword, message)
+
+	// long division by g(x), what remains in the last n-k symbols is the remainder
+	remainder := make([]int, r.n)
+	copy(remainder, message)
+	for i := 0; i < r.k; i++ {
+		coefficient := remainder[i]
+		if coefficient == 0 {
+			continue
+		}
+		for j := 1; j < len(r.generator); j++ {
+			remainder[i+j] ^= r.field.Mul(r.generator[j], coefficient)
+		}
+	}
+	copy(codeword[r.k:], remainder[r.k:])
+	return codeword
+}
+
+// Message returns the message of a systematic codeword, its first k symbols.
+func (r *ReedSolomon) Message(codeword []int) []int {
+	if len(codeword) != r.n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", r.n, len(codeword)))
+	}
+	return append([]int{}, codeword[:r.k]...)
+}
+
+// Syndromes returns S_i = c(α^i) for i=1,...,n-k, they're all zero when the codeword is valid.
+func (r *ReedSolomon) Syndromes(codeword []int) []int {
+	if len(codeword) != r.n {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", r.n, len(codeword)))
+	}
+
+	syndromes := make([]int, r.n-r.k)
+	for i := range syndromes {
+		x := r.field.Exp(i + 1)
+		s := 0
+		for _, c := range codeword {
+			s = r.field.Mul(s, x) ^ c
+		}
+		syndromes[i] = s
+	}
+	return syndromes
+}
@@ -0,0 +1,194 @@
+package reedsolomon
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func randomSymbols(random *rand.Rand, r *ReedSolomon, length int) []int {
+	symbols := make([]int, length)
+	for i := range symbols {
+		symbols[i] = random.Intn(1 << r.SymbolSize())
+	}
+	return symbols
+}
+
+func isZero(symbols []int) bool {
+	for _, s := range symbols {
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		m, n, k int
+		valid   bool
+	}{
+		{3, 7, 3, true},
+		{8, 255, 223, true},
+		{8, 40, 32, true},
+		{1, 1, 1, false},
+		{4, 16, 11, false},
+		{4, 15, 15, false},
+		{4, 15, 0, false},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := New(test.m, test.n, test.k)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected an error for (%v,%v) over GF(2^%v)", test.n, test.k, test.m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if actual.CodewordLength() != test.n || actual.MessageLength() != test.k || actual.ParitySymbols() != test.n-test.k {
+				t.Fatalf("expected (%v,%v) but found %v", test.n, test.k, actual)
+			}
+		})
+	}
+}
+
+func TestReedSolomon_Encode(t *testing.T) {
+	tests := []struct {
+		m, n, k int
+	}{
+		{3, 7, 3},
+		{4, 15, 11},
+		{8, 255, 223},
+		{8, 40, 32},
+	}
+	random := rand.New(rand.NewSource(1))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			r, err := New(test.m, test.n, test.k)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+
+			message := randomSymbols(random, r, r.MessageLength())
+			codeword := r.Encode(message)
+			if !isZero(r.Syndromes(codeword)) {
+				t.Fatalf("expected zero syndromes for %v", codeword)
+			}
+			if !reflect.DeepEqual(r.Message(codeword), message) {
+				t.Fatalf("expected message %v but found %v", message, r.Message(codeword))
+			}
+		})
+	}
+}
+
+func TestReedSolomon_Decode(t *testing.T) {
+	tests := []struct {
+		m, n, k int
+	}{
+		{3, 7, 3},
+		{4, 15, 9},
+		{8, 255, 223},
+		{8, 40, 32}, // shortened
+	}
+	random := rand.New(rand.NewSource(2))
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			r, err := New(test.m, test.n, test.k)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+
+			for trial := 0; trial < 200; trial++ {
+				codeword := r.Encode(randomSymbols(random, r, r.MessageLength()))
+
+				// any mix with 2e+f <= n-k is correctable
+				f := random.Intn(r.ParitySymbols() + 1)
+				e := random.Intn((r.ParitySymbols()-f)/2 + 1)
+
+				received := append([]int{}, codeword...)
+				positions := random.Perm(r.CodewordLength())
+				for _, j := range positions[:e] {
+					received[j] ^= 1 + random.Intn(1<<r.SymbolSize()-1)
+				}
+				erasures := positions[e : e+f]
+				for _, j := range erasures {
+					received[j] = random.Intn(1 << r.SymbolSize())
+				}
+
+				actual, _, ok := r.Decode(received, erasures)
+				if !ok {
+					t.Fatalf("expected %v errors and %v erasures to be corrected", e, f)
+				}
+				if !reflect.DeepEqual(actual, codeword) {
+					t.Fatalf("expected %v but found %v", codeword, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestReedSolomon_DecodeCorrected(t *testing.T) {
+	r, err := New(4, 15, 11)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	codeword := r.Encode(randomSymbols(rand.New(rand.NewSource(3)), r, r.MessageLength()))
+
+	actual, corrected, ok := r.Decode(codeword, nil)
+	if !ok || corrected != 0 || !reflect.DeepEqual(actual, codeword) {
+		t.Fatalf("expected a clean codeword but found %v %v %v", actual, corrected, ok)
+	}
+
+	received := append([]int{}, codeword...)
+	received[0] ^= 5
+	received[14] ^= 9
+	actual, corrected, ok = r.Decode(received, nil)
+	if !ok || corrected != 2 || !reflect.DeepEqual(actual, codeword) {
+		t.Fatalf("expected 2 symbols corrected but found %v %v %v", actual, corrected, ok)
+	}
+}
+
+func TestReedSolomon_DecodeUncorrectable(t *testing.T) {
+	r, err := New(8, 40, 32)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	// beyond 2e+f <= n-k the decoder either detects it or miscorrects
+	// to another codeword, it never returns a non-codeword as corrected
+	random := rand.New(rand.NewSource(4))
+	detected := 0
+	for trial := 0; trial < 200; trial++ {
+		codeword := r.Encode(randomSymbols(random, r, r.MessageLength()))
+		received := append([]int{}, codeword...)
+		for _, j := range random.Perm(r.CodewordLength())[:5] {
+			received[j] ^= 1 + random.Intn(255)
+		}
+
+		actual, _, ok := r.Decode(received, nil)
+		if !ok {
+			detected++
+			if !reflect.DeepEqual(actual, received) {
+				t.Fatalf("expected the received codeword to be returned when detected")
+			}
+			continue
+		}
+		if !isZero(r.Syndromes(actual)) {
+			t.Fatalf("expected a codeword but found %v", actual)
+		}
+	}
+	if detected == 0 {
+		t.Fatalf("expected some of the uncorrectable errors to be detected")
+	}
+
+	// more erasures than parity symbols can't be corrected
+	codeword := r.Encode(randomSymbols(random, r, r.MessageLength()))
+	codeword[0] ^= 1
+	if _, _, ok := r.Decode(codeword, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}); ok {
+		t.Fatalf("expected too many erasures to be uncorrectable")
+	}
+}
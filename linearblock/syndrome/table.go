@@ -0,0 +1,206 @@
+package syndrome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/linearblock"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// MaxParitySymbols is the largest number of parity symbols (rows of H) a Table
+// will be built for, the table has 2^(n-k) entries.
+const MaxParitySymbols = 24
+
+// Status is the outcome of decoding a single codeword.
+type Status int
+
+const (
+	// Clean means the syndrome was zero, there was nothing to correct.
+	Clean Status = iota
+	// Corrected means the errors were corrected using the syndrome's coset leader.
+	Corrected
+	// Detected means the errors were detected but they're uncorrectable,
+	// the codeword is returned as received.
+	Detected
+)
+
+func (s Status) String() string {
+	switch s {
+	case Clean:
+		return "Clean"
+	case Corrected:
+		return "Corrected"
+	case Detected:
+		return "Detected"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Result is the decoded codeword along with its classification.
+type Result struct {
+	Codeword mat.SparseVector
+	Status   Status
+	Errors   int // the number of bits corrected
+}
+
+// Table is the syndrome lookup decoder. It maps each syndrome to its coset leader, the
+// smallest weight error pattern with that syndrome. The table is built once so it's
+// only practical for small n-k (see MaxParitySymbols).
+type Table struct {
+	block       *linearblock.LinearBlock
+	columns     []uint64 // the syndrome of a single error at each position
+	leaderBit   []int32  // one bit of the coset leader, the rest is the leader of syndrome^columns[bit]
+	weight      []int    // weight of the coset leader, -1 when the syndrome is unreachable
+	unique      []bool   // true when the syndrome has a single coset leader
+	correctable int
+}
+
+// NewTable builds the syndrome table for the linear block.
+func NewTable(block *linearblock.LinearBlock) (*Table, error) {
+	rows, cols := block.H.Dims()
+	if rows > MaxParitySymbols {
+		return nil, fmt.Errorf("syndrome table requires <=%v parity symbols but found %v", MaxParitySymbols, rows)
+	}
+
+	t := &Table{
+		block:     block,
+		columns:   make([]uint64, cols),
+		leaderBit: make([]int32, 1<<rows),
+		weight:    make([]int, 1<<rows),
+		unique:    make([]bool, 1<<rows),
+	}
+	for c := 0; c < cols; c++ {
+		for _, r := range block.H.Column(c).NonzeroArray() {
+			t.columns[c] |= 1 << r
+		}
+	}
+	for s := range t.weight {
+		t.weight[s] = -1
+		t.leaderBit[s] = -1
+	}
+
+	// a breadth first search from the zero syndrome finds the syndromes in order of
+	// their coset leader's weight, adding a single error at a time. Each coset leader of
+	// weight w is reached from w coset leaders of weight w-1, one for each of its bits, so
+	// the number of coset leaders of each syndrome is counted along the way.
+	t.weight[0] = 0
+	t.unique[0] = true
+	current := []uint64{0}
+	leaders := map[uint64]float64{0: 1}
+	patterns := 1.0 // number of error patterns with weight <= w
+	found := 1      // number of syndromes with leader weight <= w
+	unique := true
+	for w := 1; len(current) > 0; w++ {
+		next := make([]uint64, 0)
+		nextLeaders := make(map[uint64]float64)
+		for _, s := range current {
+			for c, column := range t.columns {
+				n := s ^ column
+				if t.weight[n] == -1 {
+					t.weight[n] = w
+					t.leaderBit[n] = int32(c)
+					next = append(next, n)
+				}
+				if t.weight[n] == w {
+					nextLeaders[n] += leaders[s]
+				}
+			}
+		}
+		for _, n := range next {
+			nextLeaders[n] /= float64(w)
+			t.unique[n] = nextLeaders[n] == 1
+		}
+		current, leaders = next, nextLeaders
+
+		// every error pattern of weight <= w is correctable only
+		// when each of them has its own syndrome
+		patterns += binomial(cols, w)
+		found += len(next)
+		if unique && float64(found) == patterns {
+			t.correctable = w
+		} else {
+			unique = false
+		}
+	}
+
+	return t, nil
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 0; i < k; i++ {
+		result = result * float64(n-i) / float64(i+1)
+	}
+	return result
+}
+
+// CorrectableWeight is the largest number of errors that are always corrected, ⌊(d_min-1)/2⌋.
+// Syndromes with heavier coset leaders are classified as Detected.
+func (t *Table) CorrectableWeight() int {
+	return t.correctable
+}
+
+func (t *Table) syndrome(codeword mat.SparseVector) uint64 {
+	s := uint64(0)
+	for _, r := range t.block.Syndrome(codeword).NonzeroArray() {
+		s |= 1 << r
+	}
+	return s
+}
+
+// addLeader adds the coset leader of the syndrome to the codeword
+func (t *Table) addLeader(codeword mat.SparseVector, s uint64) {
+	for s != 0 {
+		c := int(t.leaderBit[s])
+		codeword.Set(c, codeword.At(c)+1)
+		s ^= t.columns[c]
+	}
+}
+
+// Decode looks up the syndrome (LinearBlock.Syndrome) of the codeword. Clean codewords are returned as is,
+// when the coset leader has at most CorrectableWeight errors they're corrected otherwise they're Detected.
+func (t *Table) Decode(codeword mat.SparseVector) Result {
+	s := t.syndrome(codeword)
+
+	result := Result{Codeword: mat.CSRVecCopy(codeword)}
+	switch w := t.weight[s]; {
+	case w == 0:
+		result.Status = Clean
+	case 0 < w && w <= t.correctable:
+		result.Status = Corrected
+		result.Errors = w
+		t.addLeader(result.Codeword, s)
+	default:
+		result.Status = Detected
+	}
+	return result
+}
+
+// DecodeComplete corrects every codeword with its syndrome's coset leader, even when it has more than
+// CorrectableWeight errors, so the result is always a closest codeword. Unique is false when the syndrome
+// has other coset leaders of the same weight, then there's more than one closest codeword and one of them
+// was picked.
+func (t *Table) DecodeComplete(codeword mat.SparseVector) (corrected mat.SparseVector, errors int, unique bool) {
+	s := t.syndrome(codeword)
+
+	corrected = mat.CSRVecCopy(codeword)
+	t.addLeader(corrected, s)
+	return corrected, t.weight[s], t.unique[s]
+}
+
+// Decoder adapts a Table to a linearblock.Decoder.
+type Decoder struct {
+	Table *Table
+}
+
+// Decode looks up the syndrome of the hard decisions of the received word, see linearblock.CorrectionDecoder.
+// Detected codewords are returned as received so they're Stalled.
+func (d *Decoder) Decode(ctx context.Context, received linearblock.Received) linearblock.DecodeResult {
+	return (&linearblock.CorrectionDecoder{Block: d.Table.block, Correct: d.correct}).Decode(ctx, received)
+}
+
+func (d *Decoder) correct(codeword mat.SparseVector) (mat.SparseVector, bool) {
+	result := d.Table.Decode(codeword)
+	return result.Codeword, result.Status != Detected
+}
@@ -0,0 +1,186 @@
+package syndrome
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/linearblock"
+	"github.com/nathanhack/ecc/linearblock/hamming"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestTable_Hamming(t *testing.T) {
+	block, err := hamming.New(context.Background(), 3, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	table, err := NewTable(block)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if table.CorrectableWeight() != 1 {
+		t.Fatalf("expected correctable weight %v but found %v", 1, table.CorrectableWeight())
+	}
+
+	expected := block.Encode(mat.DOKVec(4, 1, 0, 1, 1))
+	actual := table.Decode(expected)
+	if actual.Status != Clean || !actual.Codeword.Equals(expected) {
+		t.Fatalf("expected clean %v but found %v %v", expected, actual.Status, actual.Codeword)
+	}
+
+	for i := 0; i < block.CodewordLength(); i++ {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			codeword := mat.CSRVecCopy(expected)
+			codeword.Set(i, codeword.At(i)+1)
+
+			actual := table.Decode(codeword)
+			if actual.Status != Corrected || actual.Errors != 1 {
+				t.Fatalf("expected %v with 1 error but found %v with %v", Corrected, actual.Status, actual.Errors)
+			}
+			if !actual.Codeword.Equals(expected) {
+				t.Fatalf("expected %v but found %v", expected, actual.Codeword)
+			}
+		})
+	}
+}
+
+func TestTable_SECDED(t *testing.T) {
+	tests := []struct {
+		messageLength  int
+		codewordLength int
+	}{
+		{64, 72},
+		{32, 39},
+	}
+	random := rand.New(rand.NewSource(0))
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.codewordLength), func(t *testing.T) {
+			block, err := hamming.NewSECDED(context.Background(), test.messageLength, 0)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if block.MessageLength() != test.messageLength || block.CodewordLength() != test.codewordLength {
+				t.Fatalf("expected (%v,%v) but found (%v,%v)", test.codewordLength, test.messageLength, block.CodewordLength(), block.MessageLength())
+			}
+
+			table, err := NewTable(block)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			if table.CorrectableWeight() != 1 {
+				t.Fatalf("expected correctable weight %v but found %v", 1, table.CorrectableWeight())
+			}
+
+			expected := block.Encode(randomMessage(random, block.MessageLength()))
+			if actual := table.Decode(expected); actual.Status != Clean {
+				t.Fatalf("expected %v but found %v", Clean, actual.Status)
+			}
+
+			n := block.CodewordLength()
+			for i := 0; i < n; i++ {
+				codeword := mat.CSRVecCopy(expected)
+				codeword.Set(i, codeword.At(i)+1)
+
+				actual := table.Decode(codeword)
+				if actual.Status != Corrected || !actual.Codeword.Equals(expected) {
+					t.Fatalf("expected single error %v to be corrected but found %v", i, actual.Status)
+				}
+
+				for j := i + 1; j < n; j++ {
+					codeword := mat.CSRVecCopy(codeword)
+					codeword.Set(j, codeword.At(j)+1)
+
+					actual := table.Decode(codeword)
+					if actual.Status != Detected || !actual.Codeword.Equals(codeword) {
+						t.Fatalf("expected double error %v,%v to be detected but found %v", i, j, actual.Status)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTable_DecodeComplete(t *testing.T) {
+	// the (8,4) extended Hamming code has a unique coset leader for every single error
+	// but every double error is at distance 2 from two codewords
+	block, err := hamming.NewSECDED(context.Background(), 4, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	table, err := NewTable(block)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+
+	expected := block.Encode(mat.DOKVec(4, 1, 1, 0, 1))
+	if actual, errors, unique := table.DecodeComplete(expected); !actual.Equals(expected) || errors != 0 || !unique {
+		t.Fatalf("expected %v with 0 errors but found %v %v %v", expected, actual, errors, unique)
+	}
+
+	n := block.CodewordLength()
+	for i := 0; i < n; i++ {
+		codeword := mat.CSRVecCopy(expected)
+		codeword.Set(i, codeword.At(i)+1)
+
+		actual, errors, unique := table.DecodeComplete(codeword)
+		if !actual.Equals(expected) || errors != 1 || !unique {
+			t.Fatalf("error at %v: expected %v with 1 error but found %v %v %v", i, expected, actual, errors, unique)
+		}
+
+		for j := i + 1; j < n; j++ {
+			codeword := mat.CSRVecCopy(codeword)
+			codeword.Set(j, codeword.At(j)+1)
+
+			actual, errors, unique := table.DecodeComplete(codeword)
+			if errors != 2 || unique || !block.Syndrome(actual).IsZero() || actual.HammingDistance(codeword) != 2 {
+				t.Fatalf("errors at %v,%v: expected an ambiguous codeword at distance 2 but found %v %v %v", i, j, actual, errors, unique)
+			}
+		}
+	}
+}
+
+func TestNewTable_TooManyParitySymbols(t *testing.T) {
+	H := mat.CSRMat(MaxParitySymbols+1, MaxParitySymbols+2)
+	_, err := NewTable(&linearblock.LinearBlock{H: H})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	block, err := hamming.NewSECDED(context.Background(), 32, 0)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	table, err := NewTable(block)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	decoder := &Decoder{Table: table}
+
+	message := randomMessage(rand.New(rand.NewSource(1)), block.MessageLength())
+	codeword := block.Encode(message)
+
+	received := mat.CSRVecCopy(codeword)
+	received.Set(7, received.At(7)+1)
+	actual := decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if !actual.Converged || !actual.Message.Equals(message) {
+		t.Fatalf("expected message %v but found %v", message, actual.Message)
+	}
+
+	received.Set(20, received.At(20)+1)
+	actual = decoder.Decode(context.Background(), linearblock.Received{Bits: received})
+	if actual.Converged || actual.SyndromeWeight == 0 {
+		t.Fatalf("expected a detected error but found syndrome weight %v", actual.SyndromeWeight)
+	}
+}
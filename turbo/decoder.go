@@ -0,0 +1,112 @@
+package turbo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathanhack/ecc/convolutional"
+	"github.com/nathanhack/ecc/linearblock/messagepassing"
+	mat "github.com/nathanhack/sparsemat"
+	mat2 "gonum.org/v1/gonum/mat"
+)
+
+// Decoder is the iterative turbo decoder. Each iteration runs the BCJR decoder of the first RSC code then the
+// second, each using the other's extrinsic LLRs as its a priori LLRs. With MaxLog the BCJR decoders use
+// max-log-MAP instead of log-MAP. It stops early once both decoders decide the same message.
+type Decoder struct {
+	Code   *Turbo
+	MaxLog bool
+}
+
+// Decode runs at most maxIter iterations for the channel LLRs, where LLR_n = ln(P(c_n=0|y_n)/P(c_n=1|y_n)).
+// The codeword of the decided message is returned along with the number of iterations used and whether
+// both decoders agreed.
+func (d *Decoder) Decode(channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, converged bool) {
+	codeword, iterations, stop := d.DecodeContext(context.Background(), channelLLR, maxIter)
+	return codeword, iterations, stop == messagepassing.Converged
+}
+
+// DecodeContext is Decode that also stops when the ctx is canceled, returning why it stopped.
+func (d *Decoder) DecodeContext(ctx context.Context, channelLLR mat2.Vector, maxIter int) (codeword mat.SparseVector, iterations int, stop messagepassing.StopReason) {
+	code := d.Code
+	if code == nil {
+		panic("turbo code must be set before decoding")
+	}
+	code.init()
+	if channelLLR.Len() != code.CodewordLength() {
+		panic(fmt.Sprintf("channel LLR length == %v required but found %v", code.CodewordLength(), channelLLR.Len()))
+	}
+
+	// the LLRs of each RSC code's output stream, systematic and parity of each step followed by the tail
+	length := len(code.Interleaver)
+	systematic := make([]float64, length)
+	streams := [2][]float64{}
+	for e := range streams {
+		streams[e] = make([]float64, 2*(length+code.ConstraintLength-1))
+	}
+	for i, p := range code.Interleaver {
+		systematic[i] = channelLLR.AtVec(i)
+		streams[0][2*i] = channelLLR.AtVec(i)
+		streams[1][2*i] = channelLLR.AtVec(p)
+	}
+	n := length
+	for i := 0; i < length; i++ {
+		for e := range streams {
+			if code.kept[e][i] {
+				streams[e][2*i+1] = channelLLR.AtVec(n)
+				n++
+			}
+		}
+	}
+	for e := range streams {
+		for i := 2 * length; i < len(streams[e]); i++ {
+			streams[e][i] = channelLLR.AtVec(n)
+			n++
+		}
+	}
+
+	bcjr := &convolutional.BCJR{Code: code.constituent, MaxLog: d.MaxLog}
+	aPriori := [2][]float64{make([]float64, length), make([]float64, length)}
+	decision := make([]int, length)
+	for i, l := range systematic {
+		if l < 0 {
+			decision[i] = 1
+		}
+	}
+
+	for iterations = 1; iterations <= maxIter; iterations++ {
+		if ctx.Err() != nil {
+			return code.Encode(toVector(decision)), iterations - 1, messagepassing.Canceled
+		}
+
+		first := bcjr.APosteriori(streams[0], aPriori[0])
+		for i, p := range code.Interleaver {
+			aPriori[1][i] = first[p] - systematic[p] - aPriori[0][p]
+		}
+		second := bcjr.APosteriori(streams[1], aPriori[1])
+
+		agree := true
+		for i, p := range code.Interleaver {
+			aPriori[0][p] = second[i] - systematic[p] - aPriori[1][i]
+			decision[p] = 0
+			if second[i] < 0 {
+				decision[p] = 1
+			}
+			agree = agree && (first[p] < 0) == (second[i] < 0)
+		}
+		if agree {
+			return code.Encode(toVector(decision)), iterations, messagepassing.Converged
+		}
+	}
+	return code.Encode(toVector(decision)), maxIter, messagepassing.MaxIterations
+}
+
+func toVector(bits []int) mat.SparseVector {
+	result := mat.CSRVec(len(bits))
+	for i, b := range bits {
+		if b == 1 {
+			result.Set(i, 1)
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package turbo
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// MaxSRandomAttempts is the number of times SRandomInterleaver starts over before giving up.
+const MaxSRandomAttempts = 1000
+
+// RandomInterleaver returns a uniformly random permutation of the length positions.
+func RandomInterleaver(length int) []int {
+	return rand.Perm(length)
+}
+
+// SRandomInterleaver returns a random permutation where any two positions within spread of each other are moved
+// more than spread apart, from "Turbo Codes for PCS Applications" by D. Divsalar and F. Pollara. The positions are
+// drawn one at a time from the unused ones. When none fit, an unused one is swapped with an earlier position it fits
+// at, if the position it displaces fits next, otherwise it starts over. Spreads up to about √(length/2) succeed.
+func SRandomInterleaver(length, spread int) ([]int, error) {
+	if length < 1 || spread < 0 {
+		return nil, fmt.Errorf("S-random interleavers require length>=1 and spread>=0 but found %v and %v", length, spread)
+	}
+
+attempts:
+	for attempt := 0; attempt < MaxSRandomAttempts; attempt++ {
+		remaining := rand.Perm(length)
+		result := make([]int, 0, length)
+		for len(result) < length {
+			found := -1
+			for r, candidate := range remaining {
+				if fits(result, len(result), candidate, spread) {
+					found = r
+					break
+				}
+			}
+			if found < 0 && !swap(result, remaining, spread) {
+				continue attempts
+			}
+			if found < 0 {
+				found = 0
+			}
+			result = append(result, remaining[found])
+			remaining = append(remaining[:found], remaining[found+1:]...)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unable to find an S-random interleaver of length %v with spread %v after %v attempts", length, spread, MaxSRandomAttempts)
+}
+
+// fits returns true when the value is more than spread from the values placed within spread of the position
+func fits(result []int, position, value, spread int) bool {
+	for j := position - spread; j <= position+spread; j++ {
+		if j < 0 || j == position || len(result) <= j {
+			continue
+		}
+		if abs(result[j]-value) <= spread {
+			return false
+		}
+	}
+	return true
+}
+
+// swap looks for an unused value that fits at an earlier position whose value fits next, swapping them
+// so the displaced value is the first remaining one
+func swap(result, remaining []int, spread int) bool {
+	next := len(result)
+	for r, candidate := range remaining {
+		for _, k := range rand.Perm(next) {
+			if !fits(result, k, candidate, spread) {
+				continue
+			}
+			displaced := result[k]
+			result[k] = candidate
+			if fits(result, next, displaced, spread) {
+				remaining[r] = remaining[0]
+				remaining[0] = displaced
+				return true
+			}
+			result[k] = displaced
+		}
+	}
+	return false
+}
+
+// QPPInterleaver returns the quadratic permutation polynomial interleaver π(i) = (f1·i+f2·i²) mod length
+// from "Interleavers for Turbo Codes Using Permutation Polynomials over Integer Rings" by J. Sun and
+// O. Y. Takeshita, as used by LTE. Not every f1, f2 gives a permutation, those return an error.
+func QPPInterleaver(length, f1, f2 int) ([]int, error) {
+	if length < 1 {
+		return nil, fmt.Errorf("QPP interleavers require length>=1 but found %v", length)
+	}
+	n := int64(length)
+	result := make([]int, length)
+	for i := range result {
+		x := int64(i)
+		result[i] = int(((int64(f1)%n*x)%n + (int64(f2)%n*x%n*x)%n) % n)
+		if result[i] < 0 {
+			result[i] += length
+		}
+	}
+	if err := validateInterleaver(result); err != nil {
+		return nil, fmt.Errorf("f1=%v f2=%v is not a QPP interleaver for length %v: %v", f1, f2, length, err)
+	}
+	return result, nil
+}
+
+func validateInterleaver(interleaver []int) error {
+	seen := make([]bool, len(interleaver))
+	for _, p := range interleaver {
+		if p < 0 || len(interleaver) <= p {
+			return fmt.Errorf("position %v is out of range", p)
+		}
+		if seen[p] {
+			return fmt.Errorf("position %v is used more than once", p)
+		}
+		seen[p] = true
+	}
+	return nil
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,154 @@
+package turbo
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nathanhack/ecc/convolutional"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+// Turbo is the parallel concatenated convolutional code from "Near Shannon Limit Error-Correcting Coding and
+// Decoding: Turbo-Codes" by Berrou, Glavieux and Thitimajshima. Two identical zero-tail recursive systematic
+// convolutional (RSC) encoders see the message and the interleaved message, the codeword is the message followed
+// by the parity bits that survive rate matching (p1_0,p2_0,p1_1,p2_1...) and then the tails of both encoders.
+// Without rate matching the rate is about 1/3.
+type Turbo struct {
+	ConstraintLength int   // K of both RSC codes
+	Feedback         int   // the feedback polynomial of both RSC codes in octal, see convolutional.Convolutional
+	Parity           int   // the parity generator polynomial of both RSC codes in octal
+	Interleaver      []int // the second encoder's input i is message bit Interleaver[i], its length is the message length
+	N                int   // the codeword length after rate matching, 0 sends every parity bit
+
+	once        sync.Once
+	constituent *convolutional.Convolutional
+	kept        [2][]bool // kept[e][t] is true when parity bit t of encoder e is sent
+	sent        int       // the number of parity bits sent
+}
+
+// New creates the turbo code with the RSC code (1, parity/feedback) of the constraint length. The message length
+// is the interleaver's length and n is the codeword length after rate matching, 0 for no rate matching.
+func New(constraintLength, feedback, parity int, interleaver []int, n int) (*Turbo, error) {
+	t := &Turbo{
+		ConstraintLength: constraintLength,
+		Feedback:         feedback,
+		Parity:           parity,
+		Interleaver:      interleaver,
+		N:                n,
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Turbo) validate() error {
+	length := len(t.Interleaver)
+	if _, err := convolutional.NewRecursive(t.ConstraintLength, []int{t.Feedback, t.Parity}, t.Feedback, convolutional.ZeroTail, nil, length); err != nil {
+		return err
+	}
+	if err := validateInterleaver(t.Interleaver); err != nil {
+		return fmt.Errorf("invalid interleaver: %v", err)
+	}
+	smallest, largest := length+4*(t.ConstraintLength-1), 3*length+4*(t.ConstraintLength-1)
+	if t.N != 0 && (t.N < smallest || largest < t.N) {
+		return fmt.Errorf("the codeword length must be 0 or in [%v,%v] but found %v", smallest, largest, t.N)
+	}
+	return nil
+}
+
+func (t *Turbo) init() {
+	t.once.Do(func() {
+		if err := t.validate(); err != nil {
+			panic(err)
+		}
+		length := len(t.Interleaver)
+		t.constituent = &convolutional.Convolutional{
+			ConstraintLength: t.ConstraintLength,
+			Generators:       []int{t.Feedback, t.Parity},
+			Feedback:         t.Feedback,
+			Termination:      convolutional.ZeroTail,
+			Length:           length,
+		}
+
+		// the parity bits are split between the encoders and spread evenly over the message,
+		// the second encoder is offset by half a step so they alternate
+		parity := 2 * length
+		if t.N != 0 {
+			parity = t.N - length - 4*(t.ConstraintLength-1)
+		}
+		share := [2]int{(parity + 1) / 2, parity / 2}
+		for e := range t.kept {
+			t.kept[e] = make([]bool, length)
+			offset := e * length
+			for i := range t.kept[e] {
+				t.kept[e][i] = (2*(i+1)*share[e]+offset)/(2*length) > (2*i*share[e]+offset)/(2*length)
+			}
+		}
+		t.sent = parity
+	})
+}
+
+func (t *Turbo) String() string {
+	return fmt.Sprintf("Turbo(%v,%v,K=%v,1+%o/%o)", t.CodewordLength(), t.MessageLength(), t.ConstraintLength, t.Parity, t.Feedback)
+}
+
+// MessageLength is the length of the interleaver.
+func (t *Turbo) MessageLength() int {
+	return len(t.Interleaver)
+}
+
+// CodewordLength is N, or the unpunctured length when it's 0.
+func (t *Turbo) CodewordLength() int {
+	t.init()
+	return len(t.Interleaver) + t.sent + 4*(t.ConstraintLength-1)
+}
+
+// CodeRate is the message length over the codeword length.
+func (t *Turbo) CodeRate() float64 {
+	return float64(t.MessageLength()) / float64(t.CodewordLength())
+}
+
+// Encode returns the codeword of the message.
+func (t *Turbo) Encode(message mat.SparseVector) (codeword mat.SparseVector) {
+	t.init()
+	length := len(t.Interleaver)
+	if message.Len() != length {
+		panic(fmt.Sprintf("message length == %v required but found %v", length, message.Len()))
+	}
+
+	interleaved := mat.CSRVec(length)
+	for i, p := range t.Interleaver {
+		interleaved.Set(i, message.At(p))
+	}
+	streams := [2]mat.SparseVector{t.constituent.Encode(message), t.constituent.Encode(interleaved)}
+
+	codeword = mat.CSRVec(t.CodewordLength())
+	for i := 0; i < length; i++ {
+		codeword.Set(i, message.At(i))
+	}
+	n := length
+	for i := 0; i < length; i++ {
+		for e, stream := range streams {
+			if t.kept[e][i] {
+				codeword.Set(n, stream.At(2*i+1))
+				n++
+			}
+		}
+	}
+	for _, stream := range streams {
+		for i := 2 * length; i < stream.Len(); i++ {
+			codeword.Set(n, stream.At(i))
+			n++
+		}
+	}
+	return codeword
+}
+
+// Message returns the message of the codeword, its first MessageLength bits since it's systematic.
+func (t *Turbo) Message(codeword mat.SparseVector) mat.SparseVector {
+	if codeword.Len() != t.CodewordLength() {
+		panic(fmt.Sprintf("codeword length == %v required but found %v", t.CodewordLength(), codeword.Len()))
+	}
+	return codeword.Slice(0, len(t.Interleaver))
+}
@@ -0,0 +1,154 @@
+package turbo
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nathanhack/ecc/benchmarking"
+	mat "github.com/nathanhack/sparsemat"
+)
+
+func randomMessage(random *rand.Rand, length int) mat.SparseVector {
+	message := mat.CSRVec(length)
+	for i := 0; i < length; i++ {
+		message.Set(i, random.Intn(2))
+	}
+	return message
+}
+
+func TestQPPInterleaver(t *testing.T) {
+	// LTE K=40 uses f1=3 and f2=10
+	actual, err := QPPInterleaver(40, 3, 10)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	for i, expected := range []int{0, 13, 6, 19, 12, 25, 18, 31} {
+		if actual[i] != expected {
+			t.Fatalf("expected π(%v)=%v but found %v", i, expected, actual[i])
+		}
+	}
+
+	if _, err := QPPInterleaver(40, 3, 11); err == nil {
+		t.Fatalf("expected an error for f2=11")
+	}
+}
+
+func TestSRandomInterleaver(t *testing.T) {
+	length, spread := 256, 10
+	actual, err := SRandomInterleaver(length, spread)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	if err := validateInterleaver(actual); err != nil {
+		t.Fatalf("expected a permutation but found: %v", err)
+	}
+	for i := range actual {
+		for j := i + 1; j < len(actual) && j <= i+spread; j++ {
+			if abs(actual[i]-actual[j]) <= spread {
+				t.Fatalf("expected positions %v and %v to be more than %v apart but found %v and %v", i, j, spread, actual[i], actual[j])
+			}
+		}
+	}
+
+	if _, err := SRandomInterleaver(16, 16); err == nil {
+		t.Fatalf("expected an error for a spread as large as the length")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	interleaver := rand.Perm(40)
+	tests := []struct {
+		constraintLength, feedback, parity int
+		interleaver                        []int
+		n                                  int
+	}{
+		{4, 03, 015, interleaver, 0},
+		{4, 013, 025, interleaver, 0},
+		{4, 013, 015, []int{0, 1, 1}, 0},
+		{4, 013, 015, []int{}, 0},
+		{4, 013, 015, interleaver, 51},
+		{4, 013, 015, interleaver, 133},
+	}
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if _, err := New(test.constraintLength, test.feedback, test.parity, test.interleaver, test.n); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestTurbo_Encode(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	interleaver, _ := QPPInterleaver(40, 3, 10)
+	for _, n := range []int{0, 52, 80, 100, 132} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			code, err := New(4, 013, 015, interleaver, n)
+			if err != nil {
+				t.Fatalf("expected no error but found: %v", err)
+			}
+			expected := n
+			if n == 0 {
+				expected = 3*40 + 12
+			}
+			if code.CodewordLength() != expected {
+				t.Fatalf("expected codeword length %v but found %v", expected, code.CodewordLength())
+			}
+
+			message := randomMessage(random, code.MessageLength())
+			codeword := code.Encode(message)
+			if codeword.Len() != expected {
+				t.Fatalf("expected codeword length %v but found %v", expected, codeword.Len())
+			}
+			if actual := code.Message(codeword); !actual.Equals(message) {
+				t.Fatalf("expected %v but found %v", message, actual)
+			}
+		})
+	}
+}
+
+func TestDecoder_Noiseless(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	interleaver, _ := QPPInterleaver(40, 3, 10)
+	code, _ := New(4, 013, 015, interleaver, 80)
+	for _, maxLog := range []bool{false, true} {
+		decoder := &Decoder{Code: code, MaxLog: maxLog}
+		for j := 0; j < 10; j++ {
+			codeword := code.Encode(randomMessage(random, code.MessageLength()))
+			actual, iterations, converged := decoder.Decode(benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(codeword), 1e6), 1e6), 8)
+			if !converged || iterations != 1 || !actual.Equals(codeword) {
+				t.Fatalf("expected %v in one iteration but found %v after %v", codeword, actual, iterations)
+			}
+		}
+	}
+}
+
+func TestDecoder_Iterations(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	// LTE K=256 uses f1=15 and f2=32, a fixed interleaver keeps the error counts reproducible
+	interleaver, err := QPPInterleaver(256, 15, 32)
+	if err != nil {
+		t.Fatalf("expected no error but found: %v", err)
+	}
+	code, _ := New(4, 013, 015, interleaver, 0)
+
+	for _, maxLog := range []bool{false, true} {
+		t.Run(strconv.FormatBool(maxLog), func(t *testing.T) {
+			decoder := &Decoder{Code: code, MaxLog: maxLog}
+			once, iterated := 0, 0
+			for j := 0; j < 40; j++ {
+				message := randomMessage(random, code.MessageLength())
+				llr := benchmarking.BPSKToLLR(benchmarking.RandomNoiseBPSKFrom(random, benchmarking.BitsToBPSK(code.Encode(message)), 0.55), 0.55)
+
+				codeword, _, _ := decoder.Decode(llr, 1)
+				once += code.Message(codeword).HammingDistance(message)
+				codeword, _, _ = decoder.Decode(llr, 10)
+				iterated += code.Message(codeword).HammingDistance(message)
+			}
+			if once == 0 || iterated*10 > once {
+				t.Fatalf("expected iterating to remove most of the %v bit errors but found %v", once, iterated)
+			}
+		})
+	}
+}